@@ -2,12 +2,21 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"slices"
 	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/weaby/adr-buddy/internal/cli"
+	"github.com/weaby/adr-buddy/internal/export"
+	"github.com/weaby/adr-buddy/internal/importer"
+	"github.com/weaby/adr-buddy/internal/template"
+	"github.com/weaby/adr-buddy/internal/watch"
 )
 
 var rootCmd = &cobra.Command{
@@ -22,6 +31,11 @@ var initCmd = &cobra.Command{
 	Short: "Initialize adr-buddy in the current directory",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		skillFlag, _ := cmd.Flags().GetString("claude-skill")
+		templateFlag, _ := cmd.Flags().GetString("template")
+
+		if _, ok := template.LookupPreset(templateFlag); templateFlag != "" && !ok {
+			return fmt.Errorf("invalid --template value: %s (must be %s)", templateFlag, strings.Join(template.PresetNames(), ", "))
+		}
 
 		var skillLocation cli.SkillLocation
 		switch skillFlag {
@@ -37,7 +51,7 @@ var initCmd = &cobra.Command{
 			return fmt.Errorf("invalid --claude-skill value: %s (must be project, user, or skip)", skillFlag)
 		}
 
-		return cli.InitWithSkill(".", skillLocation)
+		return cli.InitWithOptions(".", cli.InitOptions{Skill: skillLocation, Template: templateFlag})
 	},
 }
 
@@ -71,12 +85,40 @@ var syncCmd = &cobra.Command{
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
 		watch, _ := cmd.Flags().GetBool("watch")
 		format, _ := cmd.Flags().GetString("format")
+		prune, _ := cmd.Flags().GetBool("prune")
+		check, _ := cmd.Flags().GetBool("check")
 
 		if watch {
-			return fmt.Errorf("watch mode not yet implemented")
+			if dryRun || check || format != "text" {
+				return fmt.Errorf("--watch cannot be combined with --dry-run, --check or --format=%s", format)
+			}
+			poll, _ := cmd.Flags().GetBool("poll")
+			interval, _ := cmd.Flags().GetDuration("poll-interval")
+			debounce, _ := cmd.Flags().GetDuration("debounce")
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return cli.Watch(ctx, ".", cli.WatchOptions{
+				Prune:    prune,
+				Poll:     poll,
+				Interval: interval,
+				Debounce: debounce,
+			}, os.Stdout)
 		}
 
-		return cli.SyncWithFormat(".", dryRun, format, os.Stdout)
+		err := cli.SyncWithOptions(".", cli.SyncOptions{
+			DryRun: dryRun,
+			Format: format,
+			Prune:  prune,
+			Check:  check,
+		}, os.Stdout)
+		if errors.Is(err, cli.ErrOutOfDate) {
+			// The summary has already been printed; only the exit code matters
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+		}
+		return err
 	},
 }
 
@@ -90,35 +132,273 @@ var checkCmd = &cobra.Command{
 	},
 }
 
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Work with ADR templates",
+}
+
+var templateValidateCmd = &cobra.Command{
+	Use:   "validate [template...]",
+	Short: "Check templates for errors (all configured templates by default)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cli.TemplateValidate(".", args, os.Stdout)
+	},
+}
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Generate the ADR index (README.md) in the output directory",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		return cli.Index(".", cli.IndexOptions{DryRun: dryRun}, os.Stdout)
+	},
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Print how decisions relate as a Mermaid, Graphviz DOT or JSON graph",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		return cli.Graph(".", format, os.Stdout)
+	},
+}
+
+var siteCmd = &cobra.Command{
+	Use:   "site",
+	Short: "Generate a static HTML site of all decisions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := cmd.Flags().GetString("out")
+		return cli.Site(".", cli.SiteOptions{Out: out}, os.Stdout)
+	},
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Browse decisions live from the annotations in a local web UI",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr, _ := cmd.Flags().GetString("addr")
+		poll, _ := cmd.Flags().GetBool("poll")
+		interval, _ := cmd.Flags().GetDuration("poll-interval")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return cli.Serve(ctx, ".", cli.ServeOptions{Addr: addr, Poll: poll, Interval: interval}, os.Stdout)
+	},
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export all decisions with every field as JSON, NDJSON, CSV or YAML",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if printSchema, _ := cmd.Flags().GetBool("schema"); printSchema {
+			_, err := os.Stdout.Write(export.Schema())
+			return err
+		}
+		format, _ := cmd.Flags().GetString("format")
+		return cli.Export(".", format, os.Stdout)
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <dir>",
+	Short: "Import ADRs written with adr-tools, Log4brains or MADR",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		suggest, _ := cmd.Flags().GetBool("suggest")
+
+		if !slices.Contains(importer.Formats, from) {
+			return fmt.Errorf("invalid --from value: %s (must be %s)", from, strings.Join(importer.Formats, ", "))
+		}
+
+		return cli.Import(".", cli.ImportOptions{From: from, Dir: args[0], DryRun: dryRun, Suggest: suggest}, os.Stdout)
+	},
+}
+
+var newCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Add an annotation for a new decision with the next free ID",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		line, _ := cmd.Flags().GetInt("line")
+		symbol, _ := cmd.Flags().GetString("symbol")
+		category, _ := cmd.Flags().GetString("category")
+		edit, _ := cmd.Flags().GetBool("edit")
+
+		return cli.New(".", cli.NewOptions{
+			Name:     args[0],
+			Category: category,
+			File:     file,
+			Line:     line,
+			Symbol:   symbol,
+			Edit:     edit,
+		}, os.Stdout)
+	},
+}
+
+var nextIDCmd = &cobra.Command{
+	Use:   "next-id",
+	Short: "Print the next free ADR ID",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		category, _ := cmd.Flags().GetString("category")
+		return cli.NextID(".", category, os.Stdout)
+	},
+}
+
+var pullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Write hand edits of ADR files back into the annotations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		return cli.Pull(".", cli.PullOptions{DryRun: dryRun}, os.Stdout)
+	},
+}
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <old-id> <new-id>",
+	Short: "Rename an ADR ID in annotations, relations, ADR files and links",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		return cli.Rename(".", args[0], args[1], cli.RenameOptions{DryRun: dryRun}, os.Stdout)
+	},
+}
+
+var renumberCmd = &cobra.Command{
+	Use:   "renumber",
+	Short: "Pad every sequential ADR ID to the same number of digits",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pad, _ := cmd.Flags().GetInt("pad")
+		if !cmd.Flags().Changed("pad") {
+			pad = -1
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		return cli.Renumber(".", cli.RenumberOptions{Pad: pad, DryRun: dryRun}, os.Stdout)
+	},
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status <id> <status>",
+	Short: "Change the status of a decision in all of its annotations",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		return cli.SetStatus(".", args[0], args[1], cli.StatusOptions{DryRun: dryRun}, os.Stdout)
+	},
+}
+
+var supersedeCmd = &cobra.Command{
+	Use:   "supersede <id> --by <id>",
+	Short: "Mark a decision as superseded by another one",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		by, _ := cmd.Flags().GetString("by")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		return cli.Supersede(".", args[0], by, cli.StatusOptions{DryRun: dryRun}, os.Stdout)
+	},
+}
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all discovered ADRs",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		category, _ := cmd.Flags().GetString("category")
-		return cli.ListCommand(".", category, nil)
+		statuses, _ := cmd.Flags().GetStringSlice("status")
+		return cli.ListWithOptions(".", cli.ListOptions{Category: category, Statuses: statuses}, nil)
 	},
 }
 
 func init() {
 	initCmd.Flags().String("claude-skill", "", "Install Claude Code skill: project, user, or skip")
+	initCmd.Flags().String("template", "", "Template preset: "+strings.Join(template.PresetNames(), ", "))
 
 	syncCmd.Flags().Bool("dry-run", false, "Show what would change without writing files")
 	syncCmd.Flags().Bool("watch", false, "Continuous mode (re-run on file changes)")
 	syncCmd.Flags().String("format", "text", "Output format: text or json")
+	syncCmd.Flags().Bool("prune", false, "Delete orphaned ADR files regardless of orphan_policy")
+	syncCmd.Flags().Bool("check", false, "Exit with code 2 if ADR files are out of date, without writing")
+	syncCmd.Flags().Bool("poll", false, "Watch mode: poll for changes instead of using native file notifications")
+	syncCmd.Flags().Duration("poll-interval", watch.DefaultInterval, "Watch mode: how often to poll for changes")
+	syncCmd.Flags().Duration("debounce", cli.DefaultDebounce, "Watch mode: wait this long after the last change before syncing")
 
 	checkCmd.Flags().Bool("strict", false, "Treat warnings as errors")
 	checkCmd.Flags().String("format", "text", "Output format: text or json")
 
 	listCmd.Flags().String("category", "", "Filter by category")
+	listCmd.Flags().StringSlice("status", nil, "Filter by status; comma-separated for several")
+
+	indexCmd.Flags().Bool("dry-run", false, "Show what would change without writing files")
+
+	graphCmd.Flags().String("format", "mermaid", "Output format: mermaid, dot or json")
+
+	siteCmd.Flags().String("out", cli.DefaultSiteDir, "Directory to write the site to")
+
+	exportCmd.Flags().String("format", export.FormatJSON, "Output format: "+strings.Join(export.Formats, ", "))
+	exportCmd.Flags().Bool("schema", false, "Print the JSON schema of the export instead")
+
+	importCmd.Flags().String("from", "", "Format of the ADRs: "+strings.Join(importer.Formats, ", "))
+	importCmd.MarkFlagRequired("from")
+	importCmd.Flags().Bool("dry-run", false, "Show what would be imported without writing files")
+	importCmd.Flags().Bool("suggest", false, "Suggest places in the code to annotate each imported decision")
+
+	newCmd.Flags().String("file", "", "File to add the annotation to; prints it if not set")
+	newCmd.Flags().Int("line", 0, "Line to add the annotation above")
+	newCmd.Flags().String("symbol", "", "Function, type or class to add the annotation above")
+	newCmd.Flags().String("category", "", "Category of the decision")
+	newCmd.Flags().Bool("edit", false, "Open the file in $EDITOR at the annotation")
+
+	nextIDCmd.Flags().String("category", "", "Category of the decision, for per-category prefixes")
+
+	pullCmd.Flags().Bool("dry-run", false, "Show the changes to the source files without writing them")
+
+	renameCmd.Flags().Bool("dry-run", false, "Show the changes without writing them")
+
+	renumberCmd.Flags().Int("pad", 0, "Digits to pad numbers to; defaults to ids.pad in the config")
+	renumberCmd.Flags().Bool("dry-run", false, "Show the changes without writing them")
+
+	statusCmd.Flags().Bool("dry-run", false, "Show the changes to the source files without writing them")
+
+	supersedeCmd.Flags().String("by", "", "ID of the decision that supersedes it")
+	supersedeCmd.MarkFlagRequired("by")
+	supersedeCmd.Flags().Bool("dry-run", false, "Show the changes to the source files without writing them")
+
+	serveCmd.Flags().String("addr", cli.DefaultServeAddr, "Address to listen on")
+	serveCmd.Flags().Bool("poll", false, "Poll for changes instead of using native file notifications")
+	serveCmd.Flags().Duration("poll-interval", watch.DefaultInterval, "How often to poll for changes")
 
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(indexCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(siteCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(newCmd)
+	rootCmd.AddCommand(nextIDCmd)
+	rootCmd.AddCommand(renameCmd)
+	rootCmd.AddCommand(renumberCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(supersedeCmd)
+
+	templateCmd.AddCommand(templateValidateCmd)
+	rootCmd.AddCommand(templateCmd)
 }
 
+// exitOutOfDate is the exit code for sync --check when ADR files would change
+const exitOutOfDate = 2
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
+		if errors.Is(err, cli.ErrOutOfDate) {
+			os.Exit(exitOutOfDate)
+		}
 		os.Exit(1)
 	}
 }
@@ -27,3 +27,32 @@ func TestExitCodes(t *testing.T) {
 	// Test failed check - exit 1
 	// (would need invalid annotations to test)
 }
+
+func TestSyncCheckExitCode(t *testing.T) {
+	cmd := exec.Command("go", "build", "-o", "/tmp/adr-buddy-check-test", ".")
+	err := cmd.Run()
+	assert.NoError(t, err)
+	defer os.Remove("/tmp/adr-buddy-check-test")
+
+	tmpDir := t.TempDir()
+	source := "// @decision.id: adr-1\n// @decision.name: Test\nconst x = 1;\n"
+	assert.NoError(t, os.WriteFile(tmpDir+"/test.js", []byte(source), 0644))
+
+	// Out of date - exit 2
+	cmd = exec.Command("/tmp/adr-buddy-check-test", "sync", "--check")
+	cmd.Dir = tmpDir
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if assert.ErrorAs(t, err, &exitErr) {
+		assert.Equal(t, 2, exitErr.ExitCode())
+	}
+
+	// Up to date after sync - exit 0
+	cmd = exec.Command("/tmp/adr-buddy-check-test", "sync")
+	cmd.Dir = tmpDir
+	assert.NoError(t, cmd.Run())
+
+	cmd = exec.Command("/tmp/adr-buddy-check-test", "sync", "--check")
+	cmd.Dir = tmpDir
+	assert.NoError(t, cmd.Run())
+}
@@ -6,8 +6,10 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/ids"
 	"github.com/weaby/adr-buddy/internal/model"
 	"github.com/weaby/adr-buddy/internal/parser"
 )
@@ -48,6 +50,8 @@ func CheckWithFormat(rootDir string, strict bool, format string, output io.Write
 		},
 	}
 
+	statuses := cfg.Lifecycle()
+
 	// Validate each annotation
 	for _, ann := range allAnnotations {
 		if err := ann.Validate(); err != nil {
@@ -64,7 +68,7 @@ func CheckWithFormat(rootDir string, strict bool, format string, output io.Write
 		}
 
 		// Validate status
-		if err := parser.ValidateStatus(ann.Status, strict); err != nil {
+		if err := parser.ValidateStatusIn(ann.Status, statuses.Statuses, strict); err != nil {
 			severity := "warning"
 			if strict {
 				severity = "error"
@@ -90,8 +94,9 @@ func CheckWithFormat(rootDir string, strict bool, format string, output io.Write
 	}
 
 	// Aggregate to check for conflicts
+	var adrs []*model.ADR
 	if len(allAnnotations) > 0 {
-		_, err := model.Aggregate(allAnnotations)
+		adrs, err = model.Aggregate(allAnnotations)
 		if err != nil {
 			result.Errors = append(result.Errors, model.ValidationError{
 				File:     "",
@@ -104,6 +109,64 @@ func CheckWithFormat(rootDir string, strict bool, format string, output io.Write
 		}
 	}
 
+	// A status may only change as the lifecycle allows, judged against the
+	// status of the previously generated file
+	if len(statuses.Transitions) > 0 && len(adrs) > 0 {
+		existing, err := scanExistingADRs(absPath(rootDir, cfg.OutputDir), cfg.ArchiveDir)
+		if err != nil {
+			return err
+		}
+		previous := make(map[string]string, len(existing))
+		for _, e := range existing {
+			previous[e.ID] = e.Status
+		}
+		sort.Slice(adrs, func(i, j int) bool { return model.IDLess(adrs[i].ID, adrs[j].ID) })
+		for _, adr := range adrs {
+			from, ok := previous[adr.ID]
+			if !ok {
+				continue
+			}
+			if err := statuses.CheckTransition(from, adr.Status); err != nil {
+				result.Errors = append(result.Errors, model.ValidationError{
+					File:     adr.Locations[0].File,
+					Line:     adr.Locations[0].Line,
+					Type:     "invalid_transition",
+					Message:  fmt.Sprintf("%s: %v", adr.ID, err),
+					Severity: "error",
+				})
+				result.Summary.ErrorCount++
+			}
+		}
+	}
+
+	// Check IDs against the ID scheme, if one is configured. Duplicates are
+	// always errors.
+	var problems []ids.Problem
+	if cfg.IDs != nil {
+		decisions, err := decisionIDs(rootDir, cfg, allAnnotations)
+		if err != nil {
+			return err
+		}
+		problems = cfg.IDs.Audit(decisions)
+	}
+	for _, problem := range problems {
+		validationErr := model.ValidationError{
+			File:     problem.Location.File,
+			Line:     problem.Location.Line,
+			Type:     problem.Type,
+			Message:  problem.Message,
+			Severity: "warning",
+		}
+		if strict || problem.Type == "id_duplicate" {
+			validationErr.Severity = "error"
+			result.Errors = append(result.Errors, validationErr)
+			result.Summary.ErrorCount++
+		} else {
+			result.Warnings = append(result.Warnings, validationErr)
+			result.Summary.WarningCount++
+		}
+	}
+
 	// Set final status
 	if result.Summary.ErrorCount > 0 {
 		result.Status = model.StatusFail
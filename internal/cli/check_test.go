@@ -340,3 +340,116 @@ const x = 1;`
 	assert.Equal(t, "pass", result.Status)
 	assert.Equal(t, 1, result.Summary.TotalAnnotations)
 }
+
+func TestCheck_IDScheme(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".adr-buddy"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".adr-buddy", "config.yml"), []byte("ids:\n  scheme: sequential\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte(`package main
+
+// @decision.id: adr-001
+// @decision.name: One
+func a() {}
+
+// @decision.id: adr-4
+// @decision.name: Four
+func b() {}
+`), 0644))
+	// adr-001 again, in another category
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "b.go"), []byte(`package main
+
+// @decision.id: adr-001
+// @decision.name: One
+// @decision.category: db
+func c() {}
+`), 0644))
+
+	var output bytes.Buffer
+	err := CheckWithFormat(tmpDir, false, "json", &output)
+	assert.Error(t, err)
+
+	var result model.CheckResult
+	require.NoError(t, json.Unmarshal(output.Bytes(), &result))
+	require.Len(t, result.Errors, 2)
+	assert.Equal(t, "aggregation_error", result.Errors[0].Type)
+	assert.Equal(t, model.ValidationError{File: "b.go", Line: 3, Type: "id_duplicate", Message: `ID "adr-001" is used in more than one category: a.go:3, b.go:3`, Severity: "error"}, result.Errors[1])
+
+	require.Len(t, result.Warnings, 2)
+	assert.Equal(t, model.ValidationError{File: "a.go", Line: 7, Type: "id_scheme", Message: `ID "adr-4" doesn't match the sequential scheme: expected adr-NNN`, Severity: "warning"}, result.Warnings[0])
+	assert.Equal(t, "gap in adr-NNN numbering: adr-002, adr-003 missing", result.Warnings[1].Message)
+}
+
+func TestCheck_IDSchemeCategoryMove(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".adr-buddy"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".adr-buddy", "config.yml"), []byte("ids:\n  scheme: sequential\n"), 0644))
+	source := func(category string) []byte {
+		return []byte("package main\n\n// @decision.id: adr-001\n// @decision.name: One\n// @decision.category: " + category + "\nfunc a() {}\n")
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a.go"), source("backend"), 0644))
+
+	var output bytes.Buffer
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &output))
+	require.FileExists(t, filepath.Join(tmpDir, "decisions", "backend", "adr-001.md"))
+
+	// Moving the decision to another category isn't a duplicate before sync
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a.go"), source("infra"), 0644))
+	output.Reset()
+	require.NoError(t, CheckWithFormat(tmpDir, true, "json", &output))
+
+	var result model.CheckResult
+	require.NoError(t, json.Unmarshal(output.Bytes(), &result))
+	assert.Empty(t, result.Errors)
+}
+
+func TestCheck_StatusTransitions(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".adr-buddy"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".adr-buddy", "config.yml"), []byte(`statuses:
+  values: [draft, proposed, in-review, accepted, rejected, retired]
+  transitions:
+    draft: [proposed]
+    proposed: [in-review, rejected]
+    in-review: [accepted, rejected]
+    accepted: [retired]
+`), 0644))
+	source := func(first, second string) string {
+		return `package main
+
+// @decision.id: adr-001
+// @decision.name: One
+// @decision.status: ` + first + `
+func a() {}
+
+// @decision.id: adr-002
+// @decision.name: Two
+// @decision.status: ` + second + `
+func b() {}
+`
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte(source("rejected", "in-review")), 0644))
+
+	var output bytes.Buffer
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &output))
+
+	// Allowed changes and custom statuses pass
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte(source("rejected", "accepted")), 0644))
+	output.Reset()
+	assert.NoError(t, CheckWithFormat(tmpDir, true, "text", &output))
+
+	// A rejected decision can't come back
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte(source("accepted", "accepted")), 0644))
+	output.Reset()
+	assert.Error(t, CheckWithFormat(tmpDir, false, "json", &output))
+
+	var result model.CheckResult
+	require.NoError(t, json.Unmarshal(output.Bytes(), &result))
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, model.ValidationError{
+		File:     "a.go",
+		Line:     3,
+		Type:     "invalid_transition",
+		Message:  "adr-001: can't change status from rejected to accepted: rejected is final, record a new decision instead",
+		Severity: "error",
+	}, result.Errors[0])
+}
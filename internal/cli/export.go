@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/export"
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+// Export writes every annotated decision with all its fields in the given
+// format: json, ndjson, csv or yaml
+func Export(rootDir, format string, output io.Writer) error {
+	if !slices.Contains(export.Formats, format) {
+		return fmt.Errorf("invalid format %q: must be one of: %s", format, strings.Join(export.Formats, ", "))
+	}
+
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	annotations, err := scanAnnotations(rootDir, cfg)
+	if err != nil {
+		return err
+	}
+
+	adrs, err := model.Aggregate(annotations)
+	if err != nil {
+		return fmt.Errorf("aggregation failed: %w", err)
+	}
+	if err := loadDates(rootDir, cfg, adrs); err != nil {
+		return err
+	}
+
+	return export.NewDocument(adrs).Write(output, format)
+}
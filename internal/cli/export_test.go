@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weaby/adr-buddy/internal/export"
+)
+
+func TestExport(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(`// @decision.id: adr-1
+// @decision.name: Use Go
+// @decision.owner: backend
+package main
+`), 0644))
+
+	var output bytes.Buffer
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &output))
+
+	output.Reset()
+	require.NoError(t, Export(tmpDir, "json", &output))
+
+	var doc export.Document
+	require.NoError(t, json.Unmarshal(output.Bytes(), &doc))
+	require.Len(t, doc.ADRs, 1)
+	adr := doc.ADRs[0]
+	assert.Equal(t, "adr-1", adr.ID)
+	assert.Equal(t, []string{"backend"}, adr.CustomFields["owner"])
+	assert.Equal(t, []export.Location{{File: "app.go", Line: 1}}, adr.Locations)
+	assert.NotEmpty(t, adr.Date, "date of the synced ADR file")
+
+	assert.ErrorContains(t, Export(tmpDir, "xml", &output), `invalid format "xml"`)
+}
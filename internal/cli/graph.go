@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/graph"
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+// Graph writes the graph of how the annotated decisions relate in the given
+// format: mermaid, dot or json
+func Graph(rootDir, format string, output io.Writer) error {
+	if _, err := (&graph.Graph{}).Format(format); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	annotations, err := scanAnnotations(rootDir, cfg)
+	if err != nil {
+		return err
+	}
+
+	adrs, err := model.Aggregate(annotations)
+	if err != nil {
+		return fmt.Errorf("aggregation failed: %w", err)
+	}
+
+	g := graph.Build(adrs)
+	g.Lifecycle = cfg.Lifecycle()
+	out, err := g.Format(format)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(output, out)
+	return err
+}
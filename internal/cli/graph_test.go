@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(`// @decision.id: adr-2
+// @decision.name: Event bus
+// @decision.status: accepted
+// @decision.supersedes: adr-1
+package main
+`), 0644))
+
+	var output bytes.Buffer
+	require.NoError(t, Graph(tmpDir, "dot", &output))
+	assert.Contains(t, output.String(), `"adr-2" -> "adr-1" [label="supersedes"];`)
+
+	output.Reset()
+	require.NoError(t, Graph(tmpDir, "mermaid", &output))
+	assert.Contains(t, output.String(), "adr_2 -->|supersedes| adr_1\n")
+
+	assert.ErrorContains(t, Graph(tmpDir, "svg", &output), `invalid format "svg"`)
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/ids"
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+// NextID prints the next free ID for a category under the configured ID
+// scheme
+func NextID(rootDir, category string, output io.Writer) error {
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	id, err := nextID(rootDir, cfg, category)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(output, id)
+	return nil
+}
+
+// nextID returns the next free ID for a category
+func nextID(rootDir string, cfg *config.Config, category string) (string, error) {
+	annotations, err := scanAnnotations(rootDir, cfg)
+	if err != nil {
+		return "", err
+	}
+	decisions, err := decisionIDs(rootDir, cfg, annotations)
+	if err != nil {
+		return "", err
+	}
+	used := make([]string, 0, len(decisions))
+	for _, d := range decisions {
+		used = append(used, d.ID)
+	}
+	scheme := cfg.IDScheme()
+	if cfg.IDs == nil {
+		// Without a configured scheme, new IDs are padded like those in use
+		scheme.Pad = scheme.InferPad(category, used)
+	}
+	return scheme.Next(category, used, time.Now())
+}
+
+// decisionIDs returns the IDs in use by annotations and by ADR files,
+// archived ones included, so new IDs never reuse them. ADR files are
+// located relative to rootDir, with their category taken from their
+// directory. Unarchived ADR files of annotated IDs are left out, since
+// their annotations are what sets the category: the file may just not be
+// synced yet.
+func decisionIDs(rootDir string, cfg *config.Config, annotations []*model.Annotation) ([]ids.Decision, error) {
+	var decisions []ids.Decision
+	annotated := make(map[string]bool)
+	for _, ann := range annotations {
+		if ann.ID != "" {
+			decisions = append(decisions, ids.Decision{ID: ann.ID, Category: ann.Category, Location: ann.Location})
+			annotated[ann.ID] = true
+		}
+	}
+
+	outputDir := absPath(rootDir, cfg.OutputDir)
+	archiveDir := filepath.Join(outputDir, cfg.ArchiveDir)
+	for _, dir := range []string{outputDir, archiveDir} {
+		existing, err := scanExistingADRs(dir, cfg.ArchiveDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range existing {
+			if annotated[e.ID] && dir != archiveDir {
+				continue
+			}
+			relPath, _ := filepath.Rel(absPath(rootDir, "."), e.Path)
+			category, _ := filepath.Rel(dir, filepath.Dir(e.Path))
+			if category == "." {
+				category = ""
+			}
+			decisions = append(decisions, ids.Decision{
+				ID:       e.ID,
+				Category: filepath.ToSlash(category),
+				Location: model.SourceLocation{File: relPath},
+				Archived: dir == archiveDir,
+			})
+		}
+	}
+	return decisions, nil
+}
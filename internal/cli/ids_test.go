@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/weaby/adr-buddy/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextID(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".adr-buddy"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".adr-buddy", "config.yml"), []byte(`ids:
+  pad: 4
+  categories:
+    infrastructure: infra-
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte(`package main
+
+// @decision.id: adr-0002
+// @decision.name: Two
+func a() {}
+
+// @decision.id: infra-0001
+// @decision.name: Queue
+// @decision.category: infrastructure
+func b() {}
+`), 0644))
+	// Archived IDs aren't reused
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "decisions", "archive"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "decisions", "archive", "adr-0005.md"), []byte("# adr-0005: Old\n"), 0644))
+
+	var output bytes.Buffer
+	require.NoError(t, NextID(tmpDir, "", &output))
+	assert.Equal(t, "adr-0006\n", output.String())
+
+	output.Reset()
+	require.NoError(t, NextID(tmpDir, "infrastructure/queues", &output))
+	assert.Equal(t, "infra-0002\n", output.String())
+}
+
+func TestNextID_Unpadded(t *testing.T) {
+	// Without ids configured, new IDs are padded like the ones in use
+	tmpDir := t.TempDir()
+	var source strings.Builder
+	source.WriteString("package main\n")
+	for i := 1; i <= 4; i++ {
+		fmt.Fprintf(&source, "\n// @decision.id: adr-%d\n// @decision.name: Decision %d\nfunc f%d() {}\n", i, i, i)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a.go"), []byte(source.String()), 0644))
+
+	var output bytes.Buffer
+	require.NoError(t, NextID(tmpDir, "", &output))
+	assert.Equal(t, "adr-5\n", output.String())
+
+	// An ID of that shape passes check
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "b.go"), []byte("package main\n\n// @decision.id: adr-5\n// @decision.name: Five\nfunc g() {}\n"), 0644))
+	output.Reset()
+	require.NoError(t, CheckWithFormat(tmpDir, true, "json", &output))
+	var result model.CheckResult
+	require.NoError(t, json.Unmarshal(output.Bytes(), &result))
+	assert.Empty(t, result.Warnings)
+	assert.Empty(t, result.Errors)
+}
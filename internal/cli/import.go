@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/importer"
+	"github.com/weaby/adr-buddy/internal/parser"
+	"github.com/weaby/adr-buddy/internal/template"
+	"github.com/weaby/adr-buddy/internal/txn"
+)
+
+// suggestionsPerADR is how many annotation points are suggested per decision
+const suggestionsPerADR = 3
+
+// maxSuggestFileSize skips large files, which are rarely hand-written code
+const maxSuggestFileSize = 1 << 20
+
+// ImportOptions controls the behavior of the import command
+type ImportOptions struct {
+	From    string // Format of the ADRs: adr-tools, log4brains or madr
+	Dir     string // Directory holding the ADRs, relative to rootDir
+	DryRun  bool   // Report what would be imported without writing files
+	Suggest bool   // List places in the code to annotate each decision
+}
+
+// Import converts ADRs written with another tool into ADR files in the
+// output directory, rendered with the configured templates. ADRs whose
+// file already exists or whose ID is already in use are skipped.
+func Import(rootDir string, opts ImportOptions, output io.Writer) error {
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	rootDir = absPath(rootDir, ".")
+	dir := absPath(rootDir, opts.Dir)
+	decisions, err := importer.Parse(dir, opts.From, importer.Options{IDs: cfg.IDScheme()})
+	if err != nil {
+		return err
+	}
+	if len(decisions) == 0 {
+		fmt.Fprintf(output, "No %s ADRs found in %s\n", opts.From, opts.Dir)
+		return nil
+	}
+
+	templates, err := loadTemplates(rootDir, cfg)
+	if err != nil {
+		return err
+	}
+
+	// An ID already used by an annotation or an ADR file in any category
+	// would be merged with or orphaned by the imported decision on sync
+	annotations, err := scanAnnotations(rootDir, cfg)
+	if err != nil {
+		return err
+	}
+	inUse, err := decisionIDs(rootDir, cfg, annotations)
+	if err != nil {
+		return err
+	}
+	usedBy := make(map[string]string)
+	for _, d := range inUse {
+		if _, ok := usedBy[d.ID]; !ok {
+			usedBy[d.ID] = d.Location.File
+			if d.Location.Line > 0 {
+				usedBy[d.ID] = d.Location.String()
+			}
+		}
+	}
+
+	outputDir := absPath(rootDir, cfg.OutputDir)
+	tx := txn.New()
+	imported := 0
+	for _, d := range decisions {
+		if d.ADR.Date == "" {
+			d.ADR.Date = time.Now().Format("2006-01-02")
+		}
+
+		path := d.ADR.OutputPath(outputDir)
+		relPath, _ := filepath.Rel(rootDir, path)
+		source := filepath.Join(opts.Dir, d.Source)
+
+		if _, err := os.Stat(path); err == nil {
+			fmt.Fprintf(output, "Skipped: %s (already exists)\n", relPath)
+			continue
+		}
+		if where, ok := usedBy[d.ADR.ID]; ok {
+			fmt.Fprintf(output, "Skipped: %s (%s already used by %s)\n", relPath, d.ADR.ID, where)
+			continue
+		}
+
+		content, err := template.RenderWithOptions(d.ADR, templates.forADR(d.ADR), templates.opts)
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", source, err)
+		}
+		imported++
+
+		if opts.DryRun {
+			fmt.Fprintf(output, "[DRY RUN] Would import: %s (from %s)\n", relPath, source)
+			continue
+		}
+		tx.Write(path, []byte(content), 0644)
+		fmt.Fprintf(output, "Imported: %s (from %s)\n", relPath, source)
+	}
+
+	if !opts.DryRun {
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to write imported ADRs: %w", err)
+		}
+	}
+	fmt.Fprintf(output, "\n✓ %d of %d %s ADRs imported\n", imported, len(decisions), opts.From)
+
+	if !opts.Suggest {
+		return nil
+	}
+
+	files, err := suggestableFiles(rootDir, cfg, []string{dir, outputDir})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(output, "\nSuggested annotation points:")
+	for _, d := range decisions {
+		fmt.Fprintf(output, "  %s %s\n", d.ADR.ID, d.ADR.Name)
+		suggestions := importer.Suggest(d.ADR, files, suggestionsPerADR)
+		if len(suggestions) == 0 {
+			fmt.Fprintln(output, "    (no matching code found)")
+		}
+		for _, s := range suggestions {
+			fmt.Fprintf(output, "    %s:%d (%s)\n", s.File, s.Line, strings.Join(s.Matches, ", "))
+		}
+	}
+	fmt.Fprintln(output, "\nAnnotate the code with the ADR's @decision.id and @decision.name to link it; sync keeps the imported text.")
+	return nil
+}
+
+// suggestableFiles reads the text files below the scan paths that could
+// hold annotations, keyed by path relative to rootDir. Markdown files and
+// the directories in skip are left out.
+func suggestableFiles(rootDir string, cfg *config.Config, skip []string) (map[string]string, error) {
+	files := make(map[string]string)
+	for _, scanPath := range cfg.ScanPaths {
+		root := absPath(rootDir, scanPath)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(root, path)
+			if d.IsDir() {
+				for _, dir := range skip {
+					if path == dir {
+						return filepath.SkipDir
+					}
+				}
+				// Directory patterns like "**/vendor/**" match what's inside
+				if rel != "." && parser.ShouldExclude(filepath.ToSlash(rel)+"/x", cfg.Exclude) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if filepath.Ext(path) == ".md" || parser.ShouldExclude(filepath.ToSlash(rel), cfg.Exclude) {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil || info.Size() > maxSuggestFileSize {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil || bytes.IndexByte(data, 0) >= 0 {
+				return nil
+			}
+			key, _ := filepath.Rel(rootDir, path)
+			files[filepath.ToSlash(key)] = string(data)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", scanPath, err)
+		}
+	}
+	return files, nil
+}
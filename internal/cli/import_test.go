@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// adrToolsFixture is a decision log written with adr-tools
+var adrToolsFixture = map[string]string{
+	"doc/adr/0001-use-postgresql.md": `# 1. Use PostgreSQL
+
+Date: 2018-02-21
+
+## Status
+
+Accepted
+
+## Context
+
+We need a database.
+`,
+}
+
+func TestImport(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, adrToolsFixture)
+	writeFiles(t, tmpDir, map[string]string{"db/conn.go": "package db\n\n// Open connects to PostgreSQL\nfunc Open() {}\n"})
+
+	var output bytes.Buffer
+	require.NoError(t, Import(tmpDir, ImportOptions{From: "adr-tools", Dir: "doc/adr", Suggest: true}, &output))
+
+	out := output.String()
+	assert.Contains(t, out, "Imported: "+filepath.Join("decisions", "adr-001.md")+" (from "+filepath.Join("doc", "adr", "0001-use-postgresql.md")+")")
+	assert.Contains(t, out, "✓ 1 of 1 adr-tools ADRs imported")
+	assert.Contains(t, out, "  adr-001 Use PostgreSQL\n    db/conn.go:3 (postgresql)\n")
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "decisions", "adr-001.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# adr-001: Use PostgreSQL")
+	assert.Contains(t, string(content), "**Date:** 2018-02-21")
+	assert.Contains(t, string(content), "We need a database.")
+
+	// Annotating the code keeps the imported text
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "db", "conn.go"), []byte(`package db
+
+// @decision.id: adr-001
+// @decision.name: Use PostgreSQL
+// @decision.status: accepted
+func Open() {}
+`), 0644))
+	output.Reset()
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &output))
+	content, err = os.ReadFile(filepath.Join(tmpDir, "decisions", "adr-001.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "We need a database.")
+	assert.Contains(t, string(content), "db/conn.go:3")
+
+	// Existing files are never overwritten
+	output.Reset()
+	require.NoError(t, Import(tmpDir, ImportOptions{From: "adr-tools", Dir: "doc/adr"}, &output))
+	assert.Contains(t, output.String(), "Skipped: "+filepath.Join("decisions", "adr-001.md")+" (already exists)")
+	assert.Contains(t, output.String(), "✓ 0 of 1 adr-tools ADRs imported")
+}
+
+func TestImport_IDScheme(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, adrToolsFixture)
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".adr-buddy"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".adr-buddy", "config.yml"), []byte("ids:\n  prefix: ADR-\n  pad: 4\n"), 0644))
+
+	var output bytes.Buffer
+	require.NoError(t, Import(tmpDir, ImportOptions{From: "adr-tools", Dir: "doc/adr"}, &output))
+	assert.Contains(t, output.String(), "Imported: "+filepath.Join("decisions", "ADR-0001.md"))
+	assert.FileExists(t, filepath.Join(tmpDir, "decisions", "ADR-0001.md"))
+}
+
+func TestImport_IDInUse(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, adrToolsFixture)
+	writeFiles(t, tmpDir, map[string]string{
+		"decisions/backend/adr-001.md": "# adr-001: Use Go\n\n**Status:** accepted\n",
+	})
+
+	var output bytes.Buffer
+	require.NoError(t, Import(tmpDir, ImportOptions{From: "adr-tools", Dir: "doc/adr"}, &output))
+	assert.Contains(t, output.String(), "Skipped: "+filepath.Join("decisions", "adr-001.md")+" (adr-001 already used by "+filepath.Join("decisions", "backend", "adr-001.md")+")")
+	assert.Contains(t, output.String(), "✓ 0 of 1 adr-tools ADRs imported")
+	assert.NoFileExists(t, filepath.Join(tmpDir, "decisions", "adr-001.md"))
+}
+
+func TestImport_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, adrToolsFixture)
+
+	var output bytes.Buffer
+	require.NoError(t, Import(tmpDir, ImportOptions{From: "adr-tools", Dir: "doc/adr", DryRun: true}, &output))
+	assert.Contains(t, output.String(), "[DRY RUN] Would import: "+filepath.Join("decisions", "adr-001.md"))
+	assert.NoDirExists(t, filepath.Join(tmpDir, "decisions"))
+}
@@ -0,0 +1,334 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/diff"
+	"github.com/weaby/adr-buddy/internal/graph"
+	"github.com/weaby/adr-buddy/internal/model"
+	"github.com/weaby/adr-buddy/internal/template"
+	"github.com/weaby/adr-buddy/internal/txn"
+)
+
+// indexFileName is the name of generated index files
+const indexFileName = "README.md"
+
+// indexMarker starts every generated index. Index files without it were
+// written by hand and are never overwritten or removed.
+const indexMarker = "<!-- Generated by adr-buddy; changes are overwritten. Edit the ADRs or the index template instead. -->\n\n"
+
+// indexChange is a generated index file that must be written or removed
+type indexChange struct {
+	Path    string // Absolute path of the index file
+	Action  string // "create", "update" or "delete"
+	Old     string // Current content, empty for "create"
+	Content string // New content, empty for "delete"
+}
+
+// indexADRs returns the ADR files an index lists, keyed by absolute path:
+// the generated ADR files in the output directory, outside the archive
+func indexADRs(existing []existingADR) map[string]string {
+	files := make(map[string]string, len(existing))
+	for _, e := range existing {
+		files[e.Path] = e.Content
+	}
+	return files
+}
+
+// buildIndexes returns the index for the output directory and one for every
+// category directory, keyed by the directory they are written to
+func buildIndexes(outputDir string, files map[string]string) map[string]*template.Index {
+	indexes := map[string]*template.Index{outputDir: {}}
+
+	for path, content := range files {
+		parsed := template.ParseExistingADR(content)
+		category := ""
+		if rel, err := filepath.Rel(outputDir, filepath.Dir(path)); err == nil && rel != "." {
+			category = filepath.ToSlash(rel)
+		}
+
+		entry := template.IndexEntry{
+			ID:       strings.TrimSuffix(filepath.Base(path), ".md"),
+			Name:     parsed.Frontmatter["Name"],
+			Status:   parsed.Frontmatter["Status"],
+			Category: category,
+			Date:     parsed.Frontmatter["Date"],
+		}
+
+		// Listed in the top-level index and the index of every enclosing category
+		dirs := []string{outputDir}
+		if category != "" {
+			parts := strings.Split(category, "/")
+			for i := range parts {
+				dirs = append(dirs, filepath.Join(outputDir, filepath.FromSlash(strings.Join(parts[:i+1], "/"))))
+			}
+		}
+		for _, dir := range dirs {
+			index, ok := indexes[dir]
+			if !ok {
+				rel, _ := filepath.Rel(outputDir, dir)
+				index = &template.Index{Category: filepath.ToSlash(rel)}
+				indexes[dir] = index
+			}
+			e := entry
+			e.Link = relLink(dir, path)
+			index.Entries = append(index.Entries, e)
+		}
+	}
+
+	for dir, index := range indexes {
+		sort.Slice(index.Entries, func(i, j int) bool {
+			return model.IDLess(index.Entries[i].ID, index.Entries[j].ID)
+		})
+
+		byCategory := make(map[string]*template.IndexGroup)
+		var categories []string
+		for _, e := range index.Entries {
+			group, ok := byCategory[e.Category]
+			if !ok {
+				group = &template.IndexGroup{Category: e.Category}
+				if e.Category != "" && e.Category != index.Category {
+					group.Link = relLink(dir, filepath.Join(outputDir, filepath.FromSlash(e.Category), indexFileName))
+				}
+				byCategory[e.Category] = group
+				categories = append(categories, e.Category)
+			}
+			group.Entries = append(group.Entries, e)
+		}
+		sort.Strings(categories)
+		for _, category := range categories {
+			index.Groups = append(index.Groups, *byCategory[category])
+		}
+	}
+
+	return indexes
+}
+
+// relLink returns a markdown link to target from a file in dir
+func relLink(dir, target string) string {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil {
+		return filepath.ToSlash(target)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// indexChanges renders the indexes for files and compares them with the
+// index files on disk. Generated index files for categories that no longer
+// have ADRs are removed; hand-written ones are reported in skipped. The
+// graph embedded in the top-level index, if enabled, is built from adrs.
+func indexChanges(rootDir, outputDir string, cfg *config.Config, files map[string]string, adrs []*model.ADR, opts template.Options) (changes []indexChange, skipped []string, err error) {
+	tmplStr := template.DefaultIndexTemplate()
+	if cfg.Index.Template != "" {
+		path := cfg.Index.Template
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(rootDir, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read index template: %w", err)
+		}
+		tmplStr = string(data)
+	}
+
+	indexes := buildIndexes(outputDir, files)
+	if cfg.Index.Graph {
+		g := graph.Build(adrs)
+		g.Lifecycle = cfg.Lifecycle()
+		indexes[outputDir].Graph = g.Mermaid()
+	}
+	dirs := make([]string, 0, len(indexes))
+	for dir := range indexes {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		rendered, err := template.RenderIndex(indexes[dir], tmplStr, opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to render index for %s: %w", dir, err)
+		}
+		content := indexMarker + rendered
+		path := filepath.Join(dir, indexFileName)
+
+		data, err := os.ReadFile(path)
+		switch {
+		case err != nil:
+			changes = append(changes, indexChange{Path: path, Action: "create", Content: content})
+		case !strings.HasPrefix(string(data), indexMarker):
+			skipped = append(skipped, path)
+		case string(data) != content:
+			changes = append(changes, indexChange{Path: path, Action: "update", Old: string(data), Content: content})
+		}
+	}
+
+	stale, err := staleIndexes(outputDir, cfg.ArchiveDir, indexes)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes = append(changes, stale...)
+
+	return changes, skipped, nil
+}
+
+// staleIndexes finds generated index files in directories that no longer
+// get an index
+func staleIndexes(outputDir, archiveDir string, indexes map[string]*template.Index) ([]indexChange, error) {
+	var stale []indexChange
+	if _, err := os.Stat(outputDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	archivePath := filepath.Join(outputDir, archiveDir)
+	err := filepath.WalkDir(outputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == archivePath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != indexFileName {
+			return nil
+		}
+		if _, ok := indexes[filepath.Dir(path)]; ok {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(string(data), indexMarker) {
+			stale = append(stale, indexChange{Path: path, Action: "delete", Old: string(data)})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s for index files: %w", outputDir, err)
+	}
+	return stale, nil
+}
+
+// recordIndexChanges adds index changes to a sync result and, unless this
+// is a dry run, stages them into tx
+func recordIndexChanges(result *model.SyncResult, tx *txn.Transaction, rootDir string, changes []indexChange, dryRun bool) {
+	for _, c := range changes {
+		relPath, _ := filepath.Rel(rootDir, c.Path)
+		change := model.ADRChange{Name: "Index", Action: c.Action, FilePath: relPath}
+
+		switch c.Action {
+		case "create":
+			result.Files.Created = append(result.Files.Created, relPath)
+		case "update":
+			result.Files.Modified = append(result.Files.Modified, relPath)
+		case "delete":
+			result.Files.Deleted = append(result.Files.Deleted, relPath)
+		}
+
+		if dryRun {
+			fromName, toName := "a/"+filepath.ToSlash(relPath), "b/"+filepath.ToSlash(relPath)
+			switch c.Action {
+			case "create":
+				fromName = "/dev/null"
+			case "delete":
+				toName = "/dev/null"
+			}
+			change.Diff = diff.Unified(fromName, toName, c.Old, c.Content)
+		} else if c.Action == "delete" {
+			tx.Remove(c.Path)
+		} else {
+			tx.Write(c.Path, []byte(c.Content), 0644)
+		}
+
+		result.ADRs = append(result.ADRs, change)
+	}
+}
+
+// IndexOptions controls the behavior of the index command
+type IndexOptions struct {
+	DryRun bool // Report changes without writing files
+}
+
+// Index regenerates the ADR index files from the ADR files in the output
+// directory, without syncing annotations
+func Index(rootDir string, opts IndexOptions, output io.Writer) error {
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	outputDir := cfg.OutputDir
+	if !filepath.IsAbs(outputDir) {
+		outputDir = filepath.Join(rootDir, outputDir)
+	}
+
+	existing, err := scanExistingADRs(outputDir, cfg.ArchiveDir)
+	if err != nil {
+		return err
+	}
+
+	parts, err := loadPartials(rootDir)
+	if err != nil {
+		return err
+	}
+
+	// The graph shows how decisions relate, which only annotations record
+	var adrs []*model.ADR
+	if cfg.Index.Graph {
+		annotations, err := scanAnnotations(rootDir, cfg)
+		if err != nil {
+			return err
+		}
+		if adrs, err = model.Aggregate(annotations); err != nil {
+			return fmt.Errorf("aggregation failed: %w", err)
+		}
+	}
+
+	changes, skipped, err := indexChanges(rootDir, outputDir, cfg, indexADRs(existing), adrs, template.Options{Partials: parts.sources, Locale: cfg.Locale})
+	if err != nil {
+		return err
+	}
+	printSkippedIndexes(rootDir, skipped, output)
+
+	result := &model.SyncResult{}
+	tx := txn.New()
+	recordIndexChanges(result, tx, rootDir, changes, opts.DryRun)
+
+	if len(changes) == 0 {
+		fmt.Fprintln(output, "✓ Index is up to date")
+		return nil
+	}
+
+	if opts.DryRun {
+		for _, change := range result.ADRs {
+			fmt.Fprintf(output, "[DRY RUN] Would %s: %s\n", change.Action, change.FilePath)
+			fmt.Fprintln(output, change.Diff)
+		}
+		return nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	printApplied(result, "", output)
+	return nil
+}
+
+// printSkippedIndexes reports index files left alone because they were
+// written by hand
+func printSkippedIndexes(rootDir string, skipped []string, output io.Writer) {
+	for _, path := range skipped {
+		relPath, _ := filepath.Rel(rootDir, path)
+		fmt.Fprintf(output, "Skipped: %s (not generated by adr-buddy)\n", relPath)
+	}
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// indexFixture is a project with three ADRs in nested categories
+var indexFixture = map[string]string{
+	"app.go": `// @decision.id: adr-2
+// @decision.name: Event bus
+// @decision.category: platform/api
+// @decision.status: accepted
+package main
+
+// @decision.id: adr-10
+// @decision.name: Logging
+var a = 1
+
+// @decision.id: adr-1
+// @decision.name: Token storage
+// @decision.category: security
+var b = 1
+`,
+}
+
+// writeFiles writes files into dir, keyed by their slash-separated path
+// relative to it, creating directories as needed
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestIndex_Generate(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, indexFixture)
+	require.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+
+	var output bytes.Buffer
+	require.NoError(t, Index(tmpDir, IndexOptions{}, &output))
+	assert.Contains(t, output.String(), "Created: "+filepath.Join("decisions", "README.md"))
+
+	root := readFile(t, filepath.Join(tmpDir, "decisions", "README.md"))
+	assert.True(t, strings.HasPrefix(root, indexMarker))
+	assert.Contains(t, root, "| [adr-10](adr-10.md) | [Logging](adr-10.md) | proposed |  |")
+	assert.Contains(t, root, "## [platform/api](platform/api/README.md)")
+	assert.Contains(t, root, "| [adr-2](platform/api/adr-2.md) | [Event bus](platform/api/adr-2.md) | accepted | platform/api |")
+	assert.Less(t, strings.Index(root, "## Uncategorized"), strings.Index(root, "## [security]"))
+
+	// Parent categories get an index listing their subcategories
+	platform := readFile(t, filepath.Join(tmpDir, "decisions", "platform", "README.md"))
+	assert.Contains(t, platform, "# platform Decisions")
+	assert.Contains(t, platform, "## [platform/api](api/README.md)")
+	assert.Contains(t, platform, "(api/adr-2.md)")
+
+	api := readFile(t, filepath.Join(tmpDir, "decisions", "platform", "api", "README.md"))
+	assert.Contains(t, api, "## platform/api\n")
+	assert.Contains(t, api, "(adr-2.md)")
+
+	// A second run changes nothing
+	output.Reset()
+	require.NoError(t, Index(tmpDir, IndexOptions{}, &output))
+	assert.Equal(t, "✓ Index is up to date\n", output.String())
+}
+
+func TestIndex_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, indexFixture)
+	require.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+
+	var output bytes.Buffer
+	require.NoError(t, Index(tmpDir, IndexOptions{DryRun: true}, &output))
+	assert.Contains(t, output.String(), "[DRY RUN] Would create: "+filepath.Join("decisions", "README.md"))
+	assert.Contains(t, output.String(), "+# Architecture Decision Records")
+
+	_, err := os.Stat(filepath.Join(tmpDir, "decisions", "README.md"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestIndex_KeepsHandWrittenReadme(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, indexFixture)
+	require.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+	readme := filepath.Join(tmpDir, "decisions", "README.md")
+	require.NoError(t, os.WriteFile(readme, []byte("# Our decisions\n"), 0644))
+
+	var output bytes.Buffer
+	require.NoError(t, Index(tmpDir, IndexOptions{}, &output))
+	assert.Contains(t, output.String(), "Skipped: "+filepath.Join("decisions", "README.md")+" (not generated by adr-buddy)")
+	assert.Equal(t, "# Our decisions\n", readFile(t, readme))
+}
+
+func TestSync_Index(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, indexFixture)
+	writeFiles(t, tmpDir, map[string]string{".adr-buddy/config.yml": "index:\n  enabled: true\norphan_policy: delete\n"})
+	require.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+
+	root := readFile(t, filepath.Join(tmpDir, "decisions", "README.md"))
+	assert.Contains(t, root, "[adr-1](security/adr-1.md)")
+	assert.FileExists(t, filepath.Join(tmpDir, "decisions", "security", "README.md"))
+
+	// Up to date after a sync, so check passes
+	assert.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Check: true, Format: "text"}, &bytes.Buffer{}))
+
+	// Removing the only security ADR removes its index and updates the top level
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte("// @decision.id: adr-10\n// @decision.name: Logging\npackage main\n"), 0644))
+	var output bytes.Buffer
+	require.NoError(t, SyncWithFormat(tmpDir, false, "text", &output))
+	assert.Contains(t, output.String(), "Removed: "+filepath.Join("decisions", "security", "README.md"))
+	assert.NotContains(t, readFile(t, filepath.Join(tmpDir, "decisions", "README.md")), "[adr-1]")
+	assert.NoFileExists(t, filepath.Join(tmpDir, "decisions", "security", "README.md"))
+}
+
+func TestSync_IndexCustomTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, indexFixture)
+	writeFiles(t, tmpDir, map[string]string{".adr-buddy/config.yml": "index:\n  enabled: true\n  template: .adr-buddy/index.md\n"})
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".adr-buddy", "index.md"),
+		[]byte("{{range .Entries}}- {{.ID}} {{.Status}}\n{{end}}"), 0644))
+
+	require.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+	assert.Equal(t, indexMarker+"- adr-1 proposed\n- adr-2 accepted\n- adr-10 proposed\n",
+		readFile(t, filepath.Join(tmpDir, "decisions", "README.md")))
+}
+
+func TestIndex_Graph(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, indexFixture)
+	writeFiles(t, tmpDir, map[string]string{".adr-buddy/config.yml": "index:\n  graph: true\n"})
+	require.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+	require.NoError(t, Index(tmpDir, IndexOptions{}, &bytes.Buffer{}))
+
+	root := readFile(t, filepath.Join(tmpDir, "decisions", "README.md"))
+	assert.Contains(t, root, "## Decision Graph\n\n```mermaid\nflowchart LR\n")
+	assert.Contains(t, root, "adr_2[\"adr-2: Event bus\"]")
+	assert.Contains(t, root, "```\n\n## Uncategorized")
+
+	// Only the top-level index has the graph
+	assert.NotContains(t, readFile(t, filepath.Join(tmpDir, "decisions", "security", "README.md")), "mermaid")
+}
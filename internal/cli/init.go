@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
@@ -20,6 +21,12 @@ const (
 	SkillLocationUser
 )
 
+// InitOptions controls what init creates
+type InitOptions struct {
+	Skill    SkillLocation
+	Template string // Built-in preset to use, e.g. "madr" (default if empty)
+}
+
 // Init initializes the .adr-buddy directory with default config and template
 func Init(rootDir string) error {
 	return InitWithSkill(rootDir, SkillLocationSkip)
@@ -27,6 +34,22 @@ func Init(rootDir string) error {
 
 // InitWithSkill initializes .adr-buddy directory and optionally installs Claude Code skill
 func InitWithSkill(rootDir string, skillLocation SkillLocation) error {
+	return InitWithOptions(rootDir, InitOptions{Skill: skillLocation})
+}
+
+// InitWithOptions initializes .adr-buddy directory using a template preset
+// and optionally installs Claude Code skill
+func InitWithOptions(rootDir string, opts InitOptions) error {
+	skillLocation := opts.Skill
+
+	preset, ok := template.LookupPreset("default")
+	if opts.Template != "" {
+		preset, ok = template.LookupPreset(opts.Template)
+	}
+	if !ok {
+		return fmt.Errorf("unknown template preset %q: must be one of: %s", opts.Template, strings.Join(template.PresetNames(), ", "))
+	}
+
 	// Verify root directory exists
 	if _, err := os.Stat(rootDir); os.IsNotExist(err) {
 		return fmt.Errorf("directory does not exist: %s", rootDir)
@@ -42,6 +65,9 @@ func InitWithSkill(rootDir string, skillLocation SkillLocation) error {
 	configPath := filepath.Join(adrDir, "config.yml")
 	if err := createFileIfNotExists(configPath, func() ([]byte, error) {
 		cfg := config.Default()
+		if opts.Template != "" {
+			cfg.Template = template.PresetPrefix + preset.Name
+		}
 		data, err := yaml.Marshal(cfg)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal config: %w", err)
@@ -56,7 +82,7 @@ func InitWithSkill(rootDir string, skillLocation SkillLocation) error {
 	templatePath := filepath.Join(adrDir, "template.md")
 	if err := createFileIfNotExists(templatePath, func() ([]byte, error) {
 		fmt.Println("Created template file:", templatePath)
-		return []byte(template.DefaultTemplate()), nil
+		return []byte(preset.Template), nil
 	}); err != nil {
 		return err
 	}
@@ -64,10 +64,36 @@ func TestInit_CreatesTemplateFile(t *testing.T) {
 	content := string(data)
 	assert.Contains(t, content, "{{.ID}}")
 	assert.Contains(t, content, "{{.Name}}")
-	assert.Contains(t, content, "{{.Status}}")
-	assert.Contains(t, content, "## Context")
-	assert.Contains(t, content, "## Decision")
-	assert.Contains(t, content, "## Consequences")
+	assert.Contains(t, content, "{{.Locale.StatusLabel .Status}}")
+	assert.Contains(t, content, "## {{.Locale.Headings.Context}}")
+	assert.Contains(t, content, "## {{.Locale.Headings.Decision}}")
+	assert.Contains(t, content, "## {{.Locale.Headings.Consequences}}")
+}
+
+func TestInitWithOptions_TemplatePreset(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := InitWithOptions(tmpDir, InitOptions{Skill: SkillLocationSkip, Template: "madr"})
+	require.NoError(t, err)
+
+	cfg, err := config.Load(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, "preset:madr", cfg.Template)
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".adr-buddy", "template.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "## Context and Problem Statement")
+}
+
+func TestInitWithOptions_UnknownPreset(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := InitWithOptions(tmpDir, InitOptions{Template: "rfc"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown template preset")
+
+	_, err = os.Stat(filepath.Join(tmpDir, ".adr-buddy"))
+	assert.True(t, os.IsNotExist(err))
 }
 
 func TestInit_IdempotentExecution(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/weaby/adr-buddy/internal/config"
@@ -13,12 +14,25 @@ import (
 	"github.com/weaby/adr-buddy/internal/parser"
 )
 
+// ListOptions controls which ADRs the list command shows
+type ListOptions struct {
+	Category string   // Only ADRs in this category
+	Statuses []string // Only ADRs with one of these statuses
+}
+
 // ListCommand lists all discovered ADRs in tabular format.
 // It scans the configured paths in rootDir, aggregates annotations into ADRs,
 // optionally filters by category, and writes the table to output.
 // If output is nil, writes to os.Stdout.
 // Returns an error if scanning or aggregation fails.
 func ListCommand(rootDir, category string, output io.Writer) error {
+	return ListWithOptions(rootDir, ListOptions{Category: category}, output)
+}
+
+// ListWithOptions lists ADRs like ListCommand, filtered by category and
+// status. Statuses must be in the configured vocabulary.
+func ListWithOptions(rootDir string, opts ListOptions, output io.Writer) error {
+	category := opts.Category
 	if output == nil {
 		output = os.Stdout
 	}
@@ -27,6 +41,11 @@ func ListCommand(rootDir, category string, output io.Writer) error {
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	for _, status := range opts.Statuses {
+		if err := parser.ValidateStatusIn(status, cfg.Lifecycle().Statuses, true); err != nil {
+			return err
+		}
+	}
 
 	// Scan all configured paths
 	var allAnnotations []*model.Annotation
@@ -71,6 +90,25 @@ func ListCommand(rootDir, category string, output io.Writer) error {
 		}
 	}
 
+	// Filter by status if specified
+	if len(opts.Statuses) > 0 {
+		var filtered []*model.ADR
+		for _, adr := range adrs {
+			for _, status := range opts.Statuses {
+				if adr.Status == status {
+					filtered = append(filtered, adr)
+					break
+				}
+			}
+		}
+		adrs = filtered
+
+		if len(adrs) == 0 {
+			fmt.Fprintf(output, "No ADRs found with status %s.\n", strings.Join(opts.Statuses, " or "))
+			return nil
+		}
+	}
+
 	// Sort ADRs by ID for deterministic output
 	sort.Slice(adrs, func(i, j int) bool {
 		return adrs[i].ID < adrs[j].ID
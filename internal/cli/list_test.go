@@ -83,3 +83,37 @@ func TestListCommand_CategoryFilter(t *testing.T) {
 	assert.NotContains(t, output, "adr-root-1")
 	assert.NotContains(t, output, "Root Decision")
 }
+
+func TestListWithOptions_StatusFilter(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".adr-buddy"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".adr-buddy", "config.yml"), []byte(`statuses:
+  values: [draft, proposed, in-review, accepted, retired]
+`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "a.js"), []byte(`// @decision.id: adr-1
+// @decision.name: Drafted
+// @decision.status: draft
+
+// @decision.id: adr-2
+// @decision.name: Under review
+// @decision.status: in-review
+
+// @decision.id: adr-3
+// @decision.name: Retired
+// @decision.status: retired
+`), 0644))
+
+	var buf bytes.Buffer
+	assert.NoError(t, ListWithOptions(tmpDir, ListOptions{Statuses: []string{"draft", "in-review"}}, &buf))
+	output := buf.String()
+	assert.Contains(t, output, "adr-1")
+	assert.Contains(t, output, "adr-2")
+	assert.NotContains(t, output, "adr-3")
+
+	buf.Reset()
+	assert.NoError(t, ListWithOptions(tmpDir, ListOptions{Statuses: []string{"accepted"}}, &buf))
+	assert.Equal(t, "No ADRs found with status accepted.\n", buf.String())
+
+	err := ListWithOptions(tmpDir, ListOptions{Statuses: []string{"rejected"}}, &buf)
+	assert.ErrorContains(t, err, `invalid status "rejected": must be one of: draft, proposed, in-review, accepted, retired`)
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/parser"
+	"github.com/weaby/adr-buddy/internal/txn"
+)
+
+// NewOptions controls the behavior of the new command
+type NewOptions struct {
+	Name     string // Name of the decision
+	Category string // Optional category
+	File     string // File to add the annotation to, relative to rootDir; empty prints it
+	Line     int    // Line to add the annotation above, from 1
+	Symbol   string // Declaration to add the annotation above, instead of Line
+	Edit     bool   // Open the file in $EDITOR afterwards
+}
+
+// openEditor opens a file at a line in the user's editor. It is a variable
+// so tests can replace it.
+var openEditor = func(path string, line int) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		return errors.New("set $EDITOR to open the file")
+	}
+	// Most editors, like vi, nano and emacs, take the line as "+N"
+	args := strings.Fields(editor)
+	args = append(args, fmt.Sprintf("+%d", line), path)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// New scaffolds the annotation of a new decision with the next free ID of
+// its category. The block is inserted into a file in the file's comment
+// style, above a line or a declaration, or printed when no file is given.
+func New(rootDir string, opts NewOptions, output io.Writer) error {
+	if strings.TrimSpace(opts.Name) == "" {
+		return errors.New("a decision name is required")
+	}
+	if opts.Line != 0 && opts.Symbol != "" {
+		return errors.New("--line and --symbol can't be used together")
+	}
+	if opts.File != "" && opts.Line == 0 && opts.Symbol == "" {
+		return errors.New("--line or --symbol is required with --file")
+	}
+
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	id, err := nextID(rootDir, cfg, opts.Category)
+	if err != nil {
+		return err
+	}
+
+	fields := []parser.Field{
+		{Name: "id", Value: id},
+		{Name: "name", Value: strings.TrimSpace(opts.Name)},
+		{Name: "status", Value: "proposed"},
+	}
+	if opts.Category != "" {
+		fields = append(fields, parser.Field{Name: "category", Value: opts.Category})
+	}
+	fields = append(fields,
+		parser.Field{Name: "context"},
+		parser.Field{Name: "decision"},
+		parser.Field{Name: "consequences"},
+	)
+
+	if opts.File == "" {
+		for _, line := range parser.FormatBlock("", "", fields) {
+			fmt.Fprintln(output, line)
+		}
+		return nil
+	}
+
+	path := absPath(rootDir, opts.File)
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", opts.File, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", opts.File, err)
+	}
+
+	line := opts.Line
+	if opts.Symbol != "" {
+		if line, err = parser.FindSymbol(path, string(data), opts.Symbol); err != nil {
+			return err
+		}
+	}
+	content, line, err := parser.InsertBlock(filepath.Base(path), string(data), line, fields)
+	if err != nil {
+		return err
+	}
+
+	tx := txn.New()
+	tx.Write(path, []byte(content), info.Mode().Perm())
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", opts.File, err)
+	}
+
+	fmt.Fprintf(output, "✓ Added %s to %s:%d\n", id, filepath.ToSlash(opts.File), line)
+	if opts.Edit {
+		return openEditor(path, line)
+	}
+	fmt.Fprintln(output, "Fill in the context, decision and consequences, then run adr-buddy sync.")
+	return nil
+}
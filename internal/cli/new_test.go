@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "src"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "src", "db.go"), []byte(`package src
+
+// @decision.id: adr-002
+// @decision.name: Use PostgreSQL
+func Open() {}
+
+func Publish() {
+	send()
+}
+`), 0644))
+	// Archived IDs aren't reused
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "decisions", "archive"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "decisions", "archive", "adr-005.md"), []byte("# adr-005: Old\n"), 0644))
+
+	var output bytes.Buffer
+	require.NoError(t, New(tmpDir, NewOptions{Name: "Use Kafka for events", Category: "infrastructure", File: "src/db.go", Symbol: "Publish"}, &output))
+	assert.Contains(t, output.String(), "✓ Added adr-006 to src/db.go:7")
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "src", "db.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `func Open() {}
+
+// @decision.id: adr-006
+// @decision.name: Use Kafka for events
+// @decision.status: proposed
+// @decision.category: infrastructure
+// @decision.context:
+// @decision.decision:
+// @decision.consequences:
+func Publish() {
+`)
+
+	// The next one gets the next ID, at a line, indented like it
+	output.Reset()
+	require.NoError(t, New(tmpDir, NewOptions{Name: "Retry sends", File: "src/db.go", Line: 15}, &output))
+	content, err = os.ReadFile(filepath.Join(tmpDir, "src", "db.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "\t// @decision.id: adr-007\n\t// @decision.name: Retry sends\n")
+
+	// An already annotated symbol keeps its annotation apart from the new one
+	output.Reset()
+	require.NoError(t, New(tmpDir, NewOptions{Name: "Pool connections", File: "src/db.go", Symbol: "Open"}, &output))
+	assert.Contains(t, output.String(), "✓ Added adr-008 to src/db.go:6")
+	content, err = os.ReadFile(filepath.Join(tmpDir, "src", "db.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "// @decision.name: Use PostgreSQL\n//\n// @decision.id: adr-008\n")
+
+	output.Reset()
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &output))
+	assert.FileExists(t, filepath.Join(tmpDir, "decisions", "adr-002.md"))
+	assert.FileExists(t, filepath.Join(tmpDir, "decisions", "adr-008.md"))
+	assert.FileExists(t, filepath.Join(tmpDir, "decisions", "infrastructure", "adr-006.md"))
+	assert.FileExists(t, filepath.Join(tmpDir, "decisions", "adr-007.md"))
+}
+
+func TestNew_Print(t *testing.T) {
+	var output bytes.Buffer
+	require.NoError(t, New(t.TempDir(), NewOptions{Name: "Use Kafka"}, &output))
+	assert.Equal(t, `// @decision.id: adr-001
+// @decision.name: Use Kafka
+// @decision.status: proposed
+// @decision.context:
+// @decision.decision:
+// @decision.consequences:
+`, output.String())
+}
+
+func TestNew_Edit(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.py"), []byte("import os\n"), 0644))
+
+	var opened string
+	var openedLine int
+	defer func(orig func(string, int) error) { openEditor = orig }(openEditor)
+	openEditor = func(path string, line int) error {
+		opened, openedLine = path, line
+		return nil
+	}
+
+	var output bytes.Buffer
+	require.NoError(t, New(tmpDir, NewOptions{Name: "Use Celery", File: "main.py", Line: 1, Edit: true}, &output))
+	assert.Equal(t, filepath.Join(tmpDir, "main.py"), opened)
+	assert.Equal(t, 1, openedLine)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "main.py"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# @decision.id: adr-001\n")
+}
+
+func TestNew_InvalidOptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	var output bytes.Buffer
+	assert.ErrorContains(t, New(tmpDir, NewOptions{Name: " "}, &output), "a decision name is required")
+	assert.ErrorContains(t, New(tmpDir, NewOptions{Name: "X", File: "a.go"}, &output), "--line or --symbol is required with --file")
+	assert.ErrorContains(t, New(tmpDir, NewOptions{Name: "X", File: "a.go", Line: 1, Symbol: "A"}, &output), "--line and --symbol can't be used together")
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/template"
+	"github.com/weaby/adr-buddy/internal/txn"
+)
+
+// existingADR is a generated ADR file found in the output directory
+type existingADR struct {
+	ID      string
+	Name    string
+	Status  string
+	Path    string // Absolute path of the ADR file
+	Content string
+}
+
+// scanExistingADRs walks outputDir and returns all generated ADR files.
+// Only files whose title heading ID matches the file name are considered ADRs,
+// so hand-written documents living next to generated ones are left alone.
+// The archive directory is skipped.
+func scanExistingADRs(outputDir, archiveDir string) ([]existingADR, error) {
+	var existing []existingADR
+
+	if _, err := os.Stat(outputDir); os.IsNotExist(err) {
+		return existing, nil
+	}
+
+	archivePath := filepath.Join(outputDir, archiveDir)
+
+	err := filepath.WalkDir(outputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path == archivePath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(path) != ".md" {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		parsed := template.ParseExistingADR(string(content))
+		id := strings.TrimSuffix(filepath.Base(path), ".md")
+		if parsed.Frontmatter["ID"] != id {
+			return nil
+		}
+
+		existing = append(existing, existingADR{
+			ID:      id,
+			Name:    parsed.Frontmatter["Name"],
+			Status:  parsed.Frontmatter["Status"],
+			Path:    path,
+			Content: string(content),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s for existing ADRs: %w", outputDir, err)
+	}
+
+	sort.Slice(existing, func(i, j int) bool {
+		return existing[i].Path < existing[j].Path
+	})
+
+	return existing, nil
+}
+
+// orphanAction returns the action policy prescribes for an orphaned ADR,
+// or "" when the file should be left untouched
+func orphanAction(o existingADR, policy string) string {
+	switch policy {
+	case config.OrphanPolicyMarkDeprecated:
+		// Already deprecated, or no status line to rewrite
+		if o.Status == "deprecated" || o.Status == "" {
+			return ""
+		}
+		return "deprecate"
+	case config.OrphanPolicyArchive:
+		return "archive"
+	case config.OrphanPolicyDelete:
+		return "delete"
+	}
+	return ""
+}
+
+// orphanContent returns the content an orphaned ADR file will have after
+// action is applied, or "" if the file goes away
+func orphanContent(o existingADR, action string, opts template.Options) string {
+	if action == "deprecate" {
+		updated, _ := template.SetStatusWithOptions(o.Content, "deprecated", opts)
+		return updated
+	}
+	return ""
+}
+
+// stageOrphanAction stages action on an orphaned ADR file into tx
+func stageOrphanAction(tx *txn.Transaction, o existingADR, action, outputDir, archiveDir string, opts template.Options) {
+	switch action {
+	case "deprecate":
+		tx.Write(o.Path, []byte(orphanContent(o, action, opts)), 0644)
+	case "archive":
+		rel, _ := filepath.Rel(outputDir, o.Path)
+		tx.Rename(o.Path, filepath.Join(outputDir, archiveDir, rel))
+	case "delete":
+		tx.Remove(o.Path)
+	}
+}
@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/diff"
+	"github.com/weaby/adr-buddy/internal/model"
+	"github.com/weaby/adr-buddy/internal/parser"
+	"github.com/weaby/adr-buddy/internal/template"
+	"github.com/weaby/adr-buddy/internal/txn"
+)
+
+// PullOptions controls the behavior of the pull command
+type PullOptions struct {
+	DryRun bool // Show the changes to the source files without writing them
+}
+
+// paragraphRe separates the paragraphs of a section
+var paragraphRe = regexp.MustCompile(`\n[ \t]*\n`)
+
+// pulledFields are the annotation fields that can be pulled, with the
+// section of the ADR file each is rendered in
+var pulledFields = []struct {
+	field   string
+	section string
+	value   func(*model.Annotation) string
+}{
+	{"context", "Context", func(a *model.Annotation) string { return a.Context }},
+	{"decision", "Decision", func(a *model.Annotation) string { return a.Decision }},
+	{"alternatives", "Alternatives", func(a *model.Annotation) string { return a.Alternatives }},
+	{"consequences", "Consequences", func(a *model.Annotation) string { return a.Consequences }},
+}
+
+// sourceEdits are the edits to one source file
+type sourceEdits struct {
+	edits   []parser.FieldEdit
+	changes []string // "adr-1: status, context" for each ADR changed
+}
+
+// Pull writes changes made by hand to generated ADR files back into the
+// annotations they were generated from. The name, status and the prose
+// sections set by annotations are compared; other edits stay in the ADR
+// files, where sync keeps them.
+func Pull(rootDir string, opts PullOptions, output io.Writer) error {
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	statuses := cfg.Lifecycle()
+	rootDir = absPath(rootDir, ".")
+
+	annotations, files, err := scanAnnotationFiles(rootDir, cfg)
+	if err != nil {
+		return err
+	}
+
+	adrs, err := model.Aggregate(annotations)
+	if err != nil {
+		return fmt.Errorf("aggregation failed: %w", err)
+	}
+	sort.Slice(adrs, func(i, j int) bool { return model.IDLess(adrs[i].ID, adrs[j].ID) })
+
+	templates, err := loadTemplates(rootDir, cfg)
+	if err != nil {
+		return err
+	}
+
+	existing, err := scanExistingADRs(absPath(rootDir, cfg.OutputDir), cfg.ArchiveDir)
+	if err != nil {
+		return fmt.Errorf("failed to scan existing ADRs: %w", err)
+	}
+	byID := make(map[string][]existingADR, len(existing))
+	for _, e := range existing {
+		byID[e.ID] = append(byID[e.ID], e)
+	}
+
+	bySource := make(map[string]*sourceEdits)
+	skipped := 0
+	for _, adr := range adrs {
+		found := byID[adr.ID]
+		if len(found) == 0 {
+			continue
+		}
+		// Which copy holds the edits is anyone's guess
+		if len(found) > 1 {
+			paths := make([]string, len(found))
+			for i, e := range found {
+				paths[i], _ = filepath.Rel(rootDir, e.Path)
+			}
+			fmt.Fprintf(output, "Skipped: %s (several ADR files: %s; remove the stale ones)\n", adr.ID, strings.Join(paths, ", "))
+			skipped++
+			continue
+		}
+		e := found[0]
+		var anns []*model.Annotation
+		for _, ann := range annotations {
+			if ann.ID == adr.ID {
+				anns = append(anns, ann)
+			}
+		}
+
+		parsed := template.ParseExistingADRWithOptions(e.Content, templates.forADR(adr), templates.opts)
+		changed := make(map[*model.Annotation][]string)
+		edit := func(ann *model.Annotation, field, value string) {
+			src := bySource[files[ann]]
+			if src == nil {
+				src = &sourceEdits{}
+				bySource[files[ann]] = src
+			}
+			src.edits = append(src.edits, parser.FieldEdit{Line: ann.Location.Line, Field: field, Value: value})
+			changed[ann] = append(changed[ann], field)
+		}
+
+		if name := parsed.Frontmatter["Name"]; name != "" && name != adr.Name {
+			for _, ann := range anns {
+				edit(ann, "name", name)
+			}
+		}
+
+		// The first annotation's status is the ADR's, so it always gets one,
+		// along with the date of the change
+		if status := parsed.Frontmatter["Status"]; status != "" && status != adr.Status {
+			err := parser.ValidateStatusIn(status, statuses.Statuses, true)
+			if err == nil {
+				err = statuses.CheckTransition(anns[0].DefaultStatus(), status)
+			}
+			if err != nil {
+				fmt.Fprintf(output, "Skipped: %s status (%v)\n", adr.ID, err)
+				skipped++
+			} else {
+				date := today()
+				for i, ann := range anns {
+					if ann.Status != "" || i == 0 {
+						edit(ann, "status", status)
+					}
+					if i == 0 {
+						edit(ann, statusDateField, date)
+					} else if _, ok := ann.CustomFields[statusDateField]; ok {
+						edit(ann, statusDateField, "")
+					}
+				}
+			}
+		}
+
+		for _, f := range pulledFields {
+			section, ok := parsed.Sections[f.section]
+			if !ok {
+				continue
+			}
+			var setters []*model.Annotation
+			var values []string
+			for _, ann := range anns {
+				if value := f.value(ann); value != "" {
+					setters = append(setters, ann)
+					values = append(values, value)
+				}
+			}
+			if len(setters) == 0 || sameText(section, strings.Join(values, "\n\n")) {
+				continue
+			}
+
+			if section == "" || template.IsPlaceholder(section) {
+				for _, ann := range setters {
+					edit(ann, f.field, "")
+				}
+				continue
+			}
+
+			paragraphs := paragraphRe.Split(strings.ReplaceAll(section, "\r\n", "\n"), -1)
+			if len(setters) == 1 && len(paragraphs) > 1 {
+				fmt.Fprintf(output, "Skipped: %s %s (%d paragraphs for one annotation, which can't hold blank lines; edit it in the code)\n",
+					adr.ID, f.field, len(paragraphs))
+				skipped++
+				continue
+			}
+			if len(paragraphs) != len(setters) {
+				fmt.Fprintf(output, "Skipped: %s %s (%d paragraphs for %d annotations, edit them in the code)\n",
+					adr.ID, f.field, len(paragraphs), len(setters))
+				skipped++
+				continue
+			}
+			for i, ann := range setters {
+				if !sameText(paragraphs[i], values[i]) {
+					edit(ann, f.field, paragraphs[i])
+				}
+			}
+		}
+
+		// Report per file, in the order the annotations were found
+		reported := make(map[string]bool)
+		for _, ann := range anns {
+			path := files[ann]
+			if len(changed[ann]) == 0 || reported[path] {
+				continue
+			}
+			reported[path] = true
+
+			var fields []string
+			seen := make(map[string]bool)
+			for _, other := range anns {
+				if files[other] != path {
+					continue
+				}
+				for _, field := range changed[other] {
+					if !seen[field] {
+						seen[field] = true
+						fields = append(fields, field)
+					}
+				}
+			}
+			bySource[path].changes = append(bySource[path].changes, adr.ID+": "+strings.Join(fields, ", "))
+		}
+	}
+
+	if len(bySource) == 0 && skipped > 0 {
+		fmt.Fprintln(output, "\nNo changes pulled")
+		return nil
+	}
+	if len(bySource) == 0 {
+		fmt.Fprintln(output, "✓ Annotations are up to date with the ADR files")
+		return nil
+	}
+
+	paths := make([]string, 0, len(bySource))
+	for path := range bySource {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	tx := txn.New()
+	for _, path := range paths {
+		relPath, _ := filepath.Rel(rootDir, path)
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", relPath, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", relPath, err)
+		}
+		content, err := parser.RewriteFields(path, string(data), bySource[path].edits)
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", relPath, err)
+		}
+
+		summary := strings.Join(bySource[path].changes, "; ")
+		if opts.DryRun {
+			fmt.Fprintf(output, "[DRY RUN] Would update: %s (%s)\n", relPath, summary)
+			fmt.Fprintln(output, diff.Unified("a/"+filepath.ToSlash(relPath), "b/"+filepath.ToSlash(relPath), string(data), content))
+			continue
+		}
+		tx.Write(path, []byte(content), info.Mode().Perm())
+		fmt.Fprintf(output, "Updated: %s (%s)\n", relPath, summary)
+	}
+
+	if opts.DryRun {
+		return nil
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write source files: %w", err)
+	}
+	fmt.Fprintf(output, "\n✓ %d source file(s) updated. Run adr-buddy sync to regenerate the ADRs.\n", len(paths))
+	return nil
+}
+
+// scanAnnotationFiles scans for annotations like scanAnnotations, also
+// returning the absolute path of the file each was found in, since
+// locations are relative to their scan path
+func scanAnnotationFiles(rootDir string, cfg *config.Config) ([]*model.Annotation, map[*model.Annotation]string, error) {
+	var annotations []*model.Annotation
+	files := make(map[*model.Annotation]string)
+	for _, scanPath := range cfg.ScanPaths {
+		root := absPath(rootDir, scanPath)
+		found, err := parser.ScanDirectory(root, cfg.Exclude)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan %s: %w", scanPath, err)
+		}
+		for _, ann := range found {
+			files[ann] = filepath.Join(root, filepath.FromSlash(ann.Location.File))
+		}
+		annotations = append(annotations, found...)
+	}
+	return annotations, files, nil
+}
+
+// sameText reports whether two texts have the same words, ignoring how
+// they are wrapped and indented
+func sameText(a, b string) bool {
+	return strings.Join(strings.Fields(a), " ") == strings.Join(strings.Fields(b), " ")
+}
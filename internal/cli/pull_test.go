@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pullFixture is two annotations of one decision, in two languages
+var pullFixture = map[string]string{
+	"src/queue.go": `package src
+
+// @decision.id: adr-001
+// @decision.name: Use Kafka
+// @decision.context: We need to publish payment events to
+//   several consumers.
+// @decision.decision: Use Kafka.
+func Publish() {}
+`,
+	"src/worker.py": `# @decision.id: adr-001
+# @decision.name: Use Kafka
+# @decision.decision: Consume with one group per service.
+def work(): pass
+`,
+}
+
+func editADR(t *testing.T, path string, replacements ...string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content := string(data)
+	for i := 0; i < len(replacements); i += 2 {
+		require.Contains(t, content, replacements[i])
+		content = strings.Replace(content, replacements[i], replacements[i+1], 1)
+	}
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestPull(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, pullFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+	stubToday(t, "2026-10-19")
+
+	adrPath := filepath.Join(tmpDir, "decisions", "adr-001.md")
+	editADR(t, adrPath,
+		"**Status:** proposed", "**Status:** accepted",
+		"several consumers.", "several consumers, which replay them.",
+		"Consume with one group per service.", "Consume with one consumer group per service.",
+	)
+
+	var output bytes.Buffer
+	require.NoError(t, Pull(tmpDir, PullOptions{}, &output))
+
+	out := output.String()
+	assert.Contains(t, out, "Updated: "+filepath.Join("src", "queue.go")+" (adr-001: status, status_date, context)")
+	assert.Contains(t, out, "Updated: "+filepath.Join("src", "worker.py")+" (adr-001: decision)")
+	assert.Contains(t, out, "✓ 2 source file(s) updated")
+
+	queue, err := os.ReadFile(filepath.Join(tmpDir, "src", "queue.go"))
+	require.NoError(t, err)
+	assert.Equal(t, `package src
+
+// @decision.id: adr-001
+// @decision.name: Use Kafka
+// @decision.status: accepted
+// @decision.status_date: 2026-10-19
+// @decision.context: We need to publish payment events to
+//   several consumers, which replay them.
+// @decision.decision: Use Kafka.
+func Publish() {}
+`, string(queue))
+
+	worker, err := os.ReadFile(filepath.Join(tmpDir, "src", "worker.py"))
+	require.NoError(t, err)
+	assert.Contains(t, string(worker), "# @decision.decision: Consume with one consumer group per service.\n")
+
+	// The annotations now generate the edited ADR, so there's nothing left
+	output.Reset()
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &output))
+	output.Reset()
+	require.NoError(t, Pull(tmpDir, PullOptions{}, &output))
+	assert.Contains(t, output.String(), "✓ Annotations are up to date with the ADR files")
+}
+
+func TestPull_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, pullFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+	editADR(t, filepath.Join(tmpDir, "decisions", "adr-001.md"), "# adr-001: Use Kafka", "# adr-001: Use Kafka for events")
+
+	before, err := os.ReadFile(filepath.Join(tmpDir, "src", "queue.go"))
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	require.NoError(t, Pull(tmpDir, PullOptions{DryRun: true}, &output))
+
+	out := output.String()
+	assert.Contains(t, out, "[DRY RUN] Would update: "+filepath.Join("src", "queue.go")+" (adr-001: name)")
+	assert.Contains(t, out, "-// @decision.name: Use Kafka\n+// @decision.name: Use Kafka for events\n")
+	assert.Contains(t, out, "+# @decision.name: Use Kafka for events\n")
+
+	after, err := os.ReadFile(filepath.Join(tmpDir, "src", "queue.go"))
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+}
+
+func TestPull_ParagraphMismatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, pullFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+	editADR(t, filepath.Join(tmpDir, "decisions", "adr-001.md"), "Use Kafka.", "Use Kafka.\n\nKeep events for a week.")
+
+	var output bytes.Buffer
+	require.NoError(t, Pull(tmpDir, PullOptions{}, &output))
+	assert.Contains(t, output.String(), "Skipped: adr-001 decision (3 paragraphs for 2 annotations, edit them in the code)")
+	assert.Contains(t, output.String(), "No changes pulled")
+}
+
+func TestPull_DuplicateADRFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, pullFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+	content, err := os.ReadFile(filepath.Join(tmpDir, "decisions", "adr-001.md"))
+	require.NoError(t, err)
+	writeFiles(t, tmpDir, map[string]string{"decisions/backend/adr-001.md": string(content)})
+	editADR(t, filepath.Join(tmpDir, "decisions", "backend", "adr-001.md"), "Use Kafka.", "Use Pulsar.")
+
+	before, err := os.ReadFile(filepath.Join(tmpDir, "src", "queue.go"))
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	require.NoError(t, Pull(tmpDir, PullOptions{}, &output))
+	assert.Contains(t, output.String(), "Skipped: adr-001 (several ADR files: "+
+		filepath.Join("decisions", "adr-001.md")+", "+filepath.Join("decisions", "backend", "adr-001.md")+"; remove the stale ones)")
+
+	after, err := os.ReadFile(filepath.Join(tmpDir, "src", "queue.go"))
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+}
+
+func TestPull_SingleAnnotationParagraphs(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, pullFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+	// Annotations can't hold blank lines, so paragraphs aren't flattened
+	editADR(t, filepath.Join(tmpDir, "decisions", "adr-001.md"), "several consumers.", "several consumers.\n\nThey replay them.")
+
+	before, err := os.ReadFile(filepath.Join(tmpDir, "src", "queue.go"))
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	require.NoError(t, Pull(tmpDir, PullOptions{}, &output))
+	assert.Contains(t, output.String(), "Skipped: adr-001 context (2 paragraphs for one annotation, which can't hold blank lines; edit it in the code)")
+	assert.Contains(t, output.String(), "No changes pulled")
+
+	after, err := os.ReadFile(filepath.Join(tmpDir, "src", "queue.go"))
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+}
+
+func TestPull_StatusLifecycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, pullFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".adr-buddy"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".adr-buddy", "config.yml"), []byte(`statuses:
+  values: [proposed, in-review, accepted, rejected]
+  transitions:
+    proposed: [in-review]
+    in-review: [accepted, rejected]
+`), 0644))
+	adrPath := filepath.Join(tmpDir, "decisions", "adr-001.md")
+
+	// Pulled statuses follow the lifecycle like the status command's
+	editADR(t, adrPath, "**Status:** proposed", "**Status:** accepted")
+	var output bytes.Buffer
+	require.NoError(t, Pull(tmpDir, PullOptions{}, &output))
+	assert.Contains(t, output.String(), "Skipped: adr-001 status (can't change status from proposed to accepted: proposed may only change to in-review)")
+
+	editADR(t, adrPath, "**Status:** accepted", "**Status:** approved")
+	output.Reset()
+	require.NoError(t, Pull(tmpDir, PullOptions{}, &output))
+	assert.Contains(t, output.String(), `Skipped: adr-001 status (invalid status "approved": must be one of: proposed, in-review, accepted, rejected)`)
+	assert.Contains(t, output.String(), "No changes pulled")
+}
@@ -0,0 +1,410 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/diff"
+	"github.com/weaby/adr-buddy/internal/model"
+	"github.com/weaby/adr-buddy/internal/parser"
+	"github.com/weaby/adr-buddy/internal/txn"
+)
+
+// RenameOptions controls the behavior of the rename command
+type RenameOptions struct {
+	DryRun bool // Show the changes without writing them
+}
+
+// RenumberOptions controls the behavior of the renumber command
+type RenumberOptions struct {
+	Pad    int  // Digits to pad sequential numbers to; negative uses the ID scheme's
+	DryRun bool // Show the changes without writing them
+}
+
+// relationFields are the annotation fields that refer to other decisions
+var relationFields = []string{"supersedes", "relates"}
+
+var (
+	// idTokenRe matches the items of a relation field
+	idTokenRe = regexp.MustCompile(`[^\s,]+`)
+	// inlineLinkRe matches "[text](target)" links
+	inlineLinkRe = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+)\)`)
+	// referenceLinkRe matches "[label]: target" link definitions
+	referenceLinkRe = regexp.MustCompile(`(?m)^([ \t]*\[[^\]]+\]:[ \t]*)(\S+)`)
+)
+
+// Rename changes the ID of a decision everywhere: in its annotations, in the
+// relations of other annotations, in the name and title of its ADR file, and
+// in markdown links to the file.
+func Rename(rootDir, oldID, newID string, opts RenameOptions, output io.Writer) error {
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	return renameIDs(rootDir, cfg, map[string]string{oldID: newID}, opts.DryRun, output)
+}
+
+// Renumber pads the number of every sequential ID to the same width, e.g.
+// adr-7 to adr-007, renaming each like Rename
+func Renumber(rootDir string, opts RenumberOptions, output io.Writer) error {
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	scheme := cfg.IDScheme()
+	pad := opts.Pad
+	if pad < 0 {
+		pad = scheme.Pad
+	}
+
+	inUse, err := idsInUse(rootDir, cfg)
+	if err != nil {
+		return err
+	}
+	renames := make(map[string]string)
+	for id := range inUse {
+		if padded, ok := scheme.Repad(id, pad); ok && padded != id {
+			renames[id] = padded
+		}
+	}
+	if len(renames) == 0 {
+		fmt.Fprintf(output, "✓ All IDs are already padded to %d digits\n", pad)
+		return nil
+	}
+	return renameIDs(rootDir, cfg, renames, opts.DryRun, output)
+}
+
+// idsInUse returns the IDs of annotations and of ADR files outside the
+// archive
+func idsInUse(rootDir string, cfg *config.Config) (map[string]bool, error) {
+	annotations, err := scanAnnotations(rootDir, cfg)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := scanExistingADRs(absPath(rootDir, cfg.OutputDir), cfg.ArchiveDir)
+	if err != nil {
+		return nil, err
+	}
+
+	inUse := make(map[string]bool)
+	for _, ann := range annotations {
+		if ann.ID != "" {
+			inUse[ann.ID] = true
+		}
+	}
+	for _, e := range existing {
+		inUse[e.ID] = true
+	}
+	return inUse, nil
+}
+
+// renameIDs applies renames, from old to new ID, to the annotations, the ADR
+// files and the markdown links to them, all together
+func renameIDs(rootDir string, cfg *config.Config, renames map[string]string, dryRun bool, output io.Writer) error {
+	rootDir = absPath(rootDir, ".")
+	inUse, err := idsInUse(rootDir, cfg)
+	if err != nil {
+		return err
+	}
+
+	olds := make([]string, 0, len(renames))
+	for old := range renames {
+		olds = append(olds, old)
+	}
+	sort.Slice(olds, func(i, j int) bool { return model.IDLess(olds[i], olds[j]) })
+
+	// With an ID scheme configured, new IDs must follow it in their category
+	categories := make(map[string]string)
+	if cfg.IDs != nil {
+		annotations, err := scanAnnotations(rootDir, cfg)
+		if err != nil {
+			return err
+		}
+		decisions, err := decisionIDs(rootDir, cfg, annotations)
+		if err != nil {
+			return err
+		}
+		for _, d := range decisions {
+			if _, ok := categories[d.ID]; !ok && !d.Archived {
+				categories[d.ID] = d.Category
+			}
+		}
+	}
+
+	taken := make(map[string]string)
+	for _, old := range olds {
+		id := renames[old]
+		switch {
+		case !inUse[old]:
+			return fmt.Errorf("%s not found in annotations or ADR files", old)
+		case id == "" || strings.ContainsAny(id, `/\:`) || strings.ContainsFunc(id, unicode.IsSpace):
+			return fmt.Errorf("invalid ID %q: must be non-empty without spaces, colons or path separators", id)
+		case cfg.IDs != nil && !cfg.IDs.Match(id, categories[old]):
+			return fmt.Errorf("invalid ID %q: doesn't match the %s scheme, expected %s", id, cfg.IDs.Kind, cfg.IDs.Pattern(categories[old]))
+		case inUse[id] && renames[id] == "":
+			return fmt.Errorf("cannot rename %s to %s: %s is already in use", old, id, id)
+		case taken[id] != "":
+			return fmt.Errorf("cannot rename both %s and %s to %s", taken[id], old, id)
+		}
+		taken[id] = old
+	}
+
+	// The new content of every file to change, keyed by its current path
+	originals := make(map[string]string)
+	contents := make(map[string]string)
+	read := func(path string) (string, error) {
+		if content, ok := contents[path]; ok {
+			return content, nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		originals[path] = string(data)
+		return string(data), nil
+	}
+
+	// Annotations
+	annotations, files, err := scanAnnotationFiles(rootDir, cfg)
+	if err != nil {
+		return err
+	}
+	edits := make(map[string][]parser.FieldEdit)
+	for _, ann := range annotations {
+		path := files[ann]
+		if id, ok := renames[ann.ID]; ok {
+			edits[path] = append(edits[path], parser.FieldEdit{Line: ann.Location.Line, Field: "id", Value: id})
+		}
+		for _, field := range relationFields {
+			value, ok := ann.CustomFields[field]
+			if !ok {
+				continue
+			}
+			if updated := replaceIDs(value, renames); updated != value {
+				edits[path] = append(edits[path], parser.FieldEdit{Line: ann.Location.Line, Field: field, Value: updated})
+			}
+		}
+	}
+	for path, fieldEdits := range edits {
+		content, err := read(path)
+		if err != nil {
+			return err
+		}
+		if contents[path], err = parser.RewriteFields(path, content, fieldEdits); err != nil {
+			return fmt.Errorf("failed to update %s: %w", path, err)
+		}
+	}
+
+	// ADR files, which move to their new name
+	existing, err := scanExistingADRs(absPath(rootDir, cfg.OutputDir), cfg.ArchiveDir)
+	if err != nil {
+		return err
+	}
+	moves := make(map[string]string)
+	for _, e := range existing {
+		id, ok := renames[e.ID]
+		if !ok {
+			continue
+		}
+		content, err := read(e.Path)
+		if err != nil {
+			return err
+		}
+		contents[e.Path] = renameInADR(content, e.ID, id)
+		moves[e.Path] = filepath.Join(filepath.Dir(e.Path), id+".md")
+	}
+
+	// Links to the moved ADR files from any markdown file
+	if len(moves) > 0 {
+		err := walkMarkdown(rootDir, cfg, func(path string) error {
+			content, err := read(path)
+			if err != nil {
+				return err
+			}
+			if updated := relinkMarkdown(rootDir, path, content, moves, renames); updated != content {
+				contents[path] = updated
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	paths := make([]string, 0, len(contents))
+	for path, content := range contents {
+		if content != originals[path] || moves[path] != "" {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	prefix := ""
+	if dryRun {
+		prefix = "[DRY RUN] Would "
+	}
+	tx := txn.New()
+	for _, path := range paths {
+		relPath, _ := filepath.Rel(rootDir, path)
+		target, moved := moves[path]
+		if !moved {
+			target = path
+		}
+		relTarget, _ := filepath.Rel(rootDir, target)
+
+		if moved {
+			fmt.Fprintf(output, "%s%s: %s -> %s\n", prefix, verb(dryRun, "Moved", "move"), relPath, relTarget)
+		} else {
+			fmt.Fprintf(output, "%s%s: %s\n", prefix, verb(dryRun, "Updated", "update"), relPath)
+		}
+		if dryRun {
+			if d := diff.Unified("a/"+filepath.ToSlash(relPath), "b/"+filepath.ToSlash(relTarget), originals[path], contents[path]); d != "" {
+				fmt.Fprintln(output, d)
+			}
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", relPath, err)
+		}
+		tx.Write(target, []byte(contents[path]), info.Mode().Perm())
+		if moved {
+			tx.Remove(path)
+		}
+	}
+
+	if dryRun {
+		fmt.Fprintf(output, "\n%d ID(s) would be renamed in %d file(s)\n", len(renames), len(paths))
+		return nil
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to rename: %w", err)
+	}
+	for _, old := range olds {
+		fmt.Fprintf(output, "Renamed: %s -> %s\n", old, renames[old])
+	}
+	fmt.Fprintf(output, "\n✓ %d ID(s) renamed in %d file(s). Run adr-buddy sync to regenerate the ADRs.\n", len(renames), len(paths))
+	return nil
+}
+
+// verb picks the past or the planned form of an action for the output
+func verb(dryRun bool, done, planned string) string {
+	if dryRun {
+		return planned
+	}
+	return done
+}
+
+// replaceIDs renames the IDs in the value of a relation field, keeping its
+// separators and layout
+func replaceIDs(value string, renames map[string]string) string {
+	return idTokenRe.ReplaceAllStringFunc(value, func(token string) string {
+		if id, ok := renames[token]; ok {
+			return id
+		}
+		return token
+	})
+}
+
+// renameInADR changes the ID in the title heading and front matter of an
+// ADR file
+func renameInADR(content, oldID, newID string) string {
+	titleRe := regexp.MustCompile(`(?m)^(# )` + regexp.QuoteMeta(oldID) + `:`)
+	content = titleRe.ReplaceAllString(content, "${1}"+strings.ReplaceAll(newID, "$", "$$")+":")
+
+	if !strings.HasPrefix(content, "---\n") && !strings.HasPrefix(content, "---\r\n") {
+		return content
+	}
+	end := strings.Index(content[3:], "\n---")
+	if end < 0 {
+		return content
+	}
+	end += 3
+	idRe := regexp.MustCompile(`(?m)^(id:[ \t]*["']?)` + regexp.QuoteMeta(oldID) + `(["']?[ \t]*\r?)$`)
+	front := idRe.ReplaceAllString(content[:end], "${1}"+strings.ReplaceAll(newID, "$", "$$")+"${2}")
+	return front + content[end:]
+}
+
+// relinkMarkdown points links in a markdown file at moved files. Links whose
+// text is the old ID get the new one.
+func relinkMarkdown(rootDir, path, content string, moves, renames map[string]string) string {
+	dir := filepath.Dir(path)
+	retarget := func(target string) (string, bool) {
+		if strings.Contains(target, "://") || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "mailto:") {
+			return "", false
+		}
+		file, anchor, _ := strings.Cut(target, "#")
+		if anchor != "" {
+			anchor = "#" + anchor
+		}
+
+		var resolved string
+		if strings.HasPrefix(file, "/") {
+			resolved = filepath.Join(rootDir, filepath.FromSlash(file))
+		} else {
+			resolved = filepath.Join(dir, filepath.FromSlash(file))
+		}
+		moved, ok := moves[resolved]
+		if !ok {
+			return "", false
+		}
+
+		if strings.HasPrefix(file, "/") {
+			rel, _ := filepath.Rel(rootDir, moved)
+			return "/" + filepath.ToSlash(rel) + anchor, true
+		}
+		rel, _ := filepath.Rel(dir, moved)
+		return filepath.ToSlash(rel) + anchor, true
+	}
+
+	content = inlineLinkRe.ReplaceAllStringFunc(content, func(link string) string {
+		m := inlineLinkRe.FindStringSubmatch(link)
+		target, ok := retarget(m[2])
+		if !ok {
+			return link
+		}
+		text := m[1]
+		if id, ok := renames[text]; ok {
+			text = id
+		}
+		return "[" + text + "](" + target + ")"
+	})
+	return referenceLinkRe.ReplaceAllStringFunc(content, func(def string) string {
+		m := referenceLinkRe.FindStringSubmatch(def)
+		if target, ok := retarget(m[2]); ok {
+			return m[1] + target
+		}
+		return def
+	})
+}
+
+// walkMarkdown calls fn with every markdown file below rootDir that isn't
+// excluded by the config
+func walkMarkdown(rootDir string, cfg *config.Config, fn func(path string) error) error {
+	return filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(rootDir, path)
+		if d.IsDir() {
+			// Directory patterns like "**/vendor/**" match what's inside
+			if rel != "." && (d.Name() == ".git" || parser.ShouldExclude(filepath.ToSlash(rel)+"/x", cfg.Exclude)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".md" || parser.ShouldExclude(filepath.ToSlash(rel), cfg.Exclude) {
+			return nil
+		}
+		return fn(path)
+	})
+}
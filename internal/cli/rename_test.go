@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// renameFixture is two related decisions and a README linking to them
+var renameFixture = map[string]string{
+	"src/queue.go": `package src
+
+// @decision.id: adr-7
+// @decision.name: Use Kafka
+// @decision.decision: Use Kafka.
+func Publish() {}
+
+// @decision.id: adr-12
+// @decision.name: Use Avro
+// @decision.decision: Use Avro.
+// @decision.relates: adr-7, adr-70
+func Encode() {}
+`,
+	"src/worker.py": `# @decision.id: adr-7
+# @decision.name: Use Kafka
+# @decision.consequences: One consumer group per service.
+def work(): pass
+`,
+	"README.md": `# Payments
+
+See [adr-7](decisions/adr-7.md#decision), [Avro](./decisions/adr-12.md) and [the docs][kafka].
+
+[kafka]: /decisions/adr-7.md
+[site]: https://example.com/decisions/adr-7.md
+`,
+}
+
+func TestRename(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, renameFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+
+	var output bytes.Buffer
+	require.NoError(t, Rename(tmpDir, "adr-7", "adr-007", RenameOptions{}, &output))
+
+	out := output.String()
+	assert.Contains(t, out, "Moved: "+filepath.Join("decisions", "adr-7.md")+" -> "+filepath.Join("decisions", "adr-007.md"))
+	assert.Contains(t, out, "Updated: README.md")
+	assert.Contains(t, out, "Updated: "+filepath.Join("src", "worker.py"))
+	assert.Contains(t, out, "✓ 1 ID(s) renamed in 4 file(s)")
+
+	queue, err := os.ReadFile(filepath.Join(tmpDir, "src", "queue.go"))
+	require.NoError(t, err)
+	assert.Equal(t, `package src
+
+// @decision.id: adr-007
+// @decision.name: Use Kafka
+// @decision.decision: Use Kafka.
+func Publish() {}
+
+// @decision.id: adr-12
+// @decision.name: Use Avro
+// @decision.decision: Use Avro.
+// @decision.relates: adr-007, adr-70
+func Encode() {}
+`, string(queue))
+
+	worker, err := os.ReadFile(filepath.Join(tmpDir, "src", "worker.py"))
+	require.NoError(t, err)
+	assert.Contains(t, string(worker), "# @decision.id: adr-007\n")
+
+	assert.NoFileExists(t, filepath.Join(tmpDir, "decisions", "adr-7.md"))
+	adr, err := os.ReadFile(filepath.Join(tmpDir, "decisions", "adr-007.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(adr), "# adr-007: Use Kafka")
+
+	readme, err := os.ReadFile(filepath.Join(tmpDir, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, `# Payments
+
+See [adr-007](decisions/adr-007.md#decision), [Avro](./decisions/adr-12.md) and [the docs][kafka].
+
+[kafka]: /decisions/adr-007.md
+[site]: https://example.com/decisions/adr-7.md
+`, string(readme))
+
+	// The renamed ADR is in sync with the annotations
+	output.Reset()
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &output))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "decisions", "adr-7.md"))
+	synced, err := os.ReadFile(filepath.Join(tmpDir, "decisions", "adr-007.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(synced), "One consumer group per service.")
+}
+
+func TestRename_DryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, renameFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+	before, err := os.ReadFile(filepath.Join(tmpDir, "src", "queue.go"))
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	require.NoError(t, Rename(tmpDir, "adr-7", "adr-007", RenameOptions{DryRun: true}, &output))
+
+	out := output.String()
+	assert.Contains(t, out, "[DRY RUN] Would move: "+filepath.Join("decisions", "adr-7.md"))
+	assert.Contains(t, out, "-// @decision.id: adr-7\n+// @decision.id: adr-007")
+	assert.Contains(t, out, "1 ID(s) would be renamed in 4 file(s)")
+
+	after, err := os.ReadFile(filepath.Join(tmpDir, "src", "queue.go"))
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+	assert.FileExists(t, filepath.Join(tmpDir, "decisions", "adr-7.md"))
+}
+
+func TestRename_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, renameFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+
+	var output bytes.Buffer
+	assert.ErrorContains(t, Rename(tmpDir, "adr-99", "adr-100", RenameOptions{}, &output), "adr-99 not found")
+	assert.ErrorContains(t, Rename(tmpDir, "adr-7", "adr-12", RenameOptions{}, &output), "adr-12 is already in use")
+	assert.ErrorContains(t, Rename(tmpDir, "adr-7", "infra/adr-7", RenameOptions{}, &output), `invalid ID "infra/adr-7"`)
+	assert.ErrorContains(t, Rename(tmpDir, "adr-7", "bad id", RenameOptions{}, &output), `invalid ID "bad id"`)
+	assert.ErrorContains(t, Rename(tmpDir, "adr-7", "adr-\t7", RenameOptions{}, &output), "must be non-empty without spaces")
+	assert.ErrorContains(t, Rename(tmpDir, "adr-7", "adr:7", RenameOptions{}, &output), `invalid ID "adr:7": must be non-empty without spaces, colons or path separators`)
+
+	// New IDs must follow the configured scheme
+	writeFiles(t, tmpDir, map[string]string{".adr-buddy/config.yml": "ids:\n  pad: 3\n"})
+	assert.ErrorContains(t, Rename(tmpDir, "adr-7", "adr-07", RenameOptions{}, &output), `invalid ID "adr-07": doesn't match the sequential scheme, expected adr-NNN`)
+	assert.NoError(t, Rename(tmpDir, "adr-7", "adr-007", RenameOptions{DryRun: true}, &output))
+}
+
+func TestRenumber(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, renameFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+
+	var output bytes.Buffer
+	require.NoError(t, Renumber(tmpDir, RenumberOptions{Pad: -1}, &output))
+	assert.Contains(t, output.String(), "Renamed: adr-7 -> adr-007")
+	assert.Contains(t, output.String(), "Renamed: adr-12 -> adr-012")
+
+	queue, err := os.ReadFile(filepath.Join(tmpDir, "src", "queue.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(queue), "// @decision.id: adr-012\n")
+	assert.Contains(t, string(queue), "// @decision.relates: adr-007, adr-70\n")
+	assert.FileExists(t, filepath.Join(tmpDir, "decisions", "adr-012.md"))
+
+	output.Reset()
+	require.NoError(t, Renumber(tmpDir, RenumberOptions{Pad: 3}, &output))
+	assert.Contains(t, output.String(), "✓ All IDs are already padded to 3 digits")
+}
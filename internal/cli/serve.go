@@ -0,0 +1,375 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/lifecycle"
+	"github.com/weaby/adr-buddy/internal/model"
+	"github.com/weaby/adr-buddy/internal/site"
+	"github.com/weaby/adr-buddy/internal/watch"
+)
+
+// DefaultServeAddr is where serve listens unless another address is given.
+// It only accepts connections from this machine.
+const DefaultServeAddr = "localhost:8080"
+
+// eventsPath streams the site version to pages, which reload when it changes
+const eventsPath = "/api/events"
+
+// ServeOptions controls the behavior of the serve command
+type ServeOptions struct {
+	Addr     string        // Address to listen on, DefaultServeAddr if empty
+	Poll     bool          // Poll for changes instead of using native notifications
+	Interval time.Duration // Polling interval
+	Debounce time.Duration // Quiet period before rebuilding (DefaultDebounce if zero)
+}
+
+// adrServer serves the site built from the current annotations. Pages and
+// ADRs are replaced together whenever sources change.
+type adrServer struct {
+	mu       sync.RWMutex
+	adrs     []*model.ADR
+	statuses lifecycle.Lifecycle // Statuses ?status= may name
+	pages    map[string][]byte
+	version  int
+	changed  chan struct{} // Closed and replaced on every update
+}
+
+func newADRServer() *adrServer {
+	return &adrServer{statuses: lifecycle.Default(), changed: make(chan struct{})}
+}
+
+// update replaces the served content and notifies open pages, unless
+// nothing a page shows has changed
+func (s *adrServer) update(adrs []*model.ADR, statuses lifecycle.Lifecycle, pages map[string][]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.adrs = adrs
+	s.statuses = statuses
+	if samePages(s.pages, pages) {
+		return
+	}
+	s.pages = pages
+	s.version++
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func samePages(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, page := range a {
+		if !bytes.Equal(page, b[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// handler routes the pages and the JSON API
+func (s *adrServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handlePage)
+	mux.HandleFunc("GET /{page}", s.handlePage)
+	mux.HandleFunc("GET /api/adrs", s.handleList)
+	mux.HandleFunc("GET /api/search", s.handleSearch)
+	mux.HandleFunc("GET "+eventsPath, s.handleEvents)
+	return mux
+}
+
+func (s *adrServer) handlePage(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("page")
+	if name == "" {
+		name = site.IndexFile
+	}
+
+	s.mu.RLock()
+	page, ok := s.pages[name]
+	loading := s.pages == nil
+	s.mu.RUnlock()
+
+	switch {
+	case loading:
+		http.Error(w, "Scanning annotations, try again in a moment", http.StatusServiceUnavailable)
+	case !ok:
+		http.NotFound(w, r)
+	default:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	}
+}
+
+// listEntry is an ADR in the JSON API, with the columns of the list command
+type listEntry struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Category  string `json:"category"`
+	Locations int    `json:"locations"`
+	URL       string `json:"url"` // Path of the ADR's page
+}
+
+func newListEntry(adr *model.ADR) listEntry {
+	return listEntry{
+		ID:        adr.ID,
+		Name:      adr.Name,
+		Status:    adr.Status,
+		Category:  adr.Category,
+		Locations: len(adr.Locations),
+		URL:       "/" + site.PageName(adr.ID),
+	}
+}
+
+// handleList returns all ADRs, optionally only those in ?category= and
+// with one of the statuses in ?status=, like the list command
+func (s *adrServer) handleList(w http.ResponseWriter, r *http.Request) {
+	category := r.URL.Query().Get("category")
+	statuses := make(map[string]bool)
+	for _, value := range r.URL.Query()["status"] {
+		for _, status := range strings.Split(value, ",") {
+			statuses[strings.TrimSpace(status)] = true
+		}
+	}
+
+	s.mu.RLock()
+	lc := s.statuses
+	entries := []listEntry{}
+	for _, adr := range s.adrs {
+		if (category == "" || adr.Category == category) && (len(statuses) == 0 || statuses[adr.Status]) {
+			entries = append(entries, newListEntry(adr))
+		}
+	}
+	s.mu.RUnlock()
+
+	for status := range statuses {
+		if !lc.Valid(status) {
+			http.Error(w, fmt.Sprintf("invalid status %q: must be one of: %s", status, strings.Join(lc.Statuses, ", ")), http.StatusBadRequest)
+			return
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return model.IDLess(entries[i].ID, entries[j].ID) })
+	writeJSON(w, entries)
+}
+
+// searchResult is an ADR matching a search, best matches first
+type searchResult struct {
+	listEntry
+	Score int `json:"score"` // Number of times the terms occur
+}
+
+// handleSearch returns the ADRs containing every term of ?q=
+func (s *adrServer) handleSearch(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	results := searchADRs(s.adrs, r.URL.Query().Get("q"))
+	s.mu.RUnlock()
+	writeJSON(w, results)
+}
+
+// searchADRs returns the ADRs whose text contains every term of query,
+// ignoring case, ranked by how often the terms occur
+func searchADRs(adrs []*model.ADR, query string) []searchResult {
+	terms := strings.Fields(strings.ToLower(query))
+	results := []searchResult{}
+	if len(terms) == 0 {
+		return results
+	}
+
+	for _, adr := range adrs {
+		text := strings.ToLower(strings.Join([]string{
+			adr.ID, adr.Name, adr.Status, adr.Category,
+			strings.Join(adr.Tags, " "),
+			strings.Join(adr.Context, "\n"),
+			strings.Join(adr.Decision, "\n"),
+			strings.Join(adr.Alternatives, "\n"),
+			strings.Join(adr.Consequences, "\n"),
+		}, "\n"))
+
+		score := 0
+		for _, term := range terms {
+			n := strings.Count(text, term)
+			if n == 0 {
+				score = 0
+				break
+			}
+			score += n
+		}
+		if score > 0 {
+			results = append(results, searchResult{listEntry: newListEntry(adr), Score: score})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return model.IDLess(results[i].ID, results[j].ID)
+	})
+	return results
+}
+
+// handleEvents streams the site version, once on connect and again after
+// every change, until the client disconnects
+func (s *adrServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	for {
+		s.mu.RLock()
+		version, changed := s.version, s.changed
+		s.mu.RUnlock()
+
+		fmt.Fprintf(w, "data: %d\n\n", version)
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changed:
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// Serve runs a web server showing the decisions straight from the
+// annotations, rebuilding the pages whenever sources or the config change,
+// until ctx is cancelled
+func Serve(ctx context.Context, rootDir string, opts ServeOptions, output io.Writer) error {
+	if opts.Addr == "" {
+		opts.Addr = DefaultServeAddr
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+
+	ln, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", opts.Addr, err)
+	}
+
+	srv := newADRServer()
+	httpSrv := &http.Server{Handler: srv.handler(), ReadHeaderTimeout: 10 * time.Second}
+	defer httpSrv.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := httpSrv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- err
+			cancel()
+		}
+	}()
+
+	fmt.Fprintf(output, "Serving decisions at http://%s\n", ln.Addr())
+
+	for {
+		restart, err := runServeSession(ctx, rootDir, opts, srv, output)
+		if err == nil && restart {
+			fmt.Fprintf(output, "%sConfig changed, reloading\n", watchStamp())
+			continue
+		}
+		select {
+		case err := <-serveErr:
+			return fmt.Errorf("server failed: %w", err)
+		default:
+			return err
+		}
+	}
+}
+
+// runServeSession builds the site and rebuilds it on changes until ctx is
+// done or the config file changes. Returns true when the caller should
+// start a new session with the new config.
+func runServeSession(ctx context.Context, rootDir string, opts ServeOptions, srv *adrServer, output io.Writer) (bool, error) {
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return false, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	s := newWatchSession(rootDir, cfg, WatchOptions{Poll: opts.Poll, Interval: opts.Interval})
+	s.dates = true
+	if err := s.scanAll(); err != nil {
+		return false, err
+	}
+
+	rebuild := func() {
+		adrs, pages, err := buildSite(s.rootDir, cfg, s.annotations(), s.annotationFiles(), eventsPath)
+		if err != nil {
+			fmt.Fprintf(output, "%sError: %v\n", watchStamp(), err)
+			return
+		}
+		srv.update(adrs, cfg.Lifecycle(), pages)
+	}
+	rebuild()
+
+	w, err := watch.New(watch.Options{
+		Roots:    s.watchRoots(),
+		Ignore:   s.ignore,
+		Poll:     opts.Poll,
+		Interval: opts.Interval,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to start watcher: %w", err)
+	}
+	defer w.Close()
+
+	batches := watch.Batch(ctx, w.Events(), opts.Debounce)
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case batch, ok := <-batches:
+			if !ok {
+				if ctx.Err() != nil {
+					return false, nil
+				}
+				return false, errors.New("file watcher stopped unexpectedly")
+			}
+			for _, path := range batch {
+				switch {
+				case path == s.configPath:
+					if _, err := config.Load(s.rootDir); err != nil {
+						fmt.Fprintf(output, "%sError: failed to load config: %v\n", watchStamp(), err)
+						continue
+					}
+					return true, nil
+				case s.isRoot(path):
+					if err := s.scanAll(); err != nil {
+						fmt.Fprintf(output, "%sError: %v\n", watchStamp(), err)
+					}
+				case s.isOutput(path):
+					// ADR files only hold the dates, which the rebuild reads
+				case !s.isTemplate(path):
+					s.refresh(path, make(map[string]bool))
+				}
+			}
+			rebuild()
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weaby/adr-buddy/internal/lifecycle"
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+func TestADRServer(t *testing.T) {
+	srv := newADRServer()
+	ts := httptest.NewServer(srv.handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "nothing to show before the first build")
+
+	srv.update([]*model.ADR{
+		{ID: "adr-10", Name: "Cache", Status: "accepted", Category: "data", Context: []string{"Reads are slow, cache reads"}},
+		{ID: "adr-2", Name: "Reads", Status: "proposed", Decision: []string{"Use replicas"}},
+	}, lifecycle.Default(), map[string][]byte{"index.html": []byte("index"), "adr-2.html": []byte("page")})
+
+	body := get(t, ts.URL+"/")
+	assert.Equal(t, "index", body)
+	assert.Equal(t, "page", get(t, ts.URL+"/adr-2.html"))
+
+	resp, err = http.Get(ts.URL + "/adr-3.html")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	var list []listEntry
+	require.NoError(t, json.Unmarshal([]byte(get(t, ts.URL+"/api/adrs")), &list))
+	require.Len(t, list, 2)
+	assert.Equal(t, listEntry{ID: "adr-2", Name: "Reads", Status: "proposed", URL: "/adr-2.html"}, list[0])
+	assert.Equal(t, "adr-10", list[1].ID)
+
+	require.NoError(t, json.Unmarshal([]byte(get(t, ts.URL+"/api/adrs?category=data")), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "adr-10", list[0].ID)
+
+	require.NoError(t, json.Unmarshal([]byte(get(t, ts.URL+"/api/adrs?status=proposed")), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "adr-2", list[0].ID)
+	require.NoError(t, json.Unmarshal([]byte(get(t, ts.URL+"/api/adrs?status=proposed,accepted")), &list))
+	assert.Len(t, list, 2)
+
+	resp, err = http.Get(ts.URL + "/api/adrs?status=done")
+	require.NoError(t, err)
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Contains(t, string(data), `invalid status "done": must be one of: proposed, accepted, rejected, deprecated, superseded`)
+
+	var results []searchResult
+	require.NoError(t, json.Unmarshal([]byte(get(t, ts.URL+"/api/search?q=READS")), &results))
+	require.Len(t, results, 2)
+	assert.Equal(t, "adr-10", results[0].ID, "more matches rank first")
+	assert.Equal(t, 2, results[0].Score)
+
+	require.NoError(t, json.Unmarshal([]byte(get(t, ts.URL+"/api/search?q=reads+replicas")), &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, "adr-2", results[0].ID)
+}
+
+func TestADRServer_Events(t *testing.T) {
+	srv := newADRServer()
+	srv.update(nil, lifecycle.Default(), map[string][]byte{"index.html": []byte("a")})
+	ts := httptest.NewServer(srv.handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + eventsPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := bufio.NewReader(resp.Body)
+	assert.Equal(t, "data: 1\n", readEvent(t, events))
+
+	// An unchanged rebuild isn't reported
+	srv.update(nil, lifecycle.Default(), map[string][]byte{"index.html": []byte("a")})
+	srv.update(nil, lifecycle.Default(), map[string][]byte{"index.html": []byte("b")})
+	assert.Equal(t, "data: 2\n", readEvent(t, events))
+}
+
+func TestServe(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := filepath.Join(tmpDir, "app.go")
+	require.NoError(t, os.WriteFile(source, []byte(`// @decision.id: adr-1
+// @decision.name: Use Go
+package main
+`), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var output syncBuffer
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, tmpDir, ServeOptions{
+			Addr:     "127.0.0.1:0",
+			Poll:     true,
+			Interval: 20 * time.Millisecond,
+			Debounce: 20 * time.Millisecond,
+		}, &output)
+	}()
+
+	addrRe := regexp.MustCompile(`Serving decisions at (http://\S+)`)
+	var url string
+	require.Eventually(t, func() bool {
+		m := addrRe.FindStringSubmatch(output.String())
+		if m != nil {
+			url = m[1]
+		}
+		return m != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		resp, err := http.Get(url + "/adr-1.html")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return strings.Contains(string(body), "adr-1: Use Go") && strings.Contains(string(body), eventsPath)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Pages follow the annotations without a sync
+	require.NoError(t, os.WriteFile(source, []byte(`// @decision.id: adr-1
+// @decision.name: Use Rust
+package main
+`), 0644))
+	assert.Eventually(t, func() bool {
+		resp, err := http.Get(url + "/adr-1.html")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return strings.Contains(string(body), "adr-1: Use Rust")
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoDirExists(t, filepath.Join(tmpDir, "decisions"))
+
+	// Dates come from the ADR files, so a sync shows up too
+	var syncOutput bytes.Buffer
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &syncOutput))
+	today := time.Now().Format("2006-01-02")
+	assert.Eventually(t, func() bool {
+		resp, err := http.Get(url + "/adr-1.html")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return strings.Contains(string(body), "<span>"+today+"</span>")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve did not stop after cancel")
+	}
+}
+
+func TestServe_AddrInUse(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	err := Serve(context.Background(), t.TempDir(), ServeOptions{Addr: strings.TrimPrefix(ts.URL, "http://")}, io.Discard)
+	assert.ErrorContains(t, err, "failed to listen on")
+}
+
+func get(t *testing.T, url string) string {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+// readEvent reads the next server-sent event's data line
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	line, err := r.ReadString('\n')
+	require.NoError(t, err)
+	_, err = r.ReadString('\n') // Blank line ending the event
+	require.NoError(t, err)
+	return line
+}
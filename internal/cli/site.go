@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/model"
+	"github.com/weaby/adr-buddy/internal/site"
+	"github.com/weaby/adr-buddy/internal/template"
+	"github.com/weaby/adr-buddy/internal/txn"
+)
+
+// DefaultSiteDir is where the site is written unless another directory is given
+const DefaultSiteDir = "site"
+
+// SiteOptions controls the behavior of the site command
+type SiteOptions struct {
+	Out string // Directory to write the site to, relative to rootDir
+}
+
+// Site renders the annotated decisions as a static HTML site. Pages in the
+// output directory that adr-buddy didn't generate are left alone; generated
+// pages of decisions that no longer exist are removed.
+func Site(rootDir string, opts SiteOptions, output io.Writer) error {
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	annotations, files, err := scanAnnotationFiles(rootDir, cfg)
+	if err != nil {
+		return err
+	}
+
+	adrs, pages, err := buildSite(rootDir, cfg, annotations, files, "")
+	if err != nil {
+		return err
+	}
+
+	outDir := opts.Out
+	if outDir == "" {
+		outDir = DefaultSiteDir
+	}
+	if !filepath.IsAbs(outDir) {
+		outDir = filepath.Join(rootDir, outDir)
+	}
+
+	tx := txn.New()
+	names := make([]string, 0, len(pages))
+	for name := range pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(outDir, name)
+		data, err := os.ReadFile(path)
+		switch {
+		case err != nil:
+			tx.Write(path, pages[name], 0644)
+		case !strings.Contains(string(data), site.Marker):
+			relPath, _ := filepath.Rel(rootDir, path)
+			fmt.Fprintf(output, "Skipped: %s (not generated by adr-buddy)\n", relPath)
+		case string(data) != string(pages[name]):
+			tx.Write(path, pages[name], 0644)
+		}
+	}
+
+	// Pages of decisions that no longer exist
+	entries, err := os.ReadDir(outDir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", outDir, err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".html" || pages[entry.Name()] != nil {
+			continue
+		}
+		path := filepath.Join(outDir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if strings.Contains(string(data), site.Marker) {
+			tx.Remove(path)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write site: %w", err)
+	}
+
+	relOut, err := filepath.Rel(rootDir, outDir)
+	if err != nil {
+		relOut = outDir
+	}
+	fmt.Fprintf(output, "✓ Site generated in %s (%d decisions)\n", relOut, len(adrs))
+	return nil
+}
+
+// buildSite aggregates annotations and renders the site pages for them,
+// with snippets read from the file each annotation was found in.
+// liveReload is passed on to site.Options.
+func buildSite(rootDir string, cfg *config.Config, annotations []*model.Annotation, files map[*model.Annotation]string, liveReload string) ([]*model.ADR, map[string][]byte, error) {
+	adrs, err := model.Aggregate(annotations)
+	if err != nil {
+		return nil, nil, fmt.Errorf("aggregation failed: %w", err)
+	}
+
+	if err := loadDates(rootDir, cfg, adrs); err != nil {
+		return nil, nil, err
+	}
+
+	// Locations are relative to their scan path, not to rootDir
+	locations := make(map[model.SourceLocation]string, len(annotations))
+	for _, ann := range annotations {
+		locations[ann.Location] = files[ann]
+	}
+
+	pages, err := site.Build(adrs, site.Options{Files: locations, Locale: cfg.Locale, Lifecycle: cfg.Statuses, LiveReload: liveReload})
+	if err != nil {
+		return nil, nil, err
+	}
+	return adrs, pages, nil
+}
+
+// loadDates sets the date of each ADR to the one in its ADR file, or clears
+// it if there is no file yet. Aggregate dates every ADR today; sync only uses
+// that date when it first writes the file.
+func loadDates(rootDir string, cfg *config.Config, adrs []*model.ADR) error {
+	existing, err := scanExistingADRs(absPath(rootDir, cfg.OutputDir), cfg.ArchiveDir)
+	if err != nil {
+		return err
+	}
+	dates := make(map[string]string, len(existing))
+	for _, e := range existing {
+		if date := template.ParseExistingADR(e.Content).Frontmatter["Date"]; date != "" {
+			dates[e.ID] = date
+		}
+	}
+	for _, adr := range adrs {
+		adr.Date = dates[adr.ID]
+	}
+	return nil
+}
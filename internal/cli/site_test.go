@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSite(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(`// @decision.id: adr-1
+// @decision.name: Use Go
+// @decision.status: accepted
+package main
+`), 0644))
+
+	var output bytes.Buffer
+	require.NoError(t, Site(tmpDir, SiteOptions{Out: "public"}, &output))
+	assert.Contains(t, output.String(), "✓ Site generated in public (1 decisions)")
+
+	page, err := os.ReadFile(filepath.Join(tmpDir, "public", "adr-1.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "<h1>adr-1: Use Go</h1>")
+	assert.FileExists(t, filepath.Join(tmpDir, "public", "index.html"))
+
+	// Hand-written pages are kept, stale generated ones removed
+	handWritten := filepath.Join(tmpDir, "public", "about.html")
+	require.NoError(t, os.WriteFile(handWritten, []byte("<p>About</p>"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(`// @decision.id: adr-2
+// @decision.name: Use Rust
+package main
+`), 0644))
+
+	output.Reset()
+	require.NoError(t, Site(tmpDir, SiteOptions{Out: "public"}, &output))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "public", "adr-1.html"))
+	assert.FileExists(t, filepath.Join(tmpDir, "public", "adr-2.html"))
+	assert.FileExists(t, handWritten)
+}
+
+func TestSite_ScanPath(t *testing.T) {
+	// Locations are relative to the scan path, not to the project root
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		".adr-buddy/config.yml": "scan_paths: [src]\n",
+		"src/app.go": `package main
+
+// @decision.id: adr-1
+// @decision.name: Use Go
+func main() {}
+`,
+	})
+
+	var output bytes.Buffer
+	require.NoError(t, Site(tmpDir, SiteOptions{Out: "public"}, &output))
+
+	page, err := os.ReadFile(filepath.Join(tmpDir, "public", "adr-1.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "<header>app.go:3</header>")
+	assert.Contains(t, string(page), `<span class="k">func</span> main`)
+}
+
+func TestSite_KeepsHandWrittenIndex(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(`// @decision.id: adr-1
+// @decision.name: Use Go
+package main
+`), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "site"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "site", "index.html"), []byte("<h1>Home</h1>"), 0644))
+
+	var output bytes.Buffer
+	require.NoError(t, Site(tmpDir, SiteOptions{}, &output))
+	assert.Contains(t, output.String(), "Skipped: site/index.html (not generated by adr-buddy)")
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "site", "index.html"))
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>Home</h1>", string(data))
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/diff"
+	"github.com/weaby/adr-buddy/internal/model"
+	"github.com/weaby/adr-buddy/internal/parser"
+	"github.com/weaby/adr-buddy/internal/txn"
+)
+
+// StatusOptions controls the behavior of the status and supersede commands
+type StatusOptions struct {
+	DryRun bool // Show the changes to the source files without writing them
+}
+
+// statusDateField records when a decision last changed status
+const statusDateField = "status_date"
+
+// today returns the date transitions are recorded with. It is a variable so
+// tests can replace it.
+var today = func() string {
+	return time.Now().Format("2006-01-02")
+}
+
+// SetStatus changes the status of a decision in every annotation block of
+// it, records the date of the change and resyncs its ADR file
+func SetStatus(rootDir, id, status string, opts StatusOptions, output io.Writer) error {
+	return transition(rootDir, id, status, "", opts, output)
+}
+
+// Supersede marks a decision as superseded by another one, adding it to the
+// supersedes field of the other decision, and resyncs both ADR files
+func Supersede(rootDir, id, by string, opts StatusOptions, output io.Writer) error {
+	if by == id {
+		return fmt.Errorf("%s can't supersede itself", id)
+	}
+	return transition(rootDir, id, "superseded", by, opts, output)
+}
+
+// transition moves the decision id to status, and when by is set records
+// that by supersedes it
+func transition(rootDir, id, status, by string, opts StatusOptions, output io.Writer) error {
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	statuses := cfg.Lifecycle()
+	if err := parser.ValidateStatusIn(status, statuses.Statuses, true); err != nil {
+		return err
+	}
+	rootDir = absPath(rootDir, ".")
+
+	annotations, files, err := scanAnnotationFiles(rootDir, cfg)
+	if err != nil {
+		return err
+	}
+	anns := annotationsOf(annotations, id)
+	if len(anns) == 0 {
+		return fmt.Errorf("no annotations found for %s", id)
+	}
+	if err := statuses.CheckTransition(anns[0].DefaultStatus(), status); err != nil {
+		return fmt.Errorf("%s: %w", id, err)
+	}
+	var byAnns []*model.Annotation
+	if by != "" {
+		if byAnns = annotationsOf(annotations, by); len(byAnns) == 0 {
+			return fmt.Errorf("no annotations found for %s", by)
+		}
+	}
+
+	edits := make(map[string][]parser.FieldEdit)
+	edit := func(ann *model.Annotation, field, value string) {
+		edits[files[ann]] = append(edits[files[ann]], parser.FieldEdit{Line: ann.Location.Line, Field: field, Value: value})
+	}
+
+	// Every block gets the status, so none is left behind whichever comes
+	// first. The date goes in the first block only.
+	changed := false
+	for _, ann := range anns {
+		changed = changed || ann.Status != status
+	}
+	if changed {
+		date := today()
+		for i, ann := range anns {
+			edit(ann, "status", status)
+			if i == 0 {
+				edit(ann, statusDateField, date)
+			} else if _, ok := ann.CustomFields[statusDateField]; ok {
+				edit(ann, statusDateField, "")
+			}
+		}
+	}
+
+	if by != "" && !supersedes(byAnns, id) {
+		// Add to the first block listing superseded decisions, if any
+		target := byAnns[0]
+		for _, ann := range byAnns {
+			if _, ok := ann.CustomFields["supersedes"]; ok {
+				target = ann
+				break
+			}
+		}
+		value := target.CustomFields["supersedes"]
+		if len(model.ListValues(value)) == 0 {
+			value = id
+		} else {
+			value += ", " + id
+		}
+		edit(target, "supersedes", value)
+	}
+
+	if len(edits) == 0 {
+		if by != "" {
+			fmt.Fprintf(output, "✓ %s is already superseded by %s\n", id, by)
+		} else {
+			fmt.Fprintf(output, "✓ %s is already %s\n", id, status)
+		}
+		return nil
+	}
+
+	paths := make([]string, 0, len(edits))
+	for path := range edits {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	tx := txn.New()
+	for _, path := range paths {
+		relPath, _ := filepath.Rel(rootDir, path)
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", relPath, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", relPath, err)
+		}
+		content, err := parser.RewriteFields(path, string(data), edits[path])
+		if err != nil {
+			return fmt.Errorf("failed to update %s: %w", relPath, err)
+		}
+
+		if opts.DryRun {
+			fmt.Fprintf(output, "[DRY RUN] Would update: %s\n", relPath)
+			fmt.Fprintln(output, diff.Unified("a/"+filepath.ToSlash(relPath), "b/"+filepath.ToSlash(relPath), string(data), content))
+			continue
+		}
+		tx.Write(path, []byte(content), info.Mode().Perm())
+		fmt.Fprintf(output, "Updated: %s\n", relPath)
+	}
+
+	if opts.DryRun {
+		return nil
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write source files: %w", err)
+	}
+
+	// Resync the ADR files of the decisions that changed
+	annotations, err = scanAnnotations(rootDir, cfg)
+	if err != nil {
+		return err
+	}
+	only := map[string]bool{id: true}
+	if by != "" {
+		only[by] = true
+	}
+	result, err := syncAnnotations(rootDir, cfg, annotations, SyncOptions{Format: "text"}, only, false, output)
+	if err != nil {
+		return err
+	}
+	printApplied(result, "", output)
+
+	if by != "" {
+		fmt.Fprintf(output, "\n✓ %s is now superseded by %s\n", id, by)
+	} else {
+		fmt.Fprintf(output, "\n✓ %s is now %s\n", id, status)
+	}
+	return nil
+}
+
+// annotationsOf returns the annotations of a decision, in scan order
+func annotationsOf(annotations []*model.Annotation, id string) []*model.Annotation {
+	var anns []*model.Annotation
+	for _, ann := range annotations {
+		if ann.ID == id {
+			anns = append(anns, ann)
+		}
+	}
+	return anns
+}
+
+// supersedes reports whether any of anns lists id as superseded
+func supersedes(anns []*model.Annotation, id string) bool {
+	for _, ann := range anns {
+		for _, item := range model.ListValues(ann.CustomFields["supersedes"]) {
+			if item == id {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stubToday(t *testing.T, date string) {
+	t.Helper()
+	original := today
+	today = func() string { return date }
+	t.Cleanup(func() { today = original })
+}
+
+func TestSetStatus(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, pullFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+	stubToday(t, "2026-10-19")
+
+	var output bytes.Buffer
+	require.NoError(t, SetStatus(tmpDir, "adr-001", "accepted", StatusOptions{}, &output))
+
+	out := output.String()
+	assert.Contains(t, out, "Updated: "+filepath.Join("src", "queue.go"))
+	assert.Contains(t, out, "Updated: "+filepath.Join("src", "worker.py"))
+	assert.Contains(t, out, "Updated: "+filepath.Join("decisions", "adr-001.md"))
+	assert.Contains(t, out, "✓ adr-001 is now accepted")
+
+	queue, err := os.ReadFile(filepath.Join(tmpDir, "src", "queue.go"))
+	require.NoError(t, err)
+	assert.Equal(t, `package src
+
+// @decision.id: adr-001
+// @decision.name: Use Kafka
+// @decision.status: accepted
+// @decision.status_date: 2026-10-19
+// @decision.context: We need to publish payment events to
+//   several consumers.
+// @decision.decision: Use Kafka.
+func Publish() {}
+`, string(queue))
+
+	worker, err := os.ReadFile(filepath.Join(tmpDir, "src", "worker.py"))
+	require.NoError(t, err)
+	assert.Equal(t, `# @decision.id: adr-001
+# @decision.name: Use Kafka
+# @decision.status: accepted
+# @decision.decision: Consume with one group per service.
+def work(): pass
+`, string(worker))
+
+	adr, err := os.ReadFile(filepath.Join(tmpDir, "decisions", "adr-001.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(adr), "**Status:** accepted")
+
+	output.Reset()
+	require.NoError(t, SetStatus(tmpDir, "adr-001", "accepted", StatusOptions{}, &output))
+	assert.Equal(t, "✓ adr-001 is already accepted\n", output.String())
+}
+
+func TestSetStatus_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, pullFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+
+	var output bytes.Buffer
+	assert.ErrorContains(t, SetStatus(tmpDir, "adr-001", "done", StatusOptions{}, &output), `invalid status "done"`)
+	assert.ErrorContains(t, SetStatus(tmpDir, "adr-404", "accepted", StatusOptions{}, &output), "no annotations found for adr-404")
+	assert.ErrorContains(t, Supersede(tmpDir, "adr-001", "adr-001", StatusOptions{}, &output), "adr-001 can't supersede itself")
+}
+
+func TestSupersede(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, pullFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+	stubToday(t, "2026-10-19")
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "src", "stream.go"), []byte(`package src
+
+// @decision.id: adr-002
+// @decision.name: Use Pulsar
+// @decision.status: accepted
+// @decision.supersedes: adr-000
+// @decision.decision: Use Pulsar.
+func Stream() {}
+`), 0644))
+
+	var output bytes.Buffer
+	require.NoError(t, Supersede(tmpDir, "adr-001", "adr-002", StatusOptions{DryRun: true}, &output))
+	assert.Contains(t, output.String(), "+// @decision.supersedes: adr-000, adr-001")
+	assert.NoFileExists(t, filepath.Join(tmpDir, "decisions", "adr-002.md"))
+
+	output.Reset()
+	require.NoError(t, Supersede(tmpDir, "adr-001", "adr-002", StatusOptions{}, &output))
+	assert.Contains(t, output.String(), "Created: "+filepath.Join("decisions", "adr-002.md"))
+	assert.Contains(t, output.String(), "✓ adr-001 is now superseded by adr-002")
+
+	stream, err := os.ReadFile(filepath.Join(tmpDir, "src", "stream.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(stream), "// @decision.supersedes: adr-000, adr-001\n")
+	assert.Contains(t, string(stream), "// @decision.status: accepted\n")
+
+	queue, err := os.ReadFile(filepath.Join(tmpDir, "src", "queue.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(queue), "// @decision.status: superseded\n// @decision.status_date: 2026-10-19\n")
+
+	adr, err := os.ReadFile(filepath.Join(tmpDir, "decisions", "adr-001.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(adr), "**Status:** superseded")
+
+	output.Reset()
+	require.NoError(t, Supersede(tmpDir, "adr-001", "adr-002", StatusOptions{}, &output))
+	assert.Equal(t, "✓ adr-001 is already superseded by adr-002\n", output.String())
+}
+
+func TestSetStatus_Lifecycle(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, pullFixture)
+	require.NoError(t, SyncWithOptions(tmpDir, SyncOptions{Format: "text"}, &bytes.Buffer{}))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".adr-buddy"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".adr-buddy", "config.yml"), []byte(`statuses:
+  values: [proposed, in-review, accepted, rejected]
+  transitions:
+    proposed: [in-review]
+    in-review: [accepted, rejected]
+`), 0644))
+
+	var output bytes.Buffer
+	assert.ErrorContains(t, SetStatus(tmpDir, "adr-001", "accepted", StatusOptions{}, &output), "adr-001: can't change status from proposed to accepted: proposed may only change to in-review")
+	assert.ErrorContains(t, Supersede(tmpDir, "adr-001", "adr-002", StatusOptions{}, &output), `invalid status "superseded"`)
+	require.NoError(t, SetStatus(tmpDir, "adr-001", "in-review", StatusOptions{}, &output))
+	assert.Contains(t, output.String(), "✓ adr-001 is now in-review")
+}
@@ -2,17 +2,31 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 
 	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/diff"
 	"github.com/weaby/adr-buddy/internal/model"
 	"github.com/weaby/adr-buddy/internal/parser"
 	"github.com/weaby/adr-buddy/internal/template"
+	"github.com/weaby/adr-buddy/internal/txn"
 )
 
+// SyncOptions controls the behavior of a sync run
+type SyncOptions struct {
+	DryRun bool   // Report changes without writing files
+	Format string // Output format: text or json
+	Prune  bool   // Delete orphaned ADR files regardless of orphan_policy
+	Check  bool   // Dry run that fails with ErrOutOfDate if any file would change
+}
+
+// ErrOutOfDate is returned by a check run when ADR files don't match annotations
+var ErrOutOfDate = errors.New("ADR files are out of date")
+
 // SyncCommand scans code and generates/updates ADR files (text output)
 func SyncCommand(rootDir string, dryRun bool, strict bool) error {
 	return SyncWithFormat(rootDir, dryRun, "text", os.Stdout)
@@ -20,17 +34,69 @@ func SyncCommand(rootDir string, dryRun bool, strict bool) error {
 
 // SyncWithFormat scans and syncs with specified output format
 func SyncWithFormat(rootDir string, dryRun bool, format string, output io.Writer) error {
+	return SyncWithOptions(rootDir, SyncOptions{DryRun: dryRun, Format: format}, output)
+}
+
+// SyncWithOptions scans and syncs using the given options
+func SyncWithOptions(rootDir string, opts SyncOptions, output io.Writer) error {
+	format := opts.Format
+
+	// Check runs only print the summary of offending paths
+	verbose := format == "text" && !opts.Check
+
 	cfg, err := config.Load(rootDir)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	if format == "text" {
+	if verbose {
 		fmt.Fprintln(output, "Scanning for annotations...")
 	}
 
-	// Scan all configured paths
-	var allAnnotations []*model.Annotation
+	allAnnotations, err := scanAnnotations(rootDir, cfg)
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		fmt.Fprintf(output, "Found %d annotation(s)\n", len(allAnnotations))
+		if len(allAnnotations) == 0 {
+			fmt.Fprintln(output, "No annotations found.")
+		}
+	}
+
+	result, err := syncAnnotations(rootDir, cfg, allAnnotations, opts, nil, verbose, output)
+	if err != nil {
+		return err
+	}
+
+	// Output based on format
+	if format == "json" {
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			return err
+		}
+		if opts.Check && result.ChangesDetected {
+			return ErrOutOfDate
+		}
+		return nil
+	}
+
+	if opts.Check {
+		return printCheckSummary(result, output)
+	}
+
+	if !opts.DryRun {
+		fmt.Fprintln(output, "\n✓ Sync complete")
+	}
+
+	return nil
+}
+
+// scanAnnotations scans every configured path for annotations
+func scanAnnotations(rootDir string, cfg *config.Config) ([]*model.Annotation, error) {
+	var all []*model.Annotation
 	for _, scanPath := range cfg.ScanPaths {
 		absPath := scanPath
 		if !filepath.IsAbs(scanPath) {
@@ -39,51 +105,33 @@ func SyncWithFormat(rootDir string, dryRun bool, format string, output io.Writer
 
 		annotations, err := parser.ScanDirectory(absPath, cfg.Exclude)
 		if err != nil {
-			return fmt.Errorf("failed to scan %s: %w", scanPath, err)
+			return nil, fmt.Errorf("failed to scan %s: %w", scanPath, err)
 		}
-		allAnnotations = append(allAnnotations, annotations...)
+		all = append(all, annotations...)
 	}
+	return all, nil
+}
 
-	if format == "text" {
-		fmt.Fprintf(output, "Found %d annotation(s)\n", len(allAnnotations))
-	}
-
-	if len(allAnnotations) == 0 {
-		if format == "json" {
-			result := &model.SyncResult{
-				ChangesDetected: false,
-				Files: model.FileChanges{
-					Created:  []string{},
-					Modified: []string{},
-					Deleted:  []string{},
-				},
-				ADRs: []model.ADRChange{},
-			}
-			encoder := json.NewEncoder(output)
-			encoder.SetIndent("", "  ")
-			return encoder.Encode(result)
-		}
-		fmt.Fprintln(output, "No annotations found.")
-		return nil
-	}
+// syncAnnotations aggregates annotations into ADRs and brings the output
+// directory in line with them. When only is non-nil, just the ADRs with those
+// IDs are rendered and only their orphaned files are handled.
+func syncAnnotations(rootDir string, cfg *config.Config, annotations []*model.Annotation, opts SyncOptions, only map[string]bool, verbose bool, output io.Writer) (*model.SyncResult, error) {
+	dryRun := opts.DryRun || opts.Check
 
 	// Aggregate into ADRs
-	adrs, err := model.Aggregate(allAnnotations)
+	adrs, err := model.Aggregate(annotations)
 	if err != nil {
-		return fmt.Errorf("aggregation failed: %w", err)
+		return nil, fmt.Errorf("aggregation failed: %w", err)
 	}
 
-	if format == "text" {
+	if verbose && len(adrs) > 0 {
 		fmt.Fprintf(output, "Generated %d ADR(s)\n\n", len(adrs))
 	}
 
 	// Load template
-	tmplStr := template.DefaultTemplate()
-	if cfg.Template != "" {
-		customTmpl, err := os.ReadFile(filepath.Join(rootDir, cfg.Template))
-		if err == nil {
-			tmplStr = string(customTmpl)
-		}
+	templates, err := loadTemplates(rootDir, cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	// Generate files
@@ -97,83 +145,298 @@ func SyncWithFormat(rootDir string, dryRun bool, format string, output io.Writer
 		Files: model.FileChanges{
 			Created:  []string{},
 			Modified: []string{},
+			Moved:    []model.FileMove{},
 			Deleted:  []string{},
 		},
 		ADRs: []model.ADRChange{},
 	}
 
+	// Index ADR files already on disk so moved and orphaned ones can be detected
+	existing, err := scanExistingADRs(outputDir, cfg.ArchiveDir)
+	if err != nil {
+		return nil, err
+	}
+	// Final content of every ADR file, for the index
+	adrFiles := indexADRs(existing)
+
+	existingByID := make(map[string][]existingADR)
+	for _, e := range existing {
+		existingByID[e.ID] = append(existingByID[e.ID], e)
+	}
+
+	// Paths accounted for by current ADRs (their output paths and move sources)
+	claimed := make(map[string]bool, len(adrs))
+
+	// File changes are staged and only applied once every ADR has rendered
+	tx := txn.New()
+	var movedFrom []string
+
 	for _, adr := range adrs {
 		outputPath := filepath.Join(outputDir, adr.OutputPath(""))
 		relPath, _ := filepath.Rel(rootDir, outputPath)
+		claimed[outputPath] = true
 
-		var action string
+		// ADRs outside the requested set keep every file carrying their ID
+		if only != nil && !only[adr.ID] {
+			for _, e := range existingByID[adr.ID] {
+				claimed[e.Path] = true
+			}
+			continue
+		}
+
+		// When the output path is free but a file for this ID exists elsewhere,
+		// the ADR changed category and its file must move
+		sourcePath := outputPath
+		var action, oldRelPath string
 		if _, err := os.Stat(outputPath); err == nil {
 			action = "update"
-			result.Files.Modified = append(result.Files.Modified, relPath)
+		} else if moved := findMoveSource(existingByID[adr.ID], outputPath); moved != "" {
+			action = "move"
+			sourcePath = moved
+			claimed[moved] = true
+			oldRelPath, _ = filepath.Rel(rootDir, moved)
 		} else {
 			action = "create"
-			result.Files.Created = append(result.Files.Created, relPath)
 		}
 
-		result.ADRs = append(result.ADRs, model.ADRChange{
+		// Render in memory, merging with the existing file if there is one
+		var content, existingContent string
+		if data, err := os.ReadFile(sourcePath); err == nil {
+			existingContent = string(data)
+			content, err = template.MergeWithOptions(adr, existingContent, templates.forADR(adr), templates.opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to merge %s: %w", sourcePath, err)
+			}
+		} else {
+			content, err = template.RenderWithOptions(adr, templates.forADR(adr), templates.opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render %s: %w", outputPath, err)
+			}
+		}
+
+		adrFiles[outputPath] = content
+		if action == "move" {
+			delete(adrFiles, sourcePath)
+		}
+
+		if action == "update" && content == existingContent {
+			if verbose {
+				fmt.Fprintf(output, "Unchanged: %s\n", relPath)
+			}
+			continue
+		}
+
+		change := model.ADRChange{
 			ID:       adr.ID,
 			Name:     adr.Name,
 			Action:   action,
 			FilePath: relPath,
-		})
+			OldPath:  oldRelPath,
+		}
+
+		switch action {
+		case "create":
+			result.Files.Created = append(result.Files.Created, relPath)
+		case "update":
+			result.Files.Modified = append(result.Files.Modified, relPath)
+		case "move":
+			result.Files.Moved = append(result.Files.Moved, model.FileMove{
+				From: oldRelPath,
+				To:   relPath,
+			})
+		}
 
 		if dryRun {
-			if format == "text" {
-				fmt.Fprintf(output, "[DRY RUN] Would write: %s\n", relPath)
+			fromName := "/dev/null"
+			switch action {
+			case "update":
+				fromName = "a/" + filepath.ToSlash(relPath)
+			case "move":
+				fromName = "a/" + filepath.ToSlash(oldRelPath)
+			}
+			change.Diff = diff.Unified(fromName, "b/"+filepath.ToSlash(relPath), existingContent, content)
+			result.ADRs = append(result.ADRs, change)
+
+			if verbose {
+				if action == "move" {
+					fmt.Fprintf(output, "[DRY RUN] Would move: %s -> %s\n", oldRelPath, relPath)
+				} else {
+					fmt.Fprintf(output, "[DRY RUN] Would %s: %s\n", action, relPath)
+				}
+				fmt.Fprintln(output, change.Diff)
 			}
 			continue
 		}
 
-		// Check if file exists
-		var content string
-		if existingContent, err := os.ReadFile(outputPath); err == nil {
-			// Merge with existing
-			content, err = template.Merge(adr, string(existingContent), tmplStr)
-			if err != nil {
-				return fmt.Errorf("failed to merge %s: %w", outputPath, err)
+		result.ADRs = append(result.ADRs, change)
+
+		tx.Write(outputPath, []byte(content), 0644)
+		if action == "move" {
+			tx.Remove(sourcePath)
+			movedFrom = append(movedFrom, sourcePath)
+		}
+	}
+
+	// Handle ADR files whose ID no longer appears in any annotation
+	var orphans []existingADR
+	for _, e := range existing {
+		if !claimed[e.Path] && (only == nil || only[e.ID]) {
+			orphans = append(orphans, e)
+		}
+	}
+
+	policy := cfg.OrphanPolicy
+	if opts.Prune {
+		policy = config.OrphanPolicyDelete
+	}
+
+	for _, o := range orphans {
+		relPath, _ := filepath.Rel(rootDir, o.Path)
+
+		action := orphanAction(o, policy)
+		switch action {
+		case "":
+			if verbose {
+				fmt.Fprintf(output, "Orphaned: %s (no annotations reference %s)\n", relPath, o.ID)
 			}
-			if format == "text" {
-				fmt.Fprintf(output, "Updated: %s\n", relPath)
+			continue
+		case "deprecate":
+			result.Files.Modified = append(result.Files.Modified, relPath)
+			adrFiles[o.Path] = orphanContent(o, action, templates.opts)
+		default:
+			result.Files.Deleted = append(result.Files.Deleted, relPath)
+			delete(adrFiles, o.Path)
+		}
+
+		change := model.ADRChange{
+			ID:       o.ID,
+			Name:     o.Name,
+			Action:   action,
+			FilePath: relPath,
+		}
+
+		if dryRun {
+			// Archiving is a pure rename, so only deprecate and delete have a diff
+			if action != "archive" {
+				toName := "/dev/null"
+				if action == "deprecate" {
+					toName = "b/" + filepath.ToSlash(relPath)
+				}
+				change.Diff = diff.Unified("a/"+filepath.ToSlash(relPath), toName, o.Content, orphanContent(o, action, templates.opts))
 			}
-		} else {
-			// Render new
-			content, err = template.Render(adr, tmplStr)
-			if err != nil {
-				return fmt.Errorf("failed to render %s: %w", outputPath, err)
+			result.ADRs = append(result.ADRs, change)
+
+			if verbose {
+				fmt.Fprintf(output, "[DRY RUN] Would %s orphan: %s\n", action, relPath)
+				if change.Diff != "" {
+					fmt.Fprintln(output, change.Diff)
+				}
 			}
-			if format == "text" {
-				fmt.Fprintf(output, "Created: %s\n", relPath)
+			continue
+		}
+
+		stageOrphanAction(tx, o, action, outputDir, cfg.ArchiveDir, templates.opts)
+		result.ADRs = append(result.ADRs, change)
+	}
+
+	if cfg.Index.Enabled {
+		changes, skipped, err := indexChanges(rootDir, outputDir, cfg, adrFiles, adrs, templates.opts)
+		if err != nil {
+			return nil, err
+		}
+		if verbose {
+			printSkippedIndexes(rootDir, skipped, output)
+		}
+		recordIndexChanges(result, tx, rootDir, changes, dryRun)
+		if dryRun && verbose {
+			for _, change := range result.ADRs[len(result.ADRs)-len(changes):] {
+				fmt.Fprintf(output, "[DRY RUN] Would %s: %s\n", change.Action, change.FilePath)
+				fmt.Fprintln(output, change.Diff)
 			}
 		}
+	}
 
-		// Create directory if needed
-		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
-			return fmt.Errorf("failed to create directory: %w", err)
+	// Everything is rendered; apply all file changes as a single unit
+	if !dryRun {
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("failed to apply ADR changes: %w", err)
+		}
+		for _, path := range movedFrom {
+			removeEmptyDir(filepath.Dir(path), outputDir)
 		}
+		if verbose {
+			printApplied(result, "", output)
+		}
+	}
+
+	result.ChangesDetected = len(result.Files.Created) > 0 ||
+		len(result.Files.Modified) > 0 ||
+		len(result.Files.Moved) > 0 ||
+		len(result.Files.Deleted) > 0
+
+	return result, nil
+}
 
-		// Write file
-		if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
-			return fmt.Errorf("failed to write %s: %w", outputPath, err)
+// findMoveSource returns the path of an existing file for the same ADR ID
+// that lives somewhere other than outputPath, or "" if there is none
+func findMoveSource(candidates []existingADR, outputPath string) string {
+	for _, c := range candidates {
+		if c.Path != outputPath {
+			return c.Path
 		}
 	}
+	return ""
+}
+
+// removeEmptyDir removes dir if it is empty and not the output directory.
+// Used to clean up category directories left behind by moved ADRs.
+func removeEmptyDir(dir, outputDir string) {
+	if dir != outputDir {
+		// Fails harmlessly when other files remain
+		os.Remove(dir)
+	}
+}
 
-	result.ChangesDetected = len(result.Files.Created) > 0 || len(result.Files.Modified) > 0
+// printApplied reports the file changes a sync applied, starting each
+// line with prefix
+func printApplied(result *model.SyncResult, prefix string, output io.Writer) {
+	for _, change := range result.ADRs {
+		// Index files aren't ADRs, so a removed one is no orphan
+		if change.ID == "" && change.Action == "delete" {
+			fmt.Fprintf(output, "%sRemoved: %s\n", prefix, change.FilePath)
+			continue
+		}
 
-	// Output based on format
-	if format == "json" {
-		encoder := json.NewEncoder(output)
-		encoder.SetIndent("", "  ")
-		return encoder.Encode(result)
+		switch change.Action {
+		case "create":
+			fmt.Fprintf(output, "%sCreated: %s\n", prefix, change.FilePath)
+		case "update":
+			fmt.Fprintf(output, "%sUpdated: %s\n", prefix, change.FilePath)
+		case "move":
+			fmt.Fprintf(output, "%sMoved: %s -> %s\n", prefix, change.OldPath, change.FilePath)
+		default:
+			fmt.Fprintf(output, "%sOrphan (%s): %s\n", prefix, change.Action, change.FilePath)
+		}
 	}
+}
 
-	if !dryRun {
-		fmt.Fprintln(output, "\n✓ Sync complete")
+// printCheckSummary lists the files a sync would change and returns
+// ErrOutOfDate if there are any
+func printCheckSummary(result *model.SyncResult, output io.Writer) error {
+	if !result.ChangesDetected {
+		fmt.Fprintln(output, "✓ ADR files are up to date")
+		return nil
 	}
 
-	return nil
+	fmt.Fprintln(output, "ADR files are out of date:")
+	for _, change := range result.ADRs {
+		if change.Action == "move" {
+			fmt.Fprintf(output, "  %-10s %s -> %s\n", change.Action, change.OldPath, change.FilePath)
+		} else {
+			fmt.Fprintf(output, "  %-10s %s\n", change.Action, change.FilePath)
+		}
+	}
+	fmt.Fprintln(output, "\nRun 'adr-buddy sync' to update them.")
+
+	return ErrOutOfDate
 }
@@ -3,8 +3,10 @@ package cli
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -166,3 +168,454 @@ const x = 1;`
 	assert.True(t, result.ChangesDetected)
 	assert.Equal(t, 1, len(result.Files.Created))
 }
+
+// orphanFixture is an ADR file whose annotation is gone, next to a kept
+// decision and a hand-written document that must never be touched
+var orphanFixture = map[string]string{
+	"decisions/adr-9.md": `# adr-9: Removed Decision
+
+**Status:** accepted
+**Date:** 2026-01-10
+
+## Context
+Manual context.
+`,
+	"decisions/notes.md": "# Notes\n",
+	"test.js": `// @decision.id: adr-1
+// @decision.name: Kept Decision
+const x = 1;
+`,
+}
+
+func TestSync_OrphanKeptByDefault(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, orphanFixture)
+	orphanPath := filepath.Join(tmpDir, "decisions", "adr-9.md")
+
+	var buf bytes.Buffer
+	err := SyncWithFormat(tmpDir, false, "text", &buf)
+	assert.NoError(t, err)
+
+	assert.FileExists(t, orphanPath)
+	assert.Contains(t, buf.String(), "Orphaned: decisions/adr-9.md")
+}
+
+func TestSync_OrphanMarkDeprecated(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, orphanFixture)
+	writeFiles(t, tmpDir, map[string]string{".adr-buddy/config.yml": "orphan_policy: mark-deprecated\n"})
+	orphanPath := filepath.Join(tmpDir, "decisions", "adr-9.md")
+
+	var buf bytes.Buffer
+	err := SyncWithFormat(tmpDir, false, "json", &buf)
+	assert.NoError(t, err)
+
+	var result model.SyncResult
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Contains(t, result.Files.Modified, "decisions/adr-9.md")
+	assert.Empty(t, result.Files.Deleted)
+
+	content, err := os.ReadFile(orphanPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "**Status:** deprecated")
+	assert.Contains(t, string(content), "Manual context.")
+
+	// Second run is a no-op for the already deprecated orphan
+	buf.Reset()
+	err = SyncWithFormat(tmpDir, false, "json", &buf)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.NotContains(t, result.Files.Modified, "decisions/adr-9.md")
+}
+
+func TestSync_OrphanArchive(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, orphanFixture)
+	writeFiles(t, tmpDir, map[string]string{".adr-buddy/config.yml": "orphan_policy: archive\n"})
+	orphanPath := filepath.Join(tmpDir, "decisions", "adr-9.md")
+
+	var buf bytes.Buffer
+	err := SyncWithFormat(tmpDir, false, "json", &buf)
+	assert.NoError(t, err)
+
+	var result model.SyncResult
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, []string{"decisions/adr-9.md"}, result.Files.Deleted)
+
+	assert.NoFileExists(t, orphanPath)
+	assert.FileExists(t, filepath.Join(tmpDir, "decisions", "archive", "adr-9.md"))
+	assert.FileExists(t, filepath.Join(tmpDir, "decisions", "notes.md"))
+
+	// Archived files are not orphans themselves
+	buf.Reset()
+	err = SyncWithFormat(tmpDir, false, "json", &buf)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Empty(t, result.Files.Deleted)
+}
+
+func TestSync_Prune(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, orphanFixture)
+	orphanPath := filepath.Join(tmpDir, "decisions", "adr-9.md")
+
+	// Dry run reports the deletion without touching the file
+	var buf bytes.Buffer
+	err := SyncWithOptions(tmpDir, SyncOptions{DryRun: true, Format: "json", Prune: true}, &buf)
+	assert.NoError(t, err)
+
+	var result model.SyncResult
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.True(t, result.ChangesDetected)
+	assert.Equal(t, []string{"decisions/adr-9.md"}, result.Files.Deleted)
+	assert.FileExists(t, orphanPath)
+
+	buf.Reset()
+	err = SyncWithOptions(tmpDir, SyncOptions{Format: "text", Prune: true}, &buf)
+	assert.NoError(t, err)
+	assert.NoFileExists(t, orphanPath)
+	assert.FileExists(t, filepath.Join(tmpDir, "decisions", "notes.md"))
+}
+
+func TestSync_CategoryMove(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	oldDir := filepath.Join(tmpDir, "decisions", "backend")
+	assert.NoError(t, os.MkdirAll(oldDir, 0755))
+
+	oldADR := `# adr-3: Message Broker
+
+**Status:** proposed
+**Date:** 2026-01-10
+**Category:** backend
+
+## Context
+<!-- TODO: Add context - what is the issue we're facing? -->
+
+## Decision
+Hand-written decision that must survive the move.
+
+## Consequences
+<!-- TODO: What are the positive/negative outcomes? -->
+
+## Code Locations
+- broker.go:1
+`
+	oldPath := filepath.Join(oldDir, "adr-3.md")
+	assert.NoError(t, os.WriteFile(oldPath, []byte(oldADR), 0644))
+
+	source := `// @decision.id: adr-3
+// @decision.name: Message Broker
+// @decision.category: infrastructure
+const x = 1;
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "broker.js"), []byte(source), 0644))
+
+	// Dry run reports the move without touching files
+	var buf bytes.Buffer
+	err := SyncWithFormat(tmpDir, true, "json", &buf)
+	assert.NoError(t, err)
+
+	var result model.SyncResult
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.True(t, result.ChangesDetected)
+	assert.Empty(t, result.Files.Created)
+	assert.Empty(t, result.Files.Deleted)
+	assert.Equal(t, []model.FileMove{{
+		From: "decisions/backend/adr-3.md",
+		To:   "decisions/infrastructure/adr-3.md",
+	}}, result.Files.Moved)
+	assert.Len(t, result.ADRs, 1)
+	assert.Equal(t, "move", result.ADRs[0].Action)
+	assert.Equal(t, "decisions/backend/adr-3.md", result.ADRs[0].OldPath)
+	assert.FileExists(t, oldPath)
+
+	buf.Reset()
+	err = SyncWithFormat(tmpDir, false, "text", &buf)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "Moved: decisions/backend/adr-3.md -> decisions/infrastructure/adr-3.md")
+
+	assert.NoFileExists(t, oldPath)
+	assert.NoDirExists(t, oldDir)
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "decisions", "infrastructure", "adr-3.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "**Category:** infrastructure")
+	assert.Contains(t, string(content), "**Date:** 2026-01-10")
+	assert.Contains(t, string(content), "Hand-written decision that must survive the move.")
+}
+
+func TestSync_Idempotent(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	source := `// @decision.id: adr-1
+// @decision.name: Stable Decision
+// @decision.context: Nothing changes
+const x = 1;
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "test.js"), []byte(source), 0644))
+
+	var buf bytes.Buffer
+	assert.NoError(t, SyncWithFormat(tmpDir, false, "text", &buf))
+
+	buf.Reset()
+	assert.NoError(t, SyncWithFormat(tmpDir, true, "json", &buf))
+
+	var result model.SyncResult
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.False(t, result.ChangesDetected)
+	assert.Empty(t, result.Files.Modified)
+	assert.Empty(t, result.ADRs)
+}
+
+func TestSync_DryRunDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	decisionsDir := filepath.Join(tmpDir, "decisions")
+	assert.NoError(t, os.MkdirAll(decisionsDir, 0755))
+
+	source := `// @decision.id: adr-1
+// @decision.name: Test Decision
+// @decision.status: proposed
+const x = 1;
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "test.js"), []byte(source), 0644))
+	assert.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+
+	// Change the status and preview
+	source = strings.Replace(source, "proposed", "accepted", 1)
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "test.js"), []byte(source), 0644))
+
+	var buf bytes.Buffer
+	assert.NoError(t, SyncWithFormat(tmpDir, true, "json", &buf))
+
+	var result model.SyncResult
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Len(t, result.ADRs, 1)
+	assert.Equal(t, "update", result.ADRs[0].Action)
+	assert.Contains(t, result.ADRs[0].Diff, "--- a/decisions/adr-1.md\n+++ b/decisions/adr-1.md\n")
+	assert.Contains(t, result.ADRs[0].Diff, "-**Status:** proposed\n+**Status:** accepted\n")
+
+	buf.Reset()
+	assert.NoError(t, SyncWithFormat(tmpDir, true, "text", &buf))
+	assert.Contains(t, buf.String(), "[DRY RUN] Would update: decisions/adr-1.md")
+	assert.Contains(t, buf.String(), "+**Status:** accepted")
+
+	// Nothing was written
+	content, err := os.ReadFile(filepath.Join(decisionsDir, "adr-1.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "**Status:** proposed")
+}
+
+func TestSync_Check(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	source := `// @decision.id: adr-1
+// @decision.name: Checked Decision
+const x = 1;
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "test.js"), []byte(source), 0644))
+
+	var buf bytes.Buffer
+	err := SyncWithOptions(tmpDir, SyncOptions{Format: "text", Check: true}, &buf)
+	assert.ErrorIs(t, err, ErrOutOfDate)
+	assert.Contains(t, buf.String(), "create     decisions/adr-1.md")
+	assert.NotContains(t, buf.String(), "Scanning")
+	assert.NoFileExists(t, filepath.Join(tmpDir, "decisions", "adr-1.md"))
+
+	assert.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+
+	buf.Reset()
+	err = SyncWithOptions(tmpDir, SyncOptions{Format: "json", Check: true}, &buf)
+	assert.NoError(t, err)
+
+	var result model.SyncResult
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.False(t, result.ChangesDetected)
+}
+
+func TestSync_TemplateErrorWritesNothing(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configDir := filepath.Join(tmpDir, ".adr-buddy")
+	assert.NoError(t, os.MkdirAll(configDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yml"),
+		[]byte("template: .adr-buddy/template.md\n"), 0644))
+
+	// Executing .Missing fails, but only for adr-5
+	tmpl := `# {{.ID}}: {{.Name}}
+{{if eq .ID "adr-5"}}{{.Missing}}{{end}}
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(configDir, "template.md"), []byte(tmpl), 0644))
+
+	decisionsDir := filepath.Join(tmpDir, "decisions")
+	assert.NoError(t, os.MkdirAll(decisionsDir, 0755))
+	existingPath := filepath.Join(decisionsDir, "adr-1.md")
+	assert.NoError(t, os.WriteFile(existingPath, []byte("# adr-1: Old Name\n"), 0644))
+
+	var source strings.Builder
+	for i := 1; i <= 5; i++ {
+		fmt.Fprintf(&source, "// @decision.id: adr-%d\n// @decision.name: Decision %d\nconst x%d = 1;\n\n", i, i, i)
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "test.js"), []byte(source.String()), 0644))
+
+	err := SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{})
+	assert.Error(t, err)
+
+	// The existing file is untouched and no other ADR was written
+	content, err := os.ReadFile(existingPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "# adr-1: Old Name\n", string(content))
+
+	entries, err := os.ReadDir(decisionsDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestSync_TemplatePreset(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configDir := filepath.Join(tmpDir, ".adr-buddy")
+	assert.NoError(t, os.MkdirAll(configDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yml"), []byte("template: preset:madr\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(`// @decision.id: adr-1
+// @decision.name: Use Pino
+// @decision.context: Need structured logs
+package main
+`), 0644))
+
+	assert.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+
+	adrPath := filepath.Join(tmpDir, "decisions", "adr-1.md")
+	content, err := os.ReadFile(adrPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "## Context and Problem Statement")
+
+	// Hand-written outcome survives a later sync
+	edited := strings.Replace(string(content), "<!-- TODO: Chosen option, because justification. -->", "Chosen option: Pino.", 1)
+	assert.NoError(t, os.WriteFile(adrPath, []byte(edited), 0644))
+	assert.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+
+	content, err = os.ReadFile(adrPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "Chosen option: Pino.")
+	assert.Contains(t, string(content), "Need structured logs")
+}
+
+func TestSync_FrontMatter(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configDir := filepath.Join(tmpDir, ".adr-buddy")
+	assert.NoError(t, os.MkdirAll(configDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yml"), []byte("front_matter: true\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "bus.go"), []byte(`// @decision.id: adr-7
+// @decision.name: Event bus
+// @decision.tags: messaging, kafka
+// @decision.supersedes: adr-2
+package bus
+`), 0644))
+
+	assert.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "decisions", "adr-7.md"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "---\nid: adr-7\n"))
+	assert.Contains(t, string(content), "tags:\n    - messaging\n    - kafka\n")
+	assert.Contains(t, string(content), "supersedes:\n        - adr-2\n")
+
+	// A second sync finds nothing to change
+	var output bytes.Buffer
+	assert.NoError(t, SyncWithFormat(tmpDir, true, "json", &output))
+	var result model.SyncResult
+	assert.NoError(t, json.Unmarshal(output.Bytes(), &result))
+	assert.False(t, result.ChangesDetected)
+}
+
+func TestSync_Locale(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configDir := filepath.Join(tmpDir, ".adr-buddy")
+	assert.NoError(t, os.MkdirAll(configDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yml"), []byte("locale: de\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(`// @decision.id: adr-1
+// @decision.name: Ereignisbus
+// @decision.status: accepted
+package main
+`), 0644))
+
+	assert.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+
+	path := filepath.Join(tmpDir, "decisions", "adr-1.md")
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "**Status:** angenommen\n")
+	assert.Contains(t, string(content), "## Kontext\n")
+
+	// Hand-written content under the localised heading survives a re-sync
+	edited := strings.Replace(string(content), "<!-- OFFEN: Kontext beschreiben - vor welchem Problem stehen wir? -->", "Von Hand geschrieben.", 1)
+	assert.NoError(t, os.WriteFile(path, []byte(edited), 0644))
+	assert.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+
+	content, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "Von Hand geschrieben.")
+}
+
+func TestSync_TemplatePerCategoryAndStatus(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configDir := filepath.Join(tmpDir, ".adr-buddy")
+	assert.NoError(t, os.MkdirAll(configDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(configDir, "security.md"),
+		[]byte("# {{.ID}}: {{.Name}}\n\n## Threat Model\n<!-- TODO: threats -->\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(configDir, "rejected.md"),
+		[]byte("# {{.ID}}: {{.Name}}\n\nRejected.\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yml"), []byte(`templates:
+  categories:
+    security: .adr-buddy/security.md
+  statuses:
+    rejected: .adr-buddy/rejected.md
+`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte(`// @decision.id: adr-1
+// @decision.name: Token storage
+// @decision.category: security
+package main
+
+// @decision.id: adr-2
+// @decision.name: Plain cookies
+// @decision.category: security
+// @decision.status: rejected
+var a = 1
+
+// @decision.id: adr-3
+// @decision.name: Logging
+var b = 1
+`), 0644))
+
+	assert.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+
+	read := func(rel string) string {
+		content, err := os.ReadFile(filepath.Join(tmpDir, "decisions", rel))
+		assert.NoError(t, err)
+		return string(content)
+	}
+	assert.Contains(t, read("security/adr-1.md"), "## Threat Model")
+	assert.Equal(t, "# adr-2: Plain cookies\n\nRejected.\n", read("security/adr-2.md"))
+	assert.Contains(t, read("adr-3.md"), "## Context")
+}
+
+func TestSync_MissingTemplateFails(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configDir := filepath.Join(tmpDir, ".adr-buddy")
+	assert.NoError(t, os.MkdirAll(configDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yml"), []byte("template: .adr-buddy/gone.md\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.go"), []byte("// @decision.id: adr-1\n// @decision.name: X\n"), 0644))
+
+	err := SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "gone.md")
+
+	_, statErr := os.Stat(filepath.Join(tmpDir, "decisions"))
+	assert.True(t, os.IsNotExist(statErr))
+}
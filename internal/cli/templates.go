@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/model"
+	"github.com/weaby/adr-buddy/internal/template"
+)
+
+// partialsDir holds the named templates other templates can include or extend
+var partialsDir = filepath.Join(".adr-buddy", "templates")
+
+// templateSet holds the templates a sync run renders with, keyed by setting
+type templateSet struct {
+	cfg       *config.Config
+	templates map[string]string
+	opts      template.Options
+}
+
+// partials holds the templates in partialsDir, keyed by name
+type partials struct {
+	sources map[string]string
+	files   map[string]string // Paths relative to the project root, for reporting
+}
+
+// loadTemplates reads and validates every template the config refers to.
+// A missing, unreadable or invalid template is an error rather than a
+// silent fallback, and is reported before any ADR is rendered.
+func loadTemplates(rootDir string, cfg *config.Config) (*templateSet, error) {
+	parts, err := loadPartials(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &templateSet{
+		cfg:       cfg,
+		templates: map[string]string{"": template.DefaultTemplate()},
+		opts:      template.Options{FrontMatter: cfg.FrontMatter, Partials: parts.sources, Locale: cfg.Locale, Lifecycle: cfg.Statuses},
+	}
+
+	for _, setting := range cfg.ADRTemplates() {
+		if setting == "" {
+			continue
+		}
+		tmplStr, err := config.ReadTemplate(rootDir, setting)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkTemplate(setting, tmplStr, set.opts, parts); err != nil {
+			return nil, err
+		}
+		set.templates[setting] = tmplStr
+	}
+
+	return set, nil
+}
+
+// forADR returns the template for an ADR's category and status
+func (s *templateSet) forADR(adr *model.ADR) string {
+	return s.templates[s.cfg.TemplateFor(adr.Category, adr.Status)]
+}
+
+// loadPartials reads the templates in partialsDir, naming each after its
+// file without the extension, e.g. "header" for header.md. A missing
+// directory means there are none.
+func loadPartials(rootDir string) (*partials, error) {
+	parts := &partials{sources: make(map[string]string), files: make(map[string]string)}
+
+	entries, err := os.ReadDir(filepath.Join(rootDir, partialsDir))
+	if os.IsNotExist(err) {
+		return parts, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template partials: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		file := filepath.Join(partialsDir, entry.Name())
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if other, ok := parts.files[name]; ok {
+			return nil, fmt.Errorf("template partials %s and %s have the same name %q", other, file, name)
+		}
+
+		data, err := os.ReadFile(filepath.Join(rootDir, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template partial: %w", err)
+		}
+		parts.sources[name] = string(data)
+		parts.files[name] = file
+	}
+
+	return parts, nil
+}
+
+// where names the file a problem is in: the template, or one of its partials
+func (p *partials) where(name string, problem template.Problem) string {
+	if file, ok := p.files[problem.Template]; ok {
+		return file
+	}
+	return name
+}
+
+// checkTemplate returns an error listing the errors template.Validate finds
+func checkTemplate(setting, tmplStr string, opts template.Options, parts *partials) error {
+	var errs []error
+	for _, problem := range template.Validate(tmplStr, opts) {
+		if problem.Severity == template.SeverityError {
+			errs = append(errs, errors.New(problem.Format(parts.where(setting, problem))))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid template %q:\n%w", setting, errors.Join(errs...))
+	}
+	return nil
+}
+
+// TemplateValidate checks templates and reports every problem found: the
+// templates named in args (file paths or "preset:<name>"), or if there are
+// none, every template the config refers to
+func TemplateValidate(rootDir string, args []string, output io.Writer) error {
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	parts, err := loadPartials(rootDir)
+	if err != nil {
+		return err
+	}
+	opts := template.Options{FrontMatter: cfg.FrontMatter, Partials: parts.sources, Locale: cfg.Locale, Lifecycle: cfg.Statuses}
+
+	settings := args
+	if len(settings) == 0 {
+		settings = cfg.ADRTemplates()
+	}
+
+	errorCount := 0
+	for _, setting := range settings {
+		name := setting
+		if name == "" {
+			name = template.PresetPrefix + "default"
+		}
+
+		tmplStr, err := config.ReadTemplate(rootDir, setting)
+		if err != nil {
+			fmt.Fprintf(output, "ERROR: %s: %v\n", name, err)
+			errorCount++
+			continue
+		}
+
+		for _, problem := range template.Validate(tmplStr, opts) {
+			if problem.Severity == template.SeverityError {
+				fmt.Fprintf(output, "ERROR: %s\n", problem.Format(parts.where(name, problem)))
+				errorCount++
+			} else {
+				fmt.Fprintf(output, "WARNING: %s\n", problem.Format(parts.where(name, problem)))
+			}
+		}
+	}
+
+	if errorCount > 0 {
+		return fmt.Errorf("template validation failed with %d error(s)", errorCount)
+	}
+
+	fmt.Fprintf(output, "Validated %d template(s) successfully\n", len(settings))
+	return nil
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateValidate_Configured(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		".adr-buddy/config.yml": `template: .adr-buddy/template.md
+templates:
+  statuses:
+    rejected: preset:nygard
+`,
+		".adr-buddy/template.md": "# {{.ID}}: {{.Name}}\n\n  {{nope .Status}}\n",
+	})
+
+	var output bytes.Buffer
+	err := TemplateValidate(tmpDir, nil, &output)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "1 error(s)")
+	assert.Contains(t, output.String(), `ERROR: .adr-buddy/template.md:3:5: function "nope" not defined`)
+	assert.Contains(t, output.String(), "WARNING: preset:nygard: does not render .Alternatives")
+}
+
+func TestTemplateValidate_Args(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	var output bytes.Buffer
+	assert.NoError(t, TemplateValidate(tmpDir, []string{"preset:madr", "preset:default"}, &output))
+	assert.Contains(t, output.String(), "Validated 2 template(s) successfully")
+
+	output.Reset()
+	err := TemplateValidate(tmpDir, []string{"missing.md"}, &output)
+	assert.Error(t, err)
+	assert.Contains(t, output.String(), "ERROR: missing.md: failed to read template")
+}
+
+func TestSync_InvalidTemplateFailsBeforeWriting(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		".adr-buddy/config.yml":  "template: .adr-buddy/template.md\n",
+		".adr-buddy/template.md": "# {{.ID}}: {{.Name}}\n{{if .Context}}\n",
+		"app.go":                 "// @decision.id: adr-1\n// @decision.name: X\n",
+	})
+
+	err := SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid template ".adr-buddy/template.md"`)
+	assert.Contains(t, err.Error(), ".adr-buddy/template.md:3: unexpected EOF")
+
+	_, statErr := os.Stat(filepath.Join(tmpDir, "decisions"))
+	assert.True(t, os.IsNotExist(statErr))
+}
+
+func TestSync_TemplatePartials(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		".adr-buddy/config.yml": `templates:
+  categories:
+    security: .adr-buddy/templates/security.md
+`,
+		".adr-buddy/templates/header.md": "# {{.ID}}: {{.Name}}\n\n**Status:** {{.Status}}\n",
+		".adr-buddy/templates/base.md": `{{template "header" .}}
+## Decision
+{{block "decision" .}}{{range .Decision}}{{.}}
+{{end}}{{end}}
+## Consequences
+{{range .Consequences}}{{.}}
+{{end}}`,
+		".adr-buddy/templates/security.md": `{{/* extends "base" */}}
+{{define "decision"}}Security review required.
+{{range .Decision}}{{.}}
+{{end}}{{end}}`,
+		"app.go": `// @decision.id: adr-1
+// @decision.name: Token storage
+// @decision.category: security
+// @decision.decision: Use the OS keychain
+package main
+`,
+	})
+
+	require.NoError(t, SyncWithFormat(tmpDir, false, "text", &bytes.Buffer{}))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "decisions", "security", "adr-1.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# adr-1: Token storage\n\n**Status:** proposed\n\n## Decision\nSecurity review required.\nUse the OS keychain\n\n## Consequences\n", string(content))
+}
+
+func TestTemplateValidate_PartialError(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFiles(t, tmpDir, map[string]string{
+		".adr-buddy/config.yml":          "template: .adr-buddy/template.md\n",
+		".adr-buddy/template.md":         "{{template \"header\" .}}\n",
+		".adr-buddy/templates/header.md": "# {{.ID}}\n{{nope}}\n",
+	})
+
+	var output bytes.Buffer
+	assert.Error(t, TemplateValidate(tmpDir, nil, &output))
+	assert.Contains(t, output.String(), filepath.Join(".adr-buddy", "templates", "header.md")+`:2:3: function "nope" not defined`)
+}
@@ -0,0 +1,437 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/weaby/adr-buddy/internal/config"
+	"github.com/weaby/adr-buddy/internal/model"
+	"github.com/weaby/adr-buddy/internal/parser"
+	"github.com/weaby/adr-buddy/internal/watch"
+)
+
+// DefaultDebounce is how long watch mode waits for a burst of saves to settle
+const DefaultDebounce = 300 * time.Millisecond
+
+// WatchOptions controls the behavior of sync --watch
+type WatchOptions struct {
+	Prune    bool          // Delete orphaned ADR files regardless of orphan_policy
+	Poll     bool          // Poll for changes instead of using native notifications
+	Interval time.Duration // Polling interval
+	Debounce time.Duration // Quiet period before re-syncing (DefaultDebounce if zero)
+}
+
+// cachedFile holds the annotations last parsed from one source file
+type cachedFile struct {
+	root        int    // Index of the scan path the file belongs to
+	rel         string // Path relative to that scan path
+	annotations []*model.Annotation
+}
+
+// watchSession keeps ADR files in sync for one configuration. Parsed
+// annotations are cached per file so a change only re-parses the files
+// that changed and re-renders the ADRs they contribute to.
+type watchSession struct {
+	rootDir    string
+	opts       WatchOptions
+	cfg        *config.Config
+	configPath string
+	templates  []string // Absolute paths of template files
+	partials   string   // Absolute path of the template partials directory
+	outputDir  string
+	dates      bool     // Watch the ADR files in outputDir, whose dates serve shows
+	roots      []string // Absolute scan paths, in config order
+	files      map[string]*cachedFile
+}
+
+// Watch runs a full sync and then re-syncs affected ADRs whenever annotated
+// sources, the config file or a template change, until ctx is cancelled
+func Watch(ctx context.Context, rootDir string, opts WatchOptions, output io.Writer) error {
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDebounce
+	}
+
+	for {
+		restart, err := runWatchSession(ctx, rootDir, opts, output)
+		if err != nil || !restart {
+			return err
+		}
+		fmt.Fprintf(output, "%sConfig changed, restarting watch\n", watchStamp())
+	}
+}
+
+// runWatchSession watches until ctx is done or the config file changes.
+// Returns true when the caller should start a new session with the new config.
+func runWatchSession(ctx context.Context, rootDir string, opts WatchOptions, output io.Writer) (bool, error) {
+	cfg, err := config.Load(rootDir)
+	if err != nil {
+		return false, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	s := newWatchSession(rootDir, cfg, opts)
+	if err := s.scanAll(); err != nil {
+		return false, err
+	}
+
+	fmt.Fprintf(output, "Found %d annotation(s)\n", len(s.annotations()))
+	if _, err := syncAnnotations(s.rootDir, cfg, s.annotations(), s.syncOptions(), nil, true, output); err != nil {
+		return false, err
+	}
+
+	w, err := watch.New(watch.Options{
+		Roots:    s.watchRoots(),
+		Ignore:   s.ignore,
+		Poll:     opts.Poll,
+		Interval: opts.Interval,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to start watcher: %w", err)
+	}
+	defer w.Close()
+
+	fmt.Fprintf(output, "\nWatching for changes (%s). Press Ctrl+C to stop.\n", w.Backend())
+
+	batches := watch.Batch(ctx, w.Events(), opts.Debounce)
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case batch, ok := <-batches:
+			if !ok {
+				if ctx.Err() != nil {
+					return false, nil
+				}
+				return false, errors.New("file watcher stopped unexpectedly")
+			}
+			if restart := s.handleBatch(batch, output); restart {
+				return true, nil
+			}
+		}
+	}
+}
+
+func newWatchSession(rootDir string, cfg *config.Config, opts WatchOptions) *watchSession {
+	rootDir = absPath(rootDir, ".")
+	s := &watchSession{
+		rootDir:    rootDir,
+		opts:       opts,
+		cfg:        cfg,
+		configPath: absPath(rootDir, filepath.Join(".adr-buddy", "config.yml")),
+		partials:   absPath(rootDir, partialsDir),
+		outputDir:  absPath(rootDir, cfg.OutputDir),
+		files:      make(map[string]*cachedFile),
+	}
+	for _, file := range cfg.TemplateFiles() {
+		s.templates = append(s.templates, absPath(rootDir, file))
+	}
+	for _, scanPath := range cfg.ScanPaths {
+		s.roots = append(s.roots, absPath(rootDir, scanPath))
+	}
+	return s
+}
+
+// absPath resolves path against rootDir unless it is already absolute
+func absPath(rootDir, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	abs, err := filepath.Abs(filepath.Join(rootDir, path))
+	if err != nil {
+		return filepath.Join(rootDir, path)
+	}
+	return abs
+}
+
+func (s *watchSession) syncOptions() SyncOptions {
+	return SyncOptions{Format: "text", Prune: s.opts.Prune}
+}
+
+// scanAll parses every configured path from scratch
+func (s *watchSession) scanAll() error {
+	s.files = make(map[string]*cachedFile)
+
+	for i, root := range s.roots {
+		annotations, err := parser.ScanDirectory(root, s.cfg.Exclude)
+		if err != nil {
+			return fmt.Errorf("failed to scan %s: %w", s.cfg.ScanPaths[i], err)
+		}
+		for _, ann := range annotations {
+			path := filepath.Join(root, ann.Location.File)
+			f, ok := s.files[path]
+			if !ok {
+				f = &cachedFile{root: i, rel: ann.Location.File}
+				s.files[path] = f
+			}
+			f.annotations = append(f.annotations, ann)
+		}
+	}
+
+	return nil
+}
+
+// annotations returns all cached annotations in the order a full scan
+// would produce them, since Aggregate keeps the first value it sees
+func (s *watchSession) annotations() []*model.Annotation {
+	files := make([]*cachedFile, 0, len(s.files))
+	for _, f := range s.files {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].root != files[j].root {
+			return files[i].root < files[j].root
+		}
+		return walkOrderLess(files[i].rel, files[j].rel)
+	})
+
+	var all []*model.Annotation
+	for _, f := range files {
+		all = append(all, f.annotations...)
+	}
+	return all
+}
+
+// annotationFiles returns the absolute path of the file each cached
+// annotation was found in
+func (s *watchSession) annotationFiles() map[*model.Annotation]string {
+	files := make(map[*model.Annotation]string)
+	for path, f := range s.files {
+		for _, ann := range f.annotations {
+			files[ann] = path
+		}
+	}
+	return files
+}
+
+// walkOrderLess orders relative paths the way filepath.WalkDir visits them,
+// comparing element by element rather than as whole strings
+func walkOrderLess(a, b string) bool {
+	as := strings.Split(filepath.ToSlash(a), "/")
+	bs := strings.Split(filepath.ToSlash(b), "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] != bs[i] {
+			return as[i] < bs[i]
+		}
+	}
+	return len(as) < len(bs)
+}
+
+// watchRoots returns the existing directories to watch: the scan paths plus
+// the directories holding the config file, the templates and the partials,
+// and the output directory if its ADR files are watched
+func (s *watchSession) watchRoots() []string {
+	candidates := append([]string{}, s.roots...)
+	candidates = append(candidates, filepath.Dir(s.configPath), s.partials)
+	if s.dates {
+		candidates = append(candidates, s.outputDir)
+	}
+	for _, tmpl := range s.templates {
+		candidates = append(candidates, filepath.Dir(tmpl))
+	}
+
+	seen := make(map[string]bool)
+	var roots []string
+	for _, dir := range candidates {
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			roots = append(roots, dir)
+		}
+	}
+
+	// Without a config directory, watch the project root so one can appear
+	if _, err := os.Stat(filepath.Dir(s.configPath)); os.IsNotExist(err) && !seen[s.rootDir] {
+		roots = append(roots, s.rootDir)
+	}
+	return roots
+}
+
+// ignore tells the watcher which paths can never affect the generated ADRs.
+// The output directory is ignored so our own writes don't loop, unless its
+// ADR files are watched.
+func (s *watchSession) ignore(path string, isDir bool) bool {
+	if path == s.configPath || s.isTemplate(path) {
+		return false
+	}
+	if isDir && (isWithin(s.configPath, path) || path == s.partials || isWithin(s.partials, path)) {
+		return false
+	}
+	for _, tmpl := range s.templates {
+		if isDir && isWithin(tmpl, path) {
+			return false
+		}
+	}
+	if s.isOutput(path) {
+		return !s.dates || (!isDir && filepath.Ext(path) != ".md")
+	}
+
+	for _, root := range s.roots {
+		if rel, ok := relWithin(root, path); ok {
+			return parser.ShouldExclude(rel, s.cfg.Exclude)
+		}
+	}
+
+	// Outside the scan paths only the config file and templates matter
+	return true
+}
+
+// handleBatch re-syncs the ADRs affected by a batch of changed paths.
+// Returns true if the config file changed and the session must restart.
+func (s *watchSession) handleBatch(paths []string, output io.Writer) bool {
+	full := false
+	affected := make(map[string]bool)
+
+	for _, path := range paths {
+		switch {
+		case path == s.configPath:
+			// Only restart once the new config is known to be valid
+			if _, err := config.Load(s.rootDir); err != nil {
+				fmt.Fprintf(output, "%sError: failed to load config: %v\n", watchStamp(), err)
+				continue
+			}
+			return true
+		case s.isTemplate(path):
+			full = true
+		case s.isRoot(path):
+			// The watcher lost events; start over
+			if err := s.scanAll(); err != nil {
+				fmt.Fprintf(output, "%sError: %v\n", watchStamp(), err)
+				return false
+			}
+			full = true
+		default:
+			s.refresh(path, affected)
+		}
+	}
+
+	var only map[string]bool
+	if !full {
+		if len(affected) == 0 {
+			return false
+		}
+		only = affected
+	}
+
+	result, err := syncAnnotations(s.rootDir, s.cfg, s.annotations(), s.syncOptions(), only, false, output)
+	if err != nil {
+		fmt.Fprintf(output, "%sError: %v\n", watchStamp(), err)
+		return false
+	}
+	printApplied(result, watchStamp(), output)
+	return false
+}
+
+// isTemplate reports whether path is a template file or a partial
+func (s *watchSession) isTemplate(path string) bool {
+	if isWithin(path, s.partials) {
+		return true
+	}
+	for _, tmpl := range s.templates {
+		if path == tmpl {
+			return true
+		}
+	}
+	return false
+}
+
+// isOutput reports whether path is the output directory or lies below it
+func (s *watchSession) isOutput(path string) bool {
+	return path == s.outputDir || isWithin(path, s.outputDir)
+}
+
+func (s *watchSession) isRoot(path string) bool {
+	for _, root := range s.roots {
+		if path == root {
+			return true
+		}
+	}
+	return false
+}
+
+// refresh re-parses path (a file or a directory) and records the IDs of
+// every ADR whose annotations were there before or are there now
+func (s *watchSession) refresh(path string, affected map[string]bool) {
+	for cached, f := range s.files {
+		if cached == path || isWithin(cached, path) {
+			for _, ann := range f.annotations {
+				affected[ann.ID] = true
+			}
+			delete(s.files, cached)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		// Removed; dropping it from the cache is all there is to do
+		return
+	}
+
+	if !info.IsDir() {
+		s.parse(path, affected)
+		return
+	}
+
+	filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if p != path && s.ignore(p, true) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		s.parse(p, affected)
+		return nil
+	})
+}
+
+// parse caches the annotations of a single file below one of the scan paths
+func (s *watchSession) parse(path string, affected map[string]bool) {
+	for i, root := range s.roots {
+		rel, ok := relWithin(root, path)
+		if !ok {
+			continue
+		}
+
+		annotations, err := parser.ScanFile(root, path, s.cfg.Exclude)
+		if err != nil || len(annotations) == 0 {
+			return
+		}
+
+		s.files[path] = &cachedFile{root: i, rel: rel, annotations: annotations}
+		for _, ann := range annotations {
+			affected[ann.ID] = true
+		}
+		return
+	}
+}
+
+// relWithin returns path relative to dir if path lies strictly below dir
+func relWithin(dir, path string) (string, bool) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// isWithin reports whether path lies strictly below dir
+func isWithin(path, dir string) bool {
+	_, ok := relWithin(dir, path)
+	return ok
+}
+
+// watchStamp prefixes watch mode log lines with the current time
+func watchStamp() string {
+	return time.Now().Format("[15:04:05] ")
+}
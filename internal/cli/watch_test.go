@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for use by the watch goroutine and the test
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatch_ResyncsAffectedADRs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	first := filepath.Join(tmpDir, "first.js")
+	second := filepath.Join(tmpDir, "second.js")
+	require.NoError(t, os.WriteFile(first, []byte(`// @decision.id: adr-1
+// @decision.name: First
+// @decision.status: proposed
+const x = 1;
+`), 0644))
+	require.NoError(t, os.WriteFile(second, []byte(`// @decision.id: adr-2
+// @decision.name: Second
+const y = 2;
+`), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var output syncBuffer
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, tmpDir, WatchOptions{
+			Poll:     true,
+			Interval: 20 * time.Millisecond,
+			Debounce: 20 * time.Millisecond,
+		}, &output)
+	}()
+
+	adr1 := filepath.Join(tmpDir, "decisions", "adr-1.md")
+	adr2 := filepath.Join(tmpDir, "decisions", "adr-2.md")
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(adr2)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return strings.Contains(output.String(), "Watching for changes (polling)")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(first, []byte(`// @decision.id: adr-1
+// @decision.name: First
+// @decision.status: accepted
+const x = 1;
+`), 0644))
+
+	assert.Eventually(t, func() bool {
+		content, err := os.ReadFile(adr1)
+		return err == nil && bytes.Contains(content, []byte("**Status:** accepted"))
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not stop after cancel")
+	}
+
+	// Only the edited ADR is re-synced
+	out := output.String()
+	assert.Contains(t, out, "Updated: "+filepath.Join("decisions", "adr-1.md"))
+	assert.NotContains(t, out, "Updated: "+filepath.Join("decisions", "adr-2.md"))
+}
@@ -1,19 +1,57 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/weaby/adr-buddy/internal/ids"
+	"github.com/weaby/adr-buddy/internal/lifecycle"
+	"github.com/weaby/adr-buddy/internal/template"
+)
+
+// Orphan policies control what sync does with ADR files whose ID no longer
+// appears in any annotation
+const (
+	OrphanPolicyKeep           = "keep"
+	OrphanPolicyMarkDeprecated = "mark-deprecated"
+	OrphanPolicyArchive        = "archive"
+	OrphanPolicyDelete         = "delete"
 )
 
+// TemplateRules selects templates for particular ADRs. Values take the same
+// form as the template setting: a file path or "preset:<name>".
+type TemplateRules struct {
+	Categories map[string]string `yaml:"categories,omitempty"`
+	Statuses   map[string]string `yaml:"statuses,omitempty"`
+}
+
+// IndexConfig controls the generated index of ADRs
+type IndexConfig struct {
+	Enabled  bool   `yaml:"enabled"`            // Regenerate the index on every sync
+	Template string `yaml:"template,omitempty"` // Index template file, built-in if empty
+	Graph    bool   `yaml:"graph,omitempty"`    // Embed a Mermaid graph of the decisions in the top-level index
+}
+
 // Config represents the adr-buddy configuration
 type Config struct {
-	ScanPaths  []string `yaml:"scan_paths"`
-	OutputDir  string   `yaml:"output_dir"`
-	Exclude    []string `yaml:"exclude"`
-	Template   string   `yaml:"template"`
-	StrictMode bool     `yaml:"strict_mode"`
+	ScanPaths    []string             `yaml:"scan_paths"`
+	OutputDir    string               `yaml:"output_dir"`
+	Exclude      []string             `yaml:"exclude"`
+	Template     string               `yaml:"template"`
+	Templates    TemplateRules        `yaml:"templates,omitempty"`
+	FrontMatter  bool                 `yaml:"front_matter"`
+	Locale       string               `yaml:"locale"`
+	Index        IndexConfig          `yaml:"index"`
+	StrictMode   bool                 `yaml:"strict_mode"`
+	OrphanPolicy string               `yaml:"orphan_policy"`
+	ArchiveDir   string               `yaml:"archive_dir"`
+	IDs          *ids.Scheme          `yaml:"ids,omitempty"` // Checked by check only when set
+	Statuses     *lifecycle.Lifecycle `yaml:"statuses,omitempty"`
 }
 
 // Default returns the default configuration
@@ -32,11 +70,189 @@ func Default() *Config {
 			"**/.claude/**",
 			"**/.github/**",
 		},
-		Template:   "",
-		StrictMode: false,
+		Template:     "",
+		FrontMatter:  false,
+		Locale:       template.DefaultLocale,
+		StrictMode:   false,
+		OrphanPolicy: OrphanPolicyKeep,
+		ArchiveDir:   "archive",
 	}
 }
 
+// Validate checks that enumerated settings hold known values
+func (c *Config) Validate() error {
+	switch c.OrphanPolicy {
+	case OrphanPolicyKeep, OrphanPolicyMarkDeprecated, OrphanPolicyArchive, OrphanPolicyDelete:
+	default:
+		return fmt.Errorf("invalid orphan_policy %q: must be one of: keep, mark-deprecated, archive, delete", c.OrphanPolicy)
+	}
+
+	if _, ok := template.PresetName(c.Index.Template); ok {
+		return fmt.Errorf("invalid index.template %q: presets are ADR templates, use a template file", c.Index.Template)
+	}
+
+	for _, setting := range c.templateSettings() {
+		if name, ok := template.PresetName(setting.value); ok {
+			if _, found := template.LookupPreset(name); !found {
+				return fmt.Errorf("invalid %s %q: unknown preset, must be one of: %s", setting.key, setting.value, strings.Join(template.PresetNames(), ", "))
+			}
+		}
+	}
+
+	if _, ok := template.LookupLocale(c.Locale); !ok {
+		return fmt.Errorf("invalid locale %q: must be one of: %s", c.Locale, strings.Join(template.LocaleCodes(), ", "))
+	}
+
+	if c.ArchiveDir == "" || filepath.IsAbs(c.ArchiveDir) {
+		return fmt.Errorf("invalid archive_dir %q: must be a path relative to output_dir", c.ArchiveDir)
+	}
+
+	if err := c.IDScheme().Validate(); err != nil {
+		return err
+	}
+
+	statuses := c.Lifecycle()
+	if err := statuses.Validate(); err != nil {
+		return err
+	}
+	for _, status := range sortedKeys(c.Templates.Statuses) {
+		if !statuses.Valid(status) {
+			return fmt.Errorf("invalid templates.statuses.%s: unknown status, must be one of: %s", status, strings.Join(statuses.Statuses, ", "))
+		}
+	}
+
+	return nil
+}
+
+// IDScheme returns the configured ID scheme, or the default one
+func (c *Config) IDScheme() ids.Scheme {
+	if c.IDs == nil {
+		return ids.Default()
+	}
+	return *c.IDs
+}
+
+// Lifecycle returns the configured statuses and transitions, or the default
+// ones
+func (c *Config) Lifecycle() lifecycle.Lifecycle {
+	if c.Statuses == nil {
+		return lifecycle.Default()
+	}
+	return *c.Statuses
+}
+
+// templateSetting is a configured template along with the config key it came from
+type templateSetting struct {
+	key   string
+	value string
+}
+
+// templateSettings returns every non-empty template setting in a stable order
+func (c *Config) templateSettings() []templateSetting {
+	var settings []templateSetting
+	if c.Template != "" {
+		settings = append(settings, templateSetting{"template", c.Template})
+	}
+	for _, category := range sortedKeys(c.Templates.Categories) {
+		settings = append(settings, templateSetting{"templates.categories." + category, c.Templates.Categories[category]})
+	}
+	for _, status := range sortedKeys(c.Templates.Statuses) {
+		settings = append(settings, templateSetting{"templates.statuses." + status, c.Templates.Statuses[status]})
+	}
+	if c.Index.Template != "" {
+		settings = append(settings, templateSetting{"index.template", c.Index.Template})
+	}
+	return settings
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// TemplateFiles returns the template files the config refers to, relative
+// to the project root as written. Presets are not included.
+func (c *Config) TemplateFiles() []string {
+	var files []string
+	seen := make(map[string]bool)
+	for _, setting := range c.templateSettings() {
+		if _, ok := template.PresetName(setting.value); ok || seen[setting.value] {
+			continue
+		}
+		seen[setting.value] = true
+		files = append(files, setting.value)
+	}
+	return files
+}
+
+// ADRTemplates returns the distinct ADR template settings, the main one
+// first. An empty setting stands for the built-in default template.
+func (c *Config) ADRTemplates() []string {
+	templates := []string{c.Template}
+	seen := map[string]bool{c.Template: true}
+	for _, setting := range c.templateSettings() {
+		if setting.key == "index.template" || seen[setting.value] {
+			continue
+		}
+		seen[setting.value] = true
+		templates = append(templates, setting.value)
+	}
+	return templates
+}
+
+// TemplateFor returns the template setting for an ADR: a status rule wins
+// over a category rule, which wins over the template setting
+func (c *Config) TemplateFor(category, status string) string {
+	if t := c.Templates.Statuses[status]; t != "" {
+		return t
+	}
+	if t := c.Templates.Categories[category]; t != "" {
+		return t
+	}
+	return c.Template
+}
+
+// ValidateTemplates checks that every template file the config refers to
+// can be read, resolving relative paths against rootDir
+func (c *Config) ValidateTemplates(rootDir string) error {
+	for _, setting := range c.templateSettings() {
+		if _, err := ReadTemplate(rootDir, setting.value); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", setting.key, setting.value, err)
+		}
+	}
+	return nil
+}
+
+// ReadTemplate resolves a template setting: a built-in preset
+// ("preset:madr"), a file relative to rootDir, or the default if empty
+func ReadTemplate(rootDir, setting string) (string, error) {
+	if setting == "" {
+		return template.DefaultTemplate(), nil
+	}
+
+	if name, ok := template.PresetName(setting); ok {
+		preset, found := template.LookupPreset(name)
+		if !found {
+			return "", fmt.Errorf("unknown template preset %q: must be one of: %s", name, strings.Join(template.PresetNames(), ", "))
+		}
+		return preset.Template, nil
+	}
+
+	path := setting
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(rootDir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template: %w", err)
+	}
+	return string(data), nil
+}
+
 // Load loads configuration from the specified directory
 // Returns default config if no config file exists
 func Load(rootDir string) (*Config, error) {
@@ -59,5 +275,13 @@ func Load(rootDir string) (*Config, error) {
 		return nil, err
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.ValidateTemplates(rootDir); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
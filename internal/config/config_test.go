@@ -6,6 +6,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/weaby/adr-buddy/internal/ids"
+	"github.com/weaby/adr-buddy/internal/lifecycle"
+	"github.com/weaby/adr-buddy/internal/template"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -56,3 +60,172 @@ func TestLoad_NoConfigFile(t *testing.T) {
 	assert.Equal(t, Default().ScanPaths, cfg.ScanPaths)
 	assert.Equal(t, Default().OutputDir, cfg.OutputDir)
 }
+
+func TestLoad_InvalidOrphanPolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".adr-buddy", "config.yml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+	assert.NoError(t, os.WriteFile(configPath, []byte("orphan_policy: shred\n"), 0644))
+
+	_, err := Load(tmpDir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "orphan_policy")
+}
+
+func TestLoad_IDs(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".adr-buddy", "config.yml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+
+	cfg, err := Load(tmpDir)
+	assert.NoError(t, err)
+	assert.Nil(t, cfg.IDs)
+	assert.Equal(t, ids.Default(), cfg.IDScheme())
+
+	assert.NoError(t, os.WriteFile(configPath, []byte("ids:\n  scheme: date\n"), 0644))
+	cfg, err = Load(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, ids.Scheme{Kind: ids.KindDate, Prefix: "adr-", Pad: 3}, cfg.IDScheme())
+
+	assert.NoError(t, os.WriteFile(configPath, []byte("ids:\n  scheme: uuid\n"), 0644))
+	_, err = Load(tmpDir)
+	assert.ErrorContains(t, err, "invalid ids.scheme")
+}
+
+func TestLoad_Statuses(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".adr-buddy", "config.yml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+
+	cfg, err := Load(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, lifecycle.Default(), cfg.Lifecycle())
+
+	assert.NoError(t, os.WriteFile(configPath, []byte(`statuses:
+  values: [draft, proposed, accepted, retired]
+  transitions:
+    draft: [proposed]
+    proposed: [accepted]
+    accepted: [retired]
+templates:
+  statuses:
+    retired: preset:nygard
+`), 0644))
+	cfg, err = Load(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"draft", "proposed", "accepted", "retired"}, cfg.Lifecycle().Statuses)
+	assert.Equal(t, []string{"retired"}, cfg.Lifecycle().Next("accepted"))
+
+	assert.NoError(t, os.WriteFile(configPath, []byte("templates:\n  statuses:\n    retired: preset:nygard\n"), 0644))
+	_, err = Load(tmpDir)
+	assert.ErrorContains(t, err, "invalid templates.statuses.retired: unknown status")
+}
+
+func TestLoad_TemplatePreset(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".adr-buddy", "config.yml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+
+	assert.NoError(t, os.WriteFile(configPath, []byte("template: preset:madr\n"), 0644))
+	cfg, err := Load(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "preset:madr", cfg.Template)
+
+	assert.NoError(t, os.WriteFile(configPath, []byte("template: preset:rfc\n"), 0644))
+	_, err = Load(tmpDir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown preset")
+}
+
+func TestLoad_Locale(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, ".adr-buddy", "config.yml")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+
+	assert.NoError(t, os.WriteFile(configPath, []byte("scan_paths: [.]\n"), 0644))
+	cfg, err := Load(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "en", cfg.Locale)
+
+	assert.NoError(t, os.WriteFile(configPath, []byte("locale: ja\n"), 0644))
+	cfg, err = Load(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "ja", cfg.Locale)
+
+	assert.NoError(t, os.WriteFile(configPath, []byte("locale: fr\n"), 0644))
+	_, err = Load(tmpDir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid locale "fr"`)
+}
+
+func TestLoad_TemplateRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, ".adr-buddy")
+	assert.NoError(t, os.MkdirAll(filepath.Join(configDir, "templates"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(configDir, "templates", "security.md"), []byte("# {{.ID}}\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(configDir, "config.yml"), []byte(`templates:
+  categories:
+    security: .adr-buddy/templates/security.md
+  statuses:
+    rejected: preset:nygard
+`), 0644))
+
+	cfg, err := Load(tmpDir)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ".adr-buddy/templates/security.md", cfg.TemplateFor("security", "accepted"))
+	assert.Equal(t, "preset:nygard", cfg.TemplateFor("security", "rejected"))
+	assert.Equal(t, "", cfg.TemplateFor("backend", "accepted"))
+	assert.Equal(t, []string{".adr-buddy/templates/security.md"}, cfg.TemplateFiles())
+	assert.Equal(t, []string{"", ".adr-buddy/templates/security.md", "preset:nygard"}, cfg.ADRTemplates())
+}
+
+func TestLoad_MissingTemplate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"template", "template: .adr-buddy/missing.md\n", `invalid template ".adr-buddy/missing.md"`},
+		{"category", "templates:\n  categories:\n    security: missing.md\n", `invalid templates.categories.security "missing.md"`},
+		{"status", "templates:\n  statuses:\n    rejected: missing.md\n", `invalid templates.statuses.rejected "missing.md"`},
+		{"preset", "templates:\n  statuses:\n    rejected: preset:rfc\n", "unknown preset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			configPath := filepath.Join(tmpDir, ".adr-buddy", "config.yml")
+			assert.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+			assert.NoError(t, os.WriteFile(configPath, []byte(tt.config), 0644))
+
+			_, err := Load(tmpDir)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestReadTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "custom.md"), []byte("# {{.ID}}\n"), 0644))
+
+	tmplStr, err := ReadTemplate(tmpDir, "")
+	assert.NoError(t, err)
+	assert.Equal(t, template.DefaultTemplate(), tmplStr)
+
+	tmplStr, err = ReadTemplate(tmpDir, "custom.md")
+	assert.NoError(t, err)
+	assert.Equal(t, "# {{.ID}}\n", tmplStr)
+
+	preset, _ := template.LookupPreset("madr")
+	tmplStr, err = ReadTemplate(tmpDir, "preset:madr")
+	assert.NoError(t, err)
+	assert.Equal(t, preset.Template, tmplStr)
+
+	_, err = ReadTemplate(tmpDir, "preset:rfc")
+	assert.ErrorContains(t, err, `unknown template preset "rfc"`)
+
+	_, err = ReadTemplate(tmpDir, "missing.md")
+	assert.ErrorContains(t, err, "failed to read template")
+}
@@ -0,0 +1,269 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change
+const contextLines = 3
+
+// opKind identifies a line-level edit
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// op is a single line-level edit between two texts
+type op struct {
+	Kind opKind
+	A    int // Index in the old lines (valid for equal/delete)
+	B    int // Index in the new lines (valid for equal/insert)
+}
+
+// Unified returns a unified diff turning oldText into newText.
+// fromName and toName label the --- and +++ headers; use "/dev/null" for
+// created or deleted files. Returns "" when the texts are equal.
+func Unified(fromName, toName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	a := splitLines(oldText)
+	b := splitLines(newText)
+	ops := myers(a, b)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n", fromName)
+	fmt.Fprintf(&sb, "+++ %s\n", toName)
+
+	for _, h := range hunks(ops) {
+		writeHunk(&sb, ops[h[0]:h[1]], a, b)
+	}
+
+	return sb.String()
+}
+
+// splitLines splits text into lines, keeping the trailing newline on each
+// line so a missing final newline shows up as a difference
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// myers computes a shortest edit script between a and b using
+// Myers' O(ND) algorithm
+func myers(a, b []string) []op {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	offset := max
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b, offset)
+			}
+		}
+	}
+
+	return backtrack(trace, a, b, offset)
+}
+
+// backtrack walks the recorded V arrays from the end to rebuild the edit script
+func backtrack(trace [][]int, a, b []string, offset int) []op {
+	x, y := len(a), len(b)
+	var ops []op
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, op{Kind: opEqual, A: x, B: y})
+		}
+
+		if d == 0 {
+			break
+		}
+
+		if x == prevX {
+			y--
+			ops = append(ops, op{Kind: opInsert, A: x, B: y})
+		} else {
+			x--
+			ops = append(ops, op{Kind: opDelete, A: x, B: y})
+		}
+	}
+
+	// Reverse into forward order
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// hunks groups ops into [start, end) ranges, each containing at least one
+// change surrounded by up to contextLines unchanged lines
+func hunks(ops []op) [][2]int {
+	var result [][2]int
+
+	i := 0
+	for i < len(ops) {
+		// Find the next change
+		for i < len(ops) && ops[i].Kind == opEqual {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		start := i - contextLines
+		if start < 0 {
+			start = 0
+		}
+
+		// Extend while changes are close enough to share context
+		end := i
+		for end < len(ops) {
+			if ops[end].Kind != opEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].Kind == opEqual {
+				run++
+			}
+			if run == len(ops) || run-end > 2*contextLines {
+				end += min(contextLines, run-end)
+				break
+			}
+			end = run
+		}
+
+		// Merge with the previous hunk if they touch
+		if len(result) > 0 && start <= result[len(result)-1][1] {
+			result[len(result)-1][1] = end
+		} else {
+			result = append(result, [2]int{start, end})
+		}
+		i = end
+	}
+
+	return result
+}
+
+// writeHunk renders a single hunk with its @@ header
+func writeHunk(sb *strings.Builder, ops []op, a, b []string) {
+	var oldStart, oldCount, newStart, newCount int
+	oldStart, newStart = -1, -1
+
+	for _, o := range ops {
+		switch o.Kind {
+		case opEqual:
+			if oldStart < 0 {
+				oldStart = o.A
+			}
+			if newStart < 0 {
+				newStart = o.B
+			}
+			oldCount++
+			newCount++
+		case opDelete:
+			if oldStart < 0 {
+				oldStart = o.A
+			}
+			oldCount++
+		case opInsert:
+			if newStart < 0 {
+				newStart = o.B
+			}
+			newCount++
+		}
+	}
+
+	// Ranges are 1-based, except empty ranges which point at the line before
+	if oldStart < 0 {
+		oldStart = ops[0].A
+	}
+	if newStart < 0 {
+		newStart = ops[0].B
+	}
+	if oldCount > 0 {
+		oldStart++
+	}
+	if newCount > 0 {
+		newStart++
+	}
+
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+
+	for _, o := range ops {
+		switch o.Kind {
+		case opEqual:
+			writeLine(sb, ' ', a[o.A])
+		case opDelete:
+			writeLine(sb, '-', a[o.A])
+		case opInsert:
+			writeLine(sb, '+', b[o.B])
+		}
+	}
+}
+
+// hunkRange formats a start,count pair, omitting the count when it is 1
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// writeLine writes a prefixed diff line, marking a missing final newline
+func writeLine(sb *strings.Builder, prefix byte, line string) {
+	sb.WriteByte(prefix)
+	sb.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		sb.WriteString("\n\\ No newline at end of file\n")
+	}
+}
@@ -0,0 +1,79 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnified_Equal(t *testing.T) {
+	assert.Equal(t, "", Unified("a/x.md", "b/x.md", "same\n", "same\n"))
+}
+
+func TestUnified_Modified(t *testing.T) {
+	oldText := "# adr-1: Test\n\n**Status:** proposed\n**Date:** 2026-01-15\n"
+	newText := "# adr-1: Test\n\n**Status:** accepted\n**Date:** 2026-01-15\n"
+
+	expected := `--- a/x.md
++++ b/x.md
+@@ -1,4 +1,4 @@
+ # adr-1: Test
+ 
+-**Status:** proposed
++**Status:** accepted
+ **Date:** 2026-01-15
+`
+	assert.Equal(t, expected, Unified("a/x.md", "b/x.md", oldText, newText))
+}
+
+func TestUnified_Created(t *testing.T) {
+	expected := `--- /dev/null
++++ b/x.md
+@@ -0,0 +1,2 @@
++line one
++line two
+`
+	assert.Equal(t, expected, Unified("/dev/null", "b/x.md", "", "line one\nline two\n"))
+}
+
+func TestUnified_Deleted(t *testing.T) {
+	expected := `--- a/x.md
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+`
+	assert.Equal(t, expected, Unified("a/x.md", "/dev/null", "gone\n", ""))
+}
+
+func TestUnified_SeparateHunks(t *testing.T) {
+	oldText := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	newText := "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n"
+
+	expected := `--- a
++++ b
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -9,4 +9,4 @@
+ 9
+ 10
+ 11
+-12
++twelve
+`
+	assert.Equal(t, expected, Unified("a", "b", oldText, newText))
+}
+
+func TestUnified_NoTrailingNewline(t *testing.T) {
+	expected := `--- a
++++ b
+@@ -1 +1 @@
+-text
+\ No newline at end of file
++text
+`
+	assert.Equal(t, expected, Unified("a", "b", "text", "text\n"))
+}
@@ -0,0 +1,241 @@
+// Package export writes the aggregated decisions in machine-readable formats
+// for other tools. The shape of every format is described by a versioned
+// JSON schema, see Schema.
+package export
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+// SchemaVersion is the version of the export format. It changes whenever a
+// field is removed or changes meaning; adding fields keeps the version.
+const SchemaVersion = 1
+
+// Formats the decisions can be exported in
+const (
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+	FormatYAML   = "yaml"
+)
+
+// Formats lists the supported formats
+var Formats = []string{FormatJSON, FormatNDJSON, FormatCSV, FormatYAML}
+
+//go:embed schema.json
+var schema []byte
+
+// Schema returns the JSON schema of the json and yaml exports
+func Schema() []byte {
+	return schema
+}
+
+// Document is a complete export
+type Document struct {
+	SchemaVersion int      `json:"schema_version" yaml:"schema_version"`
+	ADRs          []Record `json:"adrs" yaml:"adrs"`
+}
+
+// Record is one decision with every field adr-buddy knows about it
+type Record struct {
+	ID           string              `json:"id" yaml:"id"`
+	Name         string              `json:"name" yaml:"name"`
+	Status       string              `json:"status" yaml:"status"`
+	Category     string              `json:"category" yaml:"category"`
+	Date         string              `json:"date" yaml:"date"` // Empty if the ADR file hasn't been written yet
+	Tags         []string            `json:"tags" yaml:"tags"`
+	Context      []string            `json:"context" yaml:"context"`
+	Decision     []string            `json:"decision" yaml:"decision"`
+	Alternatives []string            `json:"alternatives" yaml:"alternatives"`
+	Consequences []string            `json:"consequences" yaml:"consequences"`
+	Relations    Relations           `json:"relations" yaml:"relations"`
+	Locations    []Location          `json:"locations" yaml:"locations"`
+	CustomFields map[string][]string `json:"custom_fields" yaml:"custom_fields"`
+}
+
+// Relations links a decision to others by ID
+type Relations struct {
+	Supersedes   []string `json:"supersedes" yaml:"supersedes"`
+	SupersededBy []string `json:"superseded_by" yaml:"superseded_by"` // Decisions that name this one in their supersedes
+	Relates      []string `json:"relates" yaml:"relates"`
+}
+
+// Location is a code location of a decision
+type Location struct {
+	File string `json:"file" yaml:"file"`
+	Line int    `json:"line" yaml:"line"`
+}
+
+// NewDocument returns the export of adrs, sorted by ID
+func NewDocument(adrs []*model.ADR) *Document {
+	supersededBy := make(map[string][]string)
+	for _, adr := range adrs {
+		for _, id := range adr.Supersedes {
+			supersededBy[id] = append(supersededBy[id], adr.ID)
+		}
+	}
+
+	doc := &Document{SchemaVersion: SchemaVersion, ADRs: make([]Record, 0, len(adrs))}
+	for _, adr := range adrs {
+		r := Record{
+			ID:           adr.ID,
+			Name:         adr.Name,
+			Status:       adr.Status,
+			Category:     adr.Category,
+			Date:         adr.Date,
+			Tags:         nonNil(adr.Tags),
+			Context:      nonNil(adr.Context),
+			Decision:     nonNil(adr.Decision),
+			Alternatives: nonNil(adr.Alternatives),
+			Consequences: nonNil(adr.Consequences),
+			Relations: Relations{
+				Supersedes:   nonNil(adr.Supersedes),
+				SupersededBy: nonNil(supersededBy[adr.ID]),
+				Relates:      nonNil(adr.Relates),
+			},
+			Locations:    make([]Location, 0, len(adr.Locations)),
+			CustomFields: adr.CustomFields,
+		}
+		sort.Slice(r.Relations.SupersededBy, func(i, j int) bool {
+			return model.IDLess(r.Relations.SupersededBy[i], r.Relations.SupersededBy[j])
+		})
+		for _, loc := range adr.Locations {
+			r.Locations = append(r.Locations, Location{File: loc.File, Line: loc.Line})
+		}
+		if r.CustomFields == nil {
+			r.CustomFields = map[string][]string{}
+		}
+		doc.ADRs = append(doc.ADRs, r)
+	}
+
+	sort.Slice(doc.ADRs, func(i, j int) bool { return model.IDLess(doc.ADRs[i].ID, doc.ADRs[j].ID) })
+	return doc
+}
+
+// nonNil returns list, or an empty list if it is nil, so lists are always
+// arrays and never null in the export
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return append([]string(nil), list...)
+}
+
+// Write writes the document to w in the named format
+func (d *Document) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(d); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return nil
+	case FormatNDJSON:
+		return d.writeNDJSON(w)
+	case FormatCSV:
+		return d.writeCSV(w)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(d); err != nil {
+			return fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("invalid format %q: must be one of: %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// ndjsonRecord is a line of the NDJSON export: a record that carries the
+// schema version itself, since there's no enclosing document
+type ndjsonRecord struct {
+	SchemaVersion int `json:"schema_version"`
+	Record
+}
+
+func (d *Document) writeNDJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, r := range d.ADRs {
+		if err := enc.Encode(ndjsonRecord{SchemaVersion: d.SchemaVersion, Record: r}); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", r.ID, err)
+		}
+	}
+	return nil
+}
+
+// csvColumns are the fixed CSV columns. Lists are joined by listSep,
+// paragraphs by paragraphSep; every custom field gets a column of its own.
+var csvColumns = []string{
+	"schema_version", "id", "name", "status", "category", "date", "tags",
+	"supersedes", "superseded_by", "relates", "locations",
+	"context", "decision", "alternatives", "consequences",
+}
+
+const (
+	listSep      = "; "
+	paragraphSep = "\n\n"
+)
+
+func (d *Document) writeCSV(w io.Writer) error {
+	var custom []string
+	seen := make(map[string]bool)
+	for _, r := range d.ADRs {
+		for field := range r.CustomFields {
+			if !seen[field] {
+				seen[field] = true
+				custom = append(custom, field)
+			}
+		}
+	}
+	sort.Strings(custom)
+
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	header := append([]string(nil), csvColumns...)
+	for _, field := range custom {
+		header = append(header, "custom."+field)
+	}
+	cw.Write(header)
+
+	for _, r := range d.ADRs {
+		locations := make([]string, 0, len(r.Locations))
+		for _, loc := range r.Locations {
+			locations = append(locations, fmt.Sprintf("%s:%d", loc.File, loc.Line))
+		}
+		row := []string{
+			fmt.Sprint(d.SchemaVersion), r.ID, r.Name, r.Status, r.Category, r.Date,
+			strings.Join(r.Tags, listSep),
+			strings.Join(r.Relations.Supersedes, listSep),
+			strings.Join(r.Relations.SupersededBy, listSep),
+			strings.Join(r.Relations.Relates, listSep),
+			strings.Join(locations, listSep),
+			strings.Join(r.Context, paragraphSep),
+			strings.Join(r.Decision, paragraphSep),
+			strings.Join(r.Alternatives, paragraphSep),
+			strings.Join(r.Consequences, paragraphSep),
+		}
+		for _, field := range custom {
+			row = append(row, strings.Join(r.CustomFields[field], paragraphSep))
+		}
+		cw.Write(row)
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to encode CSV: %w", err)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
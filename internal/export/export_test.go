@@ -0,0 +1,153 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+func testADRs() []*model.ADR {
+	return []*model.ADR{
+		{
+			ID:           "adr-10",
+			Name:         "Event bus",
+			Status:       "accepted",
+			Category:     "platform",
+			Date:         "2024-03-01",
+			Context:      []string{"Polling is slow", "Teams need events"},
+			Tags:         []string{"messaging"},
+			Supersedes:   []string{"adr-2"},
+			Relates:      []string{"adr-3"},
+			Locations:    []model.SourceLocation{{File: "bus/bus.go", Line: 3}, {File: "bus/sub.go", Line: 10}},
+			CustomFields: map[string][]string{"owner": {"platform-team"}},
+		},
+		{ID: "adr-2", Name: "Polling", Status: "superseded"},
+	}
+}
+
+func TestNewDocument(t *testing.T) {
+	doc := NewDocument(testADRs())
+
+	assert.Equal(t, SchemaVersion, doc.SchemaVersion)
+	require.Len(t, doc.ADRs, 2)
+	assert.Equal(t, "adr-2", doc.ADRs[0].ID, "sorted by ID, numbers numerically")
+	assert.Equal(t, []string{"adr-10"}, doc.ADRs[0].Relations.SupersededBy)
+	assert.Equal(t, []string{}, doc.ADRs[0].Tags, "lists are never null")
+	assert.Equal(t, map[string][]string{}, doc.ADRs[0].CustomFields)
+
+	bus := doc.ADRs[1]
+	assert.Equal(t, Relations{Supersedes: []string{"adr-2"}, SupersededBy: []string{}, Relates: []string{"adr-3"}}, bus.Relations)
+	assert.Equal(t, []Location{{File: "bus/bus.go", Line: 3}, {File: "bus/sub.go", Line: 10}}, bus.Locations)
+	assert.Equal(t, []string{"platform-team"}, bus.CustomFields["owner"])
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewDocument(testADRs()).Write(&buf, FormatJSON))
+
+	var decoded Document
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, NewDocument(testADRs()), &decoded)
+	assert.Contains(t, buf.String(), `"schema_version": 1`)
+	assert.Contains(t, buf.String(), `"tags": []`)
+}
+
+func TestWrite_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewDocument(testADRs()).Write(&buf, FormatNDJSON))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &line))
+	assert.Equal(t, float64(SchemaVersion), line["schema_version"])
+	assert.Equal(t, "adr-10", line["id"])
+}
+
+func TestWrite_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewDocument(testADRs()).Write(&buf, FormatCSV))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	header := rows[0]
+	assert.Equal(t, "custom.owner", header[len(header)-1])
+	row := make(map[string]string)
+	for i, col := range header {
+		row[col] = rows[2][i]
+	}
+	assert.Equal(t, "1", row["schema_version"])
+	assert.Equal(t, "adr-10", row["id"])
+	assert.Equal(t, "bus/bus.go:3; bus/sub.go:10", row["locations"])
+	assert.Equal(t, "Polling is slow\n\nTeams need events", row["context"])
+	assert.Equal(t, "platform-team", row["custom.owner"])
+	assert.Equal(t, "", rows[1][len(header)-1])
+}
+
+func TestWrite_YAML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewDocument(testADRs()).Write(&buf, FormatYAML))
+	assert.True(t, strings.HasPrefix(buf.String(), "schema_version: 1\nadrs:\n"))
+
+	var decoded Document
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, NewDocument(testADRs()), &decoded)
+}
+
+func TestWrite_InvalidFormat(t *testing.T) {
+	err := NewDocument(nil).Write(&bytes.Buffer{}, "xml")
+	assert.ErrorContains(t, err, `invalid format "xml": must be one of: json, ndjson, csv, yaml`)
+}
+
+// The schema must describe exactly the fields the export writes
+func TestSchema_MatchesRecord(t *testing.T) {
+	var s struct {
+		Properties map[string]any `json:"properties"`
+		Defs       struct {
+			ADR struct {
+				Required   []string `json:"required"`
+				Properties map[string]struct {
+					Const      any            `json:"const"`
+					Properties map[string]any `json:"properties"`
+				} `json:"properties"`
+			} `json:"adr"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(Schema(), &s))
+
+	assert.ElementsMatch(t, jsonFields(reflect.TypeOf(Document{})), keys(s.Properties))
+	assert.ElementsMatch(t, jsonFields(reflect.TypeOf(Record{})), s.Defs.ADR.Required)
+	assert.ElementsMatch(t, append(jsonFields(reflect.TypeOf(Record{})), "schema_version"), keys(s.Defs.ADR.Properties))
+	assert.ElementsMatch(t, jsonFields(reflect.TypeOf(Relations{})), keys(s.Defs.ADR.Properties["relations"].Properties))
+	assert.Equal(t, float64(SchemaVersion), s.Defs.ADR.Properties["schema_version"].Const)
+}
+
+func jsonFields(t reflect.Type) []string {
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		fields = append(fields, strings.Split(t.Field(i).Tag.Get("json"), ",")[0])
+	}
+	return fields
+}
+
+func keys[V any](m map[string]V) []string {
+	var ks []string
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
@@ -0,0 +1,355 @@
+// Package graph builds the graph of how decisions relate: ADRs as nodes,
+// and supersedes, relates and shared code locations as edges.
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/weaby/adr-buddy/internal/lifecycle"
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+// Edge kinds
+const (
+	EdgeSupersedes = "supersedes"
+	EdgeRelates    = "relates"
+	EdgeSharesCode = "shares-code"
+)
+
+// Formats the graph can be written in
+const (
+	FormatMermaid = "mermaid"
+	FormatDOT     = "dot"
+	FormatJSON    = "json"
+)
+
+// Node is a decision in the graph
+type Node struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Category string `json:"category,omitempty"`
+	Missing  bool   `json:"missing,omitempty"` // Referenced by another ADR but not annotated anywhere
+}
+
+// Edge is a relation between two decisions. Supersedes edges point from the
+// newer decision to the one it replaces; the other kinds are undirected.
+type Edge struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Kind  string   `json:"kind"`
+	Files []string `json:"files,omitempty"` // Files both decisions annotate, for shares-code
+}
+
+// Graph is the decisions and how they relate
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+
+	Lifecycle lifecycle.Lifecycle `json:"-"` // Colors nodes by status; the default lifecycle unless set
+}
+
+// Build returns the graph of adrs, sorted by ID
+func Build(adrs []*model.ADR) *Graph {
+	g := &Graph{Nodes: []Node{}, Edges: []Edge{}, Lifecycle: lifecycle.Default()}
+
+	known := make(map[string]bool)
+	for _, adr := range adrs {
+		known[adr.ID] = true
+		g.Nodes = append(g.Nodes, Node{ID: adr.ID, Name: adr.Name, Status: adr.Status, Category: adr.Category})
+	}
+
+	seen := make(map[string]bool)
+	addEdge := func(e Edge) {
+		if e.Kind != EdgeSupersedes && model.IDLess(e.To, e.From) {
+			e.From, e.To = e.To, e.From
+		}
+		key := e.Kind + "\x00" + e.From + "\x00" + e.To
+		if e.From == e.To || seen[key] {
+			return
+		}
+		seen[key] = true
+
+		for _, id := range []string{e.From, e.To} {
+			if !known[id] {
+				known[id] = true
+				g.Nodes = append(g.Nodes, Node{ID: id, Missing: true})
+			}
+		}
+		g.Edges = append(g.Edges, e)
+	}
+
+	for _, adr := range adrs {
+		for _, id := range adr.Supersedes {
+			addEdge(Edge{From: adr.ID, To: id, Kind: EdgeSupersedes})
+		}
+		for _, id := range adr.Relates {
+			addEdge(Edge{From: adr.ID, To: id, Kind: EdgeRelates})
+		}
+	}
+
+	// Decisions annotated in the same file share code
+	byFile := make(map[string][]string)
+	for _, adr := range adrs {
+		for _, loc := range adr.Locations {
+			if ids := byFile[loc.File]; len(ids) == 0 || ids[len(ids)-1] != adr.ID {
+				byFile[loc.File] = append(ids, adr.ID)
+			}
+		}
+	}
+	shared := make(map[[2]string][]string)
+	for file, ids := range byFile {
+		for i := range ids {
+			for j := i + 1; j < len(ids); j++ {
+				a, b := ids[i], ids[j]
+				if model.IDLess(b, a) {
+					a, b = b, a
+				}
+				if a != b {
+					shared[[2]string{a, b}] = append(shared[[2]string{a, b}], file)
+				}
+			}
+		}
+	}
+	for pair, files := range shared {
+		sort.Strings(files)
+		addEdge(Edge{From: pair[0], To: pair[1], Kind: EdgeSharesCode, Files: files})
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool { return model.IDLess(g.Nodes[i].ID, g.Nodes[j].ID) })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return model.IDLess(a.From, b.From)
+		}
+		if a.To != b.To {
+			return model.IDLess(a.To, b.To)
+		}
+		return a.Kind < b.Kind
+	})
+	return g
+}
+
+// Format writes the graph in the named format
+func (g *Graph) Format(format string) (string, error) {
+	switch format {
+	case FormatMermaid:
+		return g.Mermaid(), nil
+	case FormatDOT:
+		return g.DOT(), nil
+	case FormatJSON:
+		data, err := json.MarshalIndent(g, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode graph: %w", err)
+		}
+		return string(data) + "\n", nil
+	default:
+		return "", fmt.Errorf("invalid format %q: must be mermaid, dot or json", format)
+	}
+}
+
+// clusters groups the nodes by category, sorted by category; nodes without
+// a category come first under ""
+func (g *Graph) clusters() ([]string, map[string][]Node) {
+	byCategory := make(map[string][]Node)
+	var categories []string
+	for _, n := range g.Nodes {
+		if _, ok := byCategory[n.Category]; !ok {
+			categories = append(categories, n.Category)
+		}
+		byCategory[n.Category] = append(byCategory[n.Category], n)
+	}
+	sort.Strings(categories)
+	return categories, byCategory
+}
+
+// label is the text shown on a node
+func (n Node) label() string {
+	if n.Name == "" {
+		return n.ID
+	}
+	return n.ID + ": " + n.Name
+}
+
+// Mermaid returns the graph as a Mermaid flowchart
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	// Assign node IDs in node order so a collision suffixes the same node
+	// whatever the clusters
+	nodeIDs := newMermaidIDs()
+	for _, n := range g.Nodes {
+		nodeIDs.get(n.ID)
+	}
+
+	categories, byCategory := g.clusters()
+	for i, category := range categories {
+		indent := "    "
+		if category != "" {
+			fmt.Fprintf(&b, "    subgraph cluster%d[\"%s\"]\n", i, mermaidText(category))
+			indent = "        "
+		}
+		for _, n := range byCategory[category] {
+			fmt.Fprintf(&b, "%s%s[\"%s\"]\n", indent, nodeIDs.get(n.ID), mermaidText(n.label()))
+		}
+		if category != "" {
+			b.WriteString("    end\n")
+		}
+	}
+
+	for _, e := range g.Edges {
+		switch e.Kind {
+		case EdgeSupersedes:
+			fmt.Fprintf(&b, "    %s -->|supersedes| %s\n", nodeIDs.get(e.From), nodeIDs.get(e.To))
+		case EdgeRelates:
+			fmt.Fprintf(&b, "    %s -.-|relates| %s\n", nodeIDs.get(e.From), nodeIDs.get(e.To))
+		case EdgeSharesCode:
+			fmt.Fprintf(&b, "    %s ---|shares code| %s\n", nodeIDs.get(e.From), nodeIDs.get(e.To))
+		}
+	}
+
+	classes := newMermaidIDs()
+	for _, status := range g.statuses() {
+		var ids []string
+		color := ""
+		for _, n := range g.Nodes {
+			if strings.ToLower(n.Status) == status {
+				ids = append(ids, nodeIDs.get(n.ID))
+				if color == "" {
+					color = g.Lifecycle.Color(n.Status)
+				}
+			}
+		}
+		class := classes.get(status)
+		fmt.Fprintf(&b, "    classDef %s fill:%s\n", class, color)
+		fmt.Fprintf(&b, "    class %s %s\n", strings.Join(ids, ","), class)
+	}
+
+	return b.String()
+}
+
+// DOT returns the graph in Graphviz DOT format
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph decisions {\n")
+	b.WriteString("    rankdir=LR;\n")
+	b.WriteString("    node [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\"];\n")
+
+	categories, byCategory := g.clusters()
+	for i, category := range categories {
+		indent := "    "
+		if category != "" {
+			fmt.Fprintf(&b, "    subgraph cluster_%d {\n        label=%s;\n", i, dotString(category))
+			indent = "        "
+		}
+		for _, n := range byCategory[category] {
+			attrs := "label=" + dotString(n.ID+"\n"+n.Name)
+			if n.Name == "" {
+				attrs = "label=" + dotString(n.ID)
+			}
+			if n.Status != "" {
+				attrs += ", fillcolor=" + dotString(g.Lifecycle.Color(n.Status))
+			}
+			if n.Missing {
+				attrs += ", style=\"rounded,dashed\""
+			}
+			fmt.Fprintf(&b, "%s%s [%s];\n", indent, dotString(n.ID), attrs)
+		}
+		if category != "" {
+			b.WriteString("    }\n")
+		}
+	}
+
+	for _, e := range g.Edges {
+		switch e.Kind {
+		case EdgeSupersedes:
+			fmt.Fprintf(&b, "    %s -> %s [label=\"supersedes\"];\n", dotString(e.From), dotString(e.To))
+		case EdgeRelates:
+			fmt.Fprintf(&b, "    %s -> %s [label=\"relates\", style=dashed, dir=none];\n", dotString(e.From), dotString(e.To))
+		case EdgeSharesCode:
+			fmt.Fprintf(&b, "    %s -> %s [label=\"shares code\", style=dotted, dir=none];\n", dotString(e.From), dotString(e.To))
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// statuses returns the statuses of the nodes, lower-cased and sorted
+func (g *Graph) statuses() []string {
+	seen := make(map[string]bool)
+	var statuses []string
+	for _, n := range g.Nodes {
+		status := strings.ToLower(n.Status)
+		if status != "" && !seen[status] {
+			seen[status] = true
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Strings(statuses)
+	return statuses
+}
+
+// mermaidKeywords can't be used as Mermaid node IDs
+var mermaidKeywords = map[string]bool{"end": true, "graph": true, "flowchart": true, "subgraph": true, "class": true, "classdef": true, "style": true, "click": true}
+
+// mermaidIDs hands out distinct Mermaid IDs within one chart. Sanitizing
+// can map different IDs to the same one, like "adr-1.2" and "adr-1_2", so
+// later ones get a numeric suffix: "adr_1_2_2".
+type mermaidIDs struct {
+	ids   map[string]string
+	taken map[string]bool
+}
+
+func newMermaidIDs() *mermaidIDs {
+	return &mermaidIDs{ids: make(map[string]string), taken: make(map[string]bool)}
+}
+
+// get returns the Mermaid ID for id, assigning one on first use
+func (m *mermaidIDs) get(id string) string {
+	if mid, ok := m.ids[id]; ok {
+		return mid
+	}
+	base := mermaidID(id)
+	mid := base
+	for n := 2; m.taken[mid]; n++ {
+		mid = fmt.Sprintf("%s_%d", base, n)
+	}
+	m.ids[id] = mid
+	m.taken[mid] = true
+	return mid
+}
+
+// mermaidID turns an ADR ID into a Mermaid node ID, which may only hold
+// letters, digits and underscores: "adr-001" becomes "adr_001"
+func mermaidID(id string) string {
+	var b strings.Builder
+	for _, r := range id {
+		switch {
+		case r < 128 && (r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'):
+			b.WriteRune(r)
+		case r == '-' || r == '.':
+			b.WriteByte('_')
+		default:
+			fmt.Fprintf(&b, "_%x_", r)
+		}
+	}
+	if mermaidKeywords[strings.ToLower(b.String())] {
+		b.WriteByte('_')
+	}
+	return b.String()
+}
+
+// mermaidText escapes text for a quoted Mermaid label
+func mermaidText(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s)
+}
+
+// dotString quotes s as a DOT string
+func dotString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weaby/adr-buddy/internal/lifecycle"
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+func testADRs() []*model.ADR {
+	return []*model.ADR{
+		{
+			ID: "adr-10", Name: "Event bus", Status: "accepted", Category: "platform",
+			Supersedes: []string{"adr-2"},
+			Relates:    []string{"adr-3"},
+			Locations:  []model.SourceLocation{{File: "bus.go", Line: 1}, {File: "bus.go", Line: 9}},
+		},
+		{
+			ID: "adr-2", Name: "Polling", Status: "superseded", Category: "platform",
+			Locations: []model.SourceLocation{{File: "poll.go", Line: 1}},
+		},
+		{
+			ID: "adr-3", Name: `Use "JSON"`, Status: "proposed",
+			Relates:   []string{"adr-10", "adr-404"},
+			Locations: []model.SourceLocation{{File: "bus.go", Line: 20}},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	g := Build(testADRs())
+
+	require.Len(t, g.Nodes, 4)
+	assert.Equal(t, []string{"adr-2", "adr-3", "adr-10", "adr-404"}, []string{g.Nodes[0].ID, g.Nodes[1].ID, g.Nodes[2].ID, g.Nodes[3].ID})
+	assert.True(t, g.Nodes[3].Missing)
+
+	assert.Equal(t, []Edge{
+		{From: "adr-3", To: "adr-10", Kind: EdgeRelates},
+		{From: "adr-3", To: "adr-10", Kind: EdgeSharesCode, Files: []string{"bus.go"}},
+		{From: "adr-3", To: "adr-404", Kind: EdgeRelates},
+		{From: "adr-10", To: "adr-2", Kind: EdgeSupersedes},
+	}, g.Edges)
+}
+
+func TestMermaid(t *testing.T) {
+	assert.Equal(t, `flowchart LR
+    adr_3["adr-3: Use #quot;JSON#quot;"]
+    adr_404["adr-404"]
+    subgraph cluster1["platform"]
+        adr_2["adr-2: Polling"]
+        adr_10["adr-10: Event bus"]
+    end
+    adr_3 -.-|relates| adr_10
+    adr_3 ---|shares code| adr_10
+    adr_3 -.-|relates| adr_404
+    adr_10 -->|supersedes| adr_2
+    classDef accepted fill:#d1e7dd
+    class adr_10 accepted
+    classDef proposed fill:#cfe2ff
+    class adr_3 proposed
+    classDef superseded fill:#e2e3e5
+    class adr_2 superseded
+`, Build(testADRs()).Mermaid())
+}
+
+func TestMermaid_Lifecycle(t *testing.T) {
+	g := Build([]*model.ADR{{ID: "adr-1", Status: "in-review"}, {ID: "adr-2", Status: "draft"}})
+	g.Lifecycle = lifecycle.Lifecycle{Statuses: []string{"proposed", "in-review", "draft"}, Colors: map[string]string{"in-review": "#ffe5b4"}}
+
+	out := g.Mermaid()
+	assert.Contains(t, out, "    classDef in_review fill:#ffe5b4\n    class adr_1 in_review\n")
+	assert.Contains(t, out, "    classDef draft fill:"+g.Lifecycle.Color("draft")+"\n")
+	assert.Contains(t, g.DOT(), `"adr-1" [label="adr-1", fillcolor="#ffe5b4"];`)
+}
+
+func TestDOT(t *testing.T) {
+	assert.Equal(t, `digraph decisions {
+    rankdir=LR;
+    node [shape=box, style="rounded,filled", fillcolor="#ffffff"];
+    "adr-3" [label="adr-3\nUse \"JSON\"", fillcolor="#cfe2ff"];
+    "adr-404" [label="adr-404", style="rounded,dashed"];
+    subgraph cluster_1 {
+        label="platform";
+        "adr-2" [label="adr-2\nPolling", fillcolor="#e2e3e5"];
+        "adr-10" [label="adr-10\nEvent bus", fillcolor="#d1e7dd"];
+    }
+    "adr-3" -> "adr-10" [label="relates", style=dashed, dir=none];
+    "adr-3" -> "adr-10" [label="shares code", style=dotted, dir=none];
+    "adr-3" -> "adr-404" [label="relates", style=dashed, dir=none];
+    "adr-10" -> "adr-2" [label="supersedes"];
+}
+`, Build(testADRs()).DOT())
+}
+
+func TestFormat(t *testing.T) {
+	out, err := Build(testADRs()).Format(FormatJSON)
+	require.NoError(t, err)
+
+	var g Graph
+	require.NoError(t, json.Unmarshal([]byte(out), &g))
+	assert.Len(t, g.Nodes, 4)
+	assert.Len(t, g.Edges, 4)
+
+	_, err = Build(nil).Format("svg")
+	assert.ErrorContains(t, err, `invalid format "svg"`)
+}
+
+func TestMermaidID(t *testing.T) {
+	assert.Equal(t, "adr_001", mermaidID("adr-001"))
+	assert.Equal(t, "end_", mermaidID("end"))
+	assert.Equal(t, "ADR_e9_", mermaidID("ADRé"))
+}
+
+func TestMermaid_IDCollision(t *testing.T) {
+	g := Build([]*model.ADR{
+		{ID: "adr-1.2", Name: "Dotted", Status: "accepted", Relates: []string{"adr-1_2"}},
+		{ID: "adr-1_2", Name: "Underscored", Status: "accepted"},
+	})
+	out := g.Mermaid()
+
+	assert.Contains(t, out, `adr_1_2["adr-1.2: Dotted"]`)
+	assert.Contains(t, out, `adr_1_2_2["adr-1_2: Underscored"]`)
+	assert.Contains(t, out, "adr_1_2 -.-|relates| adr_1_2_2")
+	assert.Contains(t, out, "class adr_1_2,adr_1_2_2 accepted")
+}
@@ -0,0 +1,417 @@
+// Package ids allocates and checks ADR IDs according to a configurable
+// scheme.
+package ids
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+// Kinds of ID schemes
+const (
+	KindSequential = "sequential" // adr-001, adr-002, ...
+	KindDate       = "date"       // adr-20260117-1, numbered within the day
+	KindULID       = "ulid"       // adr-01J0Z3M8K6A4QX0C9D7E5F2G1H
+)
+
+// Kinds lists the supported kinds of ID schemes
+var Kinds = []string{KindSequential, KindDate, KindULID}
+
+// Defaults of the scheme
+const (
+	DefaultPrefix = "adr-"
+	DefaultPad    = 3
+)
+
+// Scheme is the shape of ADR IDs: a prefix, which may differ per category,
+// followed by a sequence number, a date and daily number, or a ULID
+type Scheme struct {
+	Kind       string            `yaml:"scheme"`
+	Prefix     string            `yaml:"prefix"`
+	Pad        int               `yaml:"pad"`                  // Zero padding of sequence numbers
+	Categories map[string]string `yaml:"categories,omitempty"` // Prefixes of categories and their subcategories
+}
+
+// Default returns the default scheme, which gives IDs like adr-001
+func Default() Scheme {
+	return Scheme{Kind: KindSequential, Prefix: DefaultPrefix, Pad: DefaultPad}
+}
+
+// UnmarshalYAML fills in the settings a config leaves out from the default
+// scheme
+func (s *Scheme) UnmarshalYAML(value *yaml.Node) error {
+	type plain Scheme
+	p := plain(Default())
+	if err := value.Decode(&p); err != nil {
+		return err
+	}
+	*s = Scheme(p)
+	return nil
+}
+
+// Validate checks that the scheme is one adr-buddy knows and that every ID
+// it gives is a valid file name
+func (s Scheme) Validate() error {
+	switch s.Kind {
+	case KindSequential, KindDate, KindULID:
+	default:
+		return fmt.Errorf("invalid ids.scheme %q: must be one of: %s", s.Kind, strings.Join(Kinds, ", "))
+	}
+	if s.Pad < 0 {
+		return fmt.Errorf("invalid ids.pad %d: must not be negative", s.Pad)
+	}
+	if strings.ContainsAny(s.Prefix, `/\`) {
+		return fmt.Errorf("invalid ids.prefix %q: must not contain path separators", s.Prefix)
+	}
+	for category, prefix := range s.Categories {
+		if strings.ContainsAny(prefix, `/\`) {
+			return fmt.Errorf("invalid ids.categories.%s %q: must not contain path separators", category, prefix)
+		}
+	}
+	return nil
+}
+
+// PrefixFor returns the prefix of IDs in a category: the prefix set for the
+// category or its nearest parent, or the scheme's prefix
+func (s Scheme) PrefixFor(category string) string {
+	for c := category; c != ""; {
+		if prefix, ok := s.Categories[c]; ok {
+			return prefix
+		}
+		i := strings.LastIndex(c, "/")
+		if i < 0 {
+			break
+		}
+		c = c[:i]
+	}
+	return s.Prefix
+}
+
+// Pattern describes the IDs of a category, e.g. "adr-NNN"
+func (s Scheme) Pattern(category string) string {
+	return s.pattern(s.PrefixFor(category))
+}
+
+func (s Scheme) pattern(prefix string) string {
+	switch s.Kind {
+	case KindDate:
+		return prefix + "YYYYMMDD-N"
+	case KindULID:
+		return prefix + "<ULID>"
+	default:
+		return prefix + strings.Repeat("N", max(s.Pad, 1))
+	}
+}
+
+var (
+	dateRe = regexp.MustCompile(`^(\d{8})-([1-9]\d*)$`)
+	ulidRe = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+)
+
+// Match reports whether id has the shape of the IDs of a category
+func (s Scheme) Match(id, category string) bool {
+	rest, ok := strings.CutPrefix(id, s.PrefixFor(category))
+	if !ok {
+		return false
+	}
+	switch s.Kind {
+	case KindDate:
+		m := dateRe.FindStringSubmatch(rest)
+		if m == nil {
+			return false
+		}
+		_, err := time.Parse("20060102", m[1])
+		return err == nil
+	case KindULID:
+		return ulidRe.MatchString(rest)
+	default:
+		n, ok := s.number(rest)
+		return ok && rest == s.format(n)
+	}
+}
+
+// number parses a sequence number, padded or not
+func (s Scheme) number(digits string) (uint64, bool) {
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(digits, 10, 64)
+	return n, err == nil
+}
+
+// format pads a sequence number
+func (s Scheme) format(n uint64) string {
+	return fmt.Sprintf("%0*d", s.Pad, n)
+}
+
+// Next returns a new ID for a category that none of the IDs in use has.
+// Sequential IDs follow the highest number in use with the same prefix;
+// date IDs the highest number of the day.
+func (s Scheme) Next(category string, used []string, now time.Time) (string, error) {
+	prefix := s.PrefixFor(category)
+	switch s.Kind {
+	case KindDate:
+		day := now.Format("20060102")
+		next := uint64(1)
+		for _, id := range used {
+			rest, ok := strings.CutPrefix(id, prefix+day+"-")
+			if n, valid := s.number(rest); ok && valid && n >= next {
+				next = n + 1
+			}
+		}
+		return fmt.Sprintf("%s%s-%d", prefix, day, next), nil
+	case KindULID:
+		id, err := newULID(now)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate ULID: %w", err)
+		}
+		return prefix + id, nil
+	default:
+		next := uint64(1)
+		for _, id := range used {
+			rest, ok := strings.CutPrefix(id, prefix)
+			if n, valid := s.number(rest); ok && valid && n >= next {
+				next = n + 1
+			}
+		}
+		return prefix + s.format(next), nil
+	}
+}
+
+// InferPad returns the padding of the highest sequential ID of a category
+// in used, e.g. 0 for adr-12 and 3 for adr-012, or the scheme's padding if
+// there is none
+func (s Scheme) InferPad(category string, used []string) int {
+	prefix := s.PrefixFor(category)
+	var highest uint64
+	pad := s.Pad
+	for _, id := range used {
+		digits, ok := strings.CutPrefix(id, prefix)
+		if !ok {
+			continue
+		}
+		if n, ok := s.number(digits); ok && n >= highest {
+			highest, pad = n, 0
+			if digits[0] == '0' {
+				pad = len(digits)
+			}
+		}
+	}
+	return pad
+}
+
+// Repad returns a sequential ID with its number zero padded to pad digits
+// instead, if id has one of the scheme's prefixes followed by a number
+func (s Scheme) Repad(id string, pad int) (string, bool) {
+	prefixes := []string{s.Prefix}
+	for _, prefix := range s.Categories {
+		prefixes = append(prefixes, prefix)
+	}
+	// The longest prefix wins, so "adr-" doesn't take "adr-db-" IDs
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+
+	for _, prefix := range prefixes {
+		rest, ok := strings.CutPrefix(id, prefix)
+		if n, valid := s.number(rest); ok && valid {
+			return fmt.Sprintf("%s%0*d", prefix, pad, n), true
+		}
+	}
+	return "", false
+}
+
+// crockford is the alphabet of ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID returns a ULID: 48 bits of milliseconds since the epoch and 80
+// random bits, in Crockford's base32
+func newULID(now time.Time) (string, error) {
+	var b [16]byte
+	ms := uint64(now.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+
+	// 128 bits in 26 characters of 5 bits, the first holding only 3
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		bit := 5 * (25 - i) // Offset of the lowest bit of character i
+		var v byte
+		for j := 0; j < 5; j++ {
+			if k := bit + j; k < 128 {
+				byteIndex, shift := 15-k/8, k%8
+				v |= (b[byteIndex] >> shift & 1) << j
+			}
+		}
+		out[i] = crockford[v]
+	}
+	return string(out), nil
+}
+
+// Decision is an ID in use, by an annotation or an ADR file
+type Decision struct {
+	ID       string
+	Category string
+	Location model.SourceLocation // Where it is used; line 0 for ADR files
+	Archived bool                 // An archived ADR file: its number is taken, its shape isn't checked
+}
+
+// Problem is a way decisions break the scheme
+type Problem struct {
+	Type     string // "id_scheme", "id_duplicate" or "id_gap"
+	ID       string
+	Location model.SourceLocation // Empty for gaps
+	Message  string
+}
+
+// Audit checks decisions against the scheme. It reports IDs of the wrong
+// shape, IDs numbered the same as another (like adr-7 and adr-007) or used
+// in more than one category, and gaps in sequential numbering.
+func (s Scheme) Audit(decisions []Decision) []Problem {
+	var problems []Problem
+
+	checked := make(map[string]bool)
+	categories := make(map[string][]Decision)
+	for _, d := range decisions {
+		if d.Archived {
+			continue
+		}
+		if !checked[d.ID] {
+			checked[d.ID] = true
+			if !s.Match(d.ID, d.Category) {
+				problems = append(problems, Problem{
+					Type:     "id_scheme",
+					ID:       d.ID,
+					Location: d.Location,
+					Message:  fmt.Sprintf("ID %q doesn't match the %s scheme: expected %s", d.ID, s.Kind, s.Pattern(d.Category)),
+				})
+			}
+		}
+		seen := false
+		for _, other := range categories[d.ID] {
+			seen = seen || other.Category == d.Category
+		}
+		if !seen {
+			categories[d.ID] = append(categories[d.ID], d)
+		}
+	}
+
+	for _, id := range sortedKeys(categories) {
+		if in := categories[id]; len(in) > 1 {
+			var where []string
+			for _, d := range in {
+				where = append(where, describe(d.Location))
+			}
+			problems = append(problems, Problem{
+				Type:     "id_duplicate",
+				ID:       id,
+				Location: in[1].Location,
+				Message:  fmt.Sprintf("ID %q is used in more than one category: %s", id, strings.Join(where, ", ")),
+			})
+		}
+	}
+
+	if s.Kind != KindSequential {
+		return problems
+	}
+	return append(problems, s.auditNumbers(decisions)...)
+}
+
+// maxListedGaps is how many missing IDs a gap lists by name
+const maxListedGaps = 10
+
+// auditNumbers reports sequential IDs sharing a number and the numbers
+// missing below the highest of each prefix
+func (s Scheme) auditNumbers(decisions []Decision) []Problem {
+	prefixes := []string{s.Prefix}
+	for _, category := range sortedKeys(s.Categories) {
+		prefixes = append(prefixes, s.Categories[category])
+	}
+
+	var problems []Problem
+	done := make(map[string]bool)
+	for _, prefix := range prefixes {
+		if done[prefix] {
+			continue
+		}
+		done[prefix] = true
+
+		byNumber := make(map[uint64][]Decision)
+		var highest uint64
+		for _, d := range decisions {
+			rest, ok := strings.CutPrefix(d.ID, prefix)
+			n, valid := s.number(rest)
+			if !ok || !valid || n == 0 {
+				continue
+			}
+			dup := false
+			for _, other := range byNumber[n] {
+				dup = dup || other.ID == d.ID
+			}
+			if !dup {
+				byNumber[n] = append(byNumber[n], d)
+			}
+			highest = max(highest, n)
+		}
+
+		var missing []string
+		for n := uint64(1); n <= highest; n++ {
+			switch in := byNumber[n]; {
+			case len(in) == 0:
+				missing = append(missing, prefix+s.format(n))
+			case len(in) > 1:
+				var names []string
+				for _, d := range in {
+					names = append(names, d.ID)
+				}
+				problems = append(problems, Problem{
+					Type:     "id_duplicate",
+					ID:       in[1].ID,
+					Location: in[1].Location,
+					Message:  fmt.Sprintf("IDs %s share number %d", strings.Join(names, " and "), n),
+				})
+			}
+		}
+		if len(missing) > 0 {
+			list := strings.Join(missing[:min(len(missing), maxListedGaps)], ", ")
+			if len(missing) > maxListedGaps {
+				list += fmt.Sprintf(" and %d more", len(missing)-maxListedGaps)
+			}
+			problems = append(problems, Problem{
+				Type:    "id_gap",
+				ID:      missing[0],
+				Message: fmt.Sprintf("gap in %s numbering: %s missing", s.pattern(prefix), list),
+			})
+		}
+	}
+	return problems
+}
+
+// describe returns a location, leaving out the line of ADR files
+func describe(loc model.SourceLocation) string {
+	if loc.Line == 0 {
+		return loc.File
+	}
+	return loc.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return model.IDLess(keys[i], keys[j]) })
+	return keys
+}
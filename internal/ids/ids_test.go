@@ -0,0 +1,167 @@
+package ids
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+var now = time.Date(2026, 1, 17, 10, 30, 0, 0, time.UTC)
+
+func TestScheme_Next(t *testing.T) {
+	perCategory := Scheme{Kind: KindSequential, Prefix: "adr-", Pad: 3, Categories: map[string]string{"infra": "inf-"}}
+
+	tests := []struct {
+		name     string
+		scheme   Scheme
+		category string
+		used     []string
+		want     string
+	}{
+		{"first", Default(), "", nil, "adr-001"},
+		{"after highest", Default(), "", []string{"adr-001", "adr-007", "adr-3", "db-99"}, "adr-008"},
+		{"unpadded", Scheme{Kind: KindSequential, Prefix: "ADR-"}, "", []string{"ADR-9"}, "ADR-10"},
+		{"padding outgrown", Default(), "", []string{"adr-999"}, "adr-1000"},
+		{"category prefix", perCategory, "infra", []string{"adr-004", "inf-001"}, "inf-002"},
+		{"subcategory uses parent prefix", perCategory, "infra/db", []string{"inf-001"}, "inf-002"},
+		{"other category", perCategory, "api", []string{"adr-004", "inf-009"}, "adr-005"},
+		{"date", Scheme{Kind: KindDate, Prefix: "adr-"}, "", []string{"adr-20260116-4", "adr-20260117-1"}, "adr-20260117-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.scheme.Next(tt.category, tt.used, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScheme_NextULID(t *testing.T) {
+	scheme := Scheme{Kind: KindULID, Prefix: "adr-"}
+	a, err := scheme.Next("", nil, now)
+	require.NoError(t, err)
+	b, err := scheme.Next("", nil, now)
+	require.NoError(t, err)
+
+	assert.True(t, scheme.Match(a, ""), a)
+	assert.NotEqual(t, a, b)
+	// The timestamp comes first, so ULIDs sort by time
+	assert.Equal(t, "adr-01KF5R2C20", a[:14])
+	assert.Equal(t, a[:14], b[:14])
+}
+
+func TestScheme_InferPad(t *testing.T) {
+	scheme := Scheme{Kind: KindSequential, Prefix: "adr-", Pad: 3, Categories: map[string]string{"db": "adr-db-"}}
+
+	assert.Equal(t, 0, scheme.InferPad("", []string{"adr-1", "adr-4", "adr-db-0002"}))
+	assert.Equal(t, 4, scheme.InferPad("db", []string{"adr-1", "adr-db-0002"}))
+	assert.Equal(t, 2, scheme.InferPad("", []string{"adr-1", "adr-07"}))
+	assert.Equal(t, 3, scheme.InferPad("", []string{"ADR-1", "adr-x"}))
+}
+
+func TestScheme_Repad(t *testing.T) {
+	scheme := Scheme{Kind: KindSequential, Prefix: "adr-", Pad: 3, Categories: map[string]string{"db": "adr-db-"}}
+
+	got, ok := scheme.Repad("adr-7", 3)
+	assert.True(t, ok)
+	assert.Equal(t, "adr-007", got)
+
+	got, ok = scheme.Repad("adr-db-0012", 2)
+	assert.True(t, ok)
+	assert.Equal(t, "adr-db-12", got)
+
+	got, ok = scheme.Repad("adr-1234", 3)
+	assert.True(t, ok)
+	assert.Equal(t, "adr-1234", got)
+
+	_, ok = scheme.Repad("adr-20260117-1", 3)
+	assert.False(t, ok)
+	_, ok = scheme.Repad("ADR-7", 3)
+	assert.False(t, ok)
+}
+
+func TestScheme_Match(t *testing.T) {
+	tests := []struct {
+		scheme Scheme
+		id     string
+		want   bool
+	}{
+		{Default(), "adr-001", true},
+		{Default(), "adr-1000", true},
+		{Default(), "adr-1", false},
+		{Default(), "adr-0001", false},
+		{Default(), "ADR-001", false},
+		{Scheme{Kind: KindSequential, Prefix: "adr-"}, "adr-1", true},
+		{Scheme{Kind: KindDate, Prefix: "adr-"}, "adr-20260117-2", true},
+		{Scheme{Kind: KindDate, Prefix: "adr-"}, "adr-20261317-2", false},
+		{Scheme{Kind: KindDate, Prefix: "adr-"}, "adr-20260117", false},
+		{Scheme{Kind: KindULID, Prefix: "adr-"}, "adr-01KF6Q5Z3M8K6A4QX0C9D7E5F2", true},
+		{Scheme{Kind: KindULID, Prefix: "adr-"}, "adr-01KF6Q5Z3M8K6A4QX0C9D7E5FU", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme.Kind+"/"+tt.id, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.scheme.Match(tt.id, ""))
+		})
+	}
+}
+
+func TestScheme_Audit(t *testing.T) {
+	scheme := Scheme{Kind: KindSequential, Prefix: "adr-", Pad: 3, Categories: map[string]string{"infra": "inf-"}}
+	at := func(file string, line int) model.SourceLocation {
+		return model.SourceLocation{File: file, Line: line}
+	}
+
+	problems := scheme.Audit([]Decision{
+		{ID: "adr-001", Location: at("a.go", 1)},
+		{ID: "adr-001", Location: at("b.go", 5)},
+		{ID: "adr-001", Location: at("decisions/adr-001.md", 0)},
+		{ID: "adr-7", Location: at("c.go", 3)},
+		{ID: "adr-007", Location: at("d.go", 3)},
+		{ID: "adr-005", Category: "api", Location: at("e.go", 1)},
+		{ID: "adr-005", Category: "web", Location: at("decisions/web/adr-005.md", 0)},
+		{ID: "adr-003", Location: at("decisions/archive/adr-003.md", 0), Archived: true},
+		{ID: "inf-002", Category: "infra", Location: at("f.go", 1)},
+		{ID: "inf-003", Category: "api", Location: at("g.go", 1)},
+	})
+
+	assert.Equal(t, []Problem{
+		{Type: "id_scheme", ID: "adr-7", Location: at("c.go", 3), Message: `ID "adr-7" doesn't match the sequential scheme: expected adr-NNN`},
+		{Type: "id_scheme", ID: "inf-003", Location: at("g.go", 1), Message: `ID "inf-003" doesn't match the sequential scheme: expected adr-NNN`},
+		{Type: "id_duplicate", ID: "adr-005", Location: at("decisions/web/adr-005.md", 0), Message: `ID "adr-005" is used in more than one category: e.go:1, decisions/web/adr-005.md`},
+		{Type: "id_duplicate", ID: "adr-007", Location: at("d.go", 3), Message: "IDs adr-7 and adr-007 share number 7"},
+		{Type: "id_gap", ID: "adr-002", Message: "gap in adr-NNN numbering: adr-002, adr-004, adr-006 missing"},
+		{Type: "id_gap", ID: "inf-001", Message: "gap in inf-NNN numbering: inf-001 missing"},
+	}, problems)
+}
+
+func TestScheme_AuditOtherKinds(t *testing.T) {
+	scheme := Scheme{Kind: KindDate, Prefix: "adr-"}
+	problems := scheme.Audit([]Decision{
+		{ID: "adr-20260117-1"},
+		{ID: "adr-20260117-3"},
+		{ID: "adr-001", Location: model.SourceLocation{File: "a.go", Line: 2}},
+	})
+	require.Len(t, problems, 1)
+	assert.Equal(t, `ID "adr-001" doesn't match the date scheme: expected adr-YYYYMMDD-N`, problems[0].Message)
+}
+
+func TestScheme_UnmarshalYAML(t *testing.T) {
+	var s Scheme
+	require.NoError(t, yaml.Unmarshal([]byte("scheme: date\ncategories:\n  infra: inf-\n"), &s))
+	assert.Equal(t, Scheme{Kind: KindDate, Prefix: "adr-", Pad: 3, Categories: map[string]string{"infra": "inf-"}}, s)
+}
+
+func TestScheme_Validate(t *testing.T) {
+	assert.NoError(t, Default().Validate())
+	assert.ErrorContains(t, Scheme{Kind: "uuid"}.Validate(), `invalid ids.scheme "uuid": must be one of: sequential, date, ulid`)
+	assert.ErrorContains(t, Scheme{Kind: KindSequential, Pad: -1}.Validate(), "invalid ids.pad -1")
+	assert.ErrorContains(t, Scheme{Kind: KindSequential, Prefix: "adr/"}.Validate(), `invalid ids.prefix "adr/"`)
+	assert.ErrorContains(t, Scheme{Kind: KindSequential, Categories: map[string]string{"infra": "a/"}}.Validate(), `invalid ids.categories.infra "a/"`)
+}
@@ -0,0 +1,404 @@
+// Package importer reads ADRs written with other tools (adr-tools,
+// Log4brains and MADR) and turns them into adr-buddy ADRs.
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/weaby/adr-buddy/internal/ids"
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+// Formats ADRs can be imported from
+const (
+	FormatADRTools   = "adr-tools"
+	FormatLog4brains = "log4brains"
+	FormatMADR       = "madr"
+)
+
+// Formats lists the supported formats
+var Formats = []string{FormatADRTools, FormatLog4brains, FormatMADR}
+
+// Options controls how imported ADRs are identified
+type Options struct {
+	IDs ids.Scheme // Scheme whose prefix and padding IDs take; ids.Default() if unset
+}
+
+// Decision is an imported ADR and the file it came from
+type Decision struct {
+	ADR    *model.ADR
+	Source string // Path of the source file, relative to the imported directory
+}
+
+// numberedRe matches the file names of adr-tools and MADR: 0001-title.md
+var numberedRe = regexp.MustCompile(`^(\d+)-.+\.md$`)
+
+// log4brainsSkip are files in a Log4brains directory that aren't ADRs
+var log4brainsSkip = map[string]bool{"readme.md": true, "index.md": true, "template.md": true}
+
+// Parse reads the ADRs in dir, written in the given format. Numbered files
+// keep their number; Log4brains files, named by date, are numbered in
+// order. Links between the files become relations between the IDs.
+func Parse(dir, format string, opts Options) ([]*Decision, error) {
+	switch format {
+	case FormatADRTools, FormatLog4brains, FormatMADR:
+	default:
+		return nil, fmt.Errorf("invalid format %q: must be one of: %s", format, strings.Join(Formats, ", "))
+	}
+	if opts.IDs.Kind == "" {
+		opts.IDs = ids.Default()
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	// File name -> ID, so links can be resolved
+	fileIDs := make(map[string]string)
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".md" {
+			continue
+		}
+
+		if format == FormatLog4brains {
+			if !log4brainsSkip[strings.ToLower(name)] {
+				files = append(files, name)
+			}
+			continue
+		}
+
+		m := numberedRe.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+		id := formatID(opts, n)
+		for other, otherID := range fileIDs {
+			if otherID == id {
+				return nil, fmt.Errorf("%s and %s both have number %d", other, name, n)
+			}
+		}
+		fileIDs[name] = id
+		files = append(files, name)
+	}
+
+	sort.Strings(files)
+	if format == FormatLog4brains {
+		for i, name := range files {
+			fileIDs[name] = formatID(opts, i+1)
+		}
+	}
+
+	var decisions []*Decision
+	byID := make(map[string]*model.ADR)
+	var supersededBy [][2]string // [superseded, superseding]
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		doc := parseDocument(string(data))
+		adr := doc.toADR(fileIDs[name])
+		if adr.Name == "" {
+			adr.Name = titleFromFileName(name)
+		}
+
+		for _, l := range doc.links {
+			target, ok := fileIDs[filepath.Base(l.target)]
+			if !ok {
+				continue
+			}
+			switch l.kind {
+			case linkSupersedes:
+				adr.Supersedes = appendUnique(adr.Supersedes, target)
+			case linkSupersededBy:
+				supersededBy = append(supersededBy, [2]string{adr.ID, target})
+			default:
+				adr.Relates = appendUnique(adr.Relates, target)
+			}
+		}
+
+		byID[adr.ID] = adr
+		decisions = append(decisions, &Decision{ADR: adr, Source: name})
+	}
+
+	// "Superseded by" is recorded on the superseding decision
+	for _, pair := range supersededBy {
+		if newer, ok := byID[pair[1]]; ok {
+			newer.Supersedes = appendUnique(newer.Supersedes, pair[0])
+		}
+	}
+
+	sort.Slice(decisions, func(i, j int) bool { return model.IDLess(decisions[i].ADR.ID, decisions[j].ADR.ID) })
+	return decisions, nil
+}
+
+func formatID(opts Options, n int) string {
+	return fmt.Sprintf("%s%0*d", opts.IDs.Prefix, opts.IDs.Pad, n)
+}
+
+// titleFromFileName turns "0001-use-postgres.md" into "Use postgres"
+func titleFromFileName(name string) string {
+	name = strings.TrimSuffix(name, ".md")
+	name = strings.TrimLeft(name, "0123456789")
+	name = strings.TrimSpace(strings.ReplaceAll(strings.Trim(name, "-_"), "-", " "))
+	if name == "" {
+		return "Untitled"
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
+
+// Kinds of links between ADRs
+const (
+	linkSupersedes   = "supersedes"
+	linkSupersededBy = "superseded by"
+	linkRelates      = "relates"
+)
+
+// link is a link to another ADR file, e.g. "Superseded by [3. Use X](0003-use-x.md)"
+type link struct {
+	kind   string
+	target string // Link target, a file name relative to the ADR
+}
+
+// document is what the supported formats have in common: a title,
+// metadata, sections under headings and links to other ADRs
+type document struct {
+	title    string
+	status   string
+	date     string
+	tags     []string
+	sections map[string][]string // Field -> text of each section mapped to it
+	links    []link
+}
+
+var (
+	titleRe    = regexp.MustCompile(`^#\s+(?:\d+\.\s+)?(.+?)\s*$`)
+	headingRe  = regexp.MustCompile(`^(#{2,6})\s+(.+?)\s*#*\s*$`)
+	metaRe     = regexp.MustCompile(`(?i)^\s*(?:[-*]\s+)?([a-z]+(?: [a-z]+){0,2}):\s*(.+?)\s*$`)
+	linkLineRe = regexp.MustCompile(`(?i)^\s*(?:[-*]\s+)?([a-z][a-z ]*?)\s*:?\s*\[[^\]]*\]\(([^)\s]+)\)`)
+	frontRe    = regexp.MustCompile(`(?s)\A---\r?\n(.*?)\r?\n---[ \t]*(?:\r?\n|\z)`)
+)
+
+// sectionFields maps headings, lower-cased, to the ADR field they hold
+var sectionFields = map[string]string{
+	"context":                       "Context",
+	"context and problem statement": "Context",
+	"problem statement":             "Context",
+	"decision drivers":              "Context",
+	"decision":                      "Decision",
+	"decision outcome":              "Decision",
+	"considered options":            "Alternatives",
+	"options":                       "Alternatives",
+	"alternatives":                  "Alternatives",
+	"alternatives considered":       "Alternatives",
+	"pros and cons of the options":  "Alternatives",
+	"consequences":                  "Consequences",
+	"positive consequences":         "Consequences",
+	"negative consequences":         "Consequences",
+}
+
+// parseDocument reads an adr-tools, Log4brains or MADR file
+func parseDocument(content string) *document {
+	doc := &document{sections: make(map[string][]string)}
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	// MADR 3 and later keep metadata in YAML front matter
+	if m := frontRe.FindStringSubmatch(content); m != nil {
+		var front map[string]any
+		if yaml.Unmarshal([]byte(m[1]), &front) == nil {
+			doc.status = stringValue(front["status"])
+			doc.date = stringValue(front["date"])
+			doc.tags = listValue(front["tags"])
+			if l, ok := parseLink(doc.status); ok {
+				doc.links = append(doc.links, l)
+			}
+		}
+		content = content[len(m[0]):]
+	}
+
+	heading, field := "", "Context"
+	var body []string
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(body, "\n"))
+		body = nil
+		if text == "" || field == "" {
+			return
+		}
+		if _, known := sectionFields[strings.ToLower(heading)]; heading != "" && (!known || isSubheading(heading, field)) {
+			text = "**" + heading + "**\n\n" + text
+		}
+		doc.sections[field] = append(doc.sections[field], text)
+	}
+
+	section := ""
+	for _, line := range strings.Split(content, "\n") {
+		if doc.title == "" && section == "" {
+			if m := titleRe.FindStringSubmatch(line); m != nil && !strings.HasPrefix(line, "##") {
+				doc.title = m[1]
+				continue
+			}
+		}
+
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			flush()
+			heading = m[2]
+			section = strings.ToLower(heading)
+			if f, ok := sectionFields[section]; ok {
+				field = f
+			}
+			continue
+		}
+
+		if l, ok := parseLink(line); ok {
+			doc.links = append(doc.links, l)
+			if section == "status" && l.kind == linkSupersededBy && doc.status == "" {
+				doc.status = "superseded"
+			}
+			continue
+		}
+
+		// Metadata lines come before the first section; other text there,
+		// like a Log4brains "Technical Story", is context
+		if section == "" {
+			if m := metaRe.FindStringSubmatch(line); m != nil && !strings.HasPrefix(strings.ToLower(m[1]), "technical story") {
+				switch strings.ToLower(m[1]) {
+				case "status":
+					// "Status: superseded by [ADR-0005](0005-example.md)" in MADR 2
+					if l, ok := parseLink(m[2]); ok {
+						doc.links = append(doc.links, l)
+					}
+					if doc.status == "" {
+						doc.status = m[2]
+					}
+				case "date":
+					if doc.date == "" {
+						doc.date = m[2]
+					}
+				case "tags":
+					doc.tags = append(doc.tags, model.ListValues(m[2])...)
+				}
+				continue
+			}
+			body = append(body, line)
+			continue
+		}
+
+		switch section {
+		case "status":
+			if trimmed := strings.TrimSpace(line); trimmed != "" && doc.status == "" {
+				doc.status = trimmed
+			}
+		case "links":
+		default:
+			body = append(body, line)
+		}
+	}
+	flush()
+
+	return doc
+}
+
+// isSubheading reports whether a known heading is a part of its field
+// that should keep its name, like "Positive Consequences"
+func isSubheading(heading, field string) bool {
+	return !strings.EqualFold(heading, field) && strings.HasSuffix(strings.ToLower(heading), strings.ToLower(field))
+}
+
+// parseLink parses a line like "Superseded by [3. Use X](0003-use-x.md)".
+// Links to anything but a local markdown file aren't ADR links.
+func parseLink(line string) (link, bool) {
+	m := linkLineRe.FindStringSubmatch(line)
+	if m == nil || strings.Contains(m[2], "://") || filepath.Ext(m[2]) != ".md" {
+		return link{}, false
+	}
+
+	kind := strings.ToLower(strings.Join(strings.Fields(m[1]), " "))
+	switch kind {
+	case linkSupersedes, linkSupersededBy:
+	default:
+		kind = linkRelates
+	}
+	return link{kind: kind, target: m[2]}, true
+}
+
+// toADR returns the document as an ADR with the given ID
+func (d *document) toADR(id string) *model.ADR {
+	adr := &model.ADR{
+		ID:           id,
+		Name:         d.title,
+		Status:       normalizeStatus(d.status),
+		Date:         d.date,
+		Context:      d.sections["Context"],
+		Decision:     d.sections["Decision"],
+		Alternatives: d.sections["Alternatives"],
+		Consequences: d.sections["Consequences"],
+		Tags:         d.tags,
+		CustomFields: map[string][]string{},
+	}
+	return adr
+}
+
+// normalizeStatus turns "Accepted" or "superseded by ADR-0005" into the
+// status annotations use
+func normalizeStatus(status string) string {
+	fields := strings.Fields(strings.ToLower(status))
+	if len(fields) == 0 {
+		return "proposed"
+	}
+	return strings.Trim(fields[0], ".,;:!*_")
+}
+
+func stringValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format("2006-01-02")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func listValue(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return model.ListValues(v)
+	case []any:
+		var items []string
+		for _, item := range v {
+			if s := stringValue(item); s != "" {
+				items = append(items, s)
+			}
+		}
+		return items
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weaby/adr-buddy/internal/ids"
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestParse_ADRTools(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"0001-record-architecture-decisions.md": `# 1. Record architecture decisions
+
+Date: 2018-02-21
+
+## Status
+
+Accepted
+
+## Context
+
+We need to record the architectural decisions made on this project.
+
+## Decision
+
+We will use Architecture Decision Records.
+
+## Consequences
+
+See Michael Nygard's article.
+`,
+		"0002-use-polling.md": `# 2. Use polling
+
+Date: 2018-03-01
+
+## Status
+
+Superseded by [3. Use an event bus](0003-use-an-event-bus.md)
+
+## Context
+
+Updates must reach other services.
+`,
+		"0003-use-an-event-bus.md": `# 3. Use an event bus
+
+Date: 2019-05-10
+
+## Status
+
+Accepted
+
+Supersedes [2. Use polling](0002-use-polling.md)
+
+Amends [1. Record architecture decisions](0001-record-architecture-decisions.md)
+
+## Context
+
+Polling is slow.
+`,
+		"README.md": "# Decisions\n",
+	})
+
+	decisions, err := Parse(dir, FormatADRTools, Options{})
+	require.NoError(t, err)
+	require.Len(t, decisions, 3)
+
+	first := decisions[0]
+	assert.Equal(t, "0001-record-architecture-decisions.md", first.Source)
+	assert.Equal(t, "adr-001", first.ADR.ID)
+	assert.Equal(t, "Record architecture decisions", first.ADR.Name)
+	assert.Equal(t, "accepted", first.ADR.Status)
+	assert.Equal(t, "2018-02-21", first.ADR.Date)
+	assert.Equal(t, []string{"We need to record the architectural decisions made on this project."}, first.ADR.Context)
+	assert.Equal(t, []string{"We will use Architecture Decision Records."}, first.ADR.Decision)
+	assert.Equal(t, []string{"See Michael Nygard's article."}, first.ADR.Consequences)
+
+	assert.Equal(t, "superseded", decisions[1].ADR.Status)
+	assert.Empty(t, decisions[1].ADR.Supersedes)
+
+	bus := decisions[2].ADR
+	assert.Equal(t, "adr-003", bus.ID)
+	assert.Equal(t, []string{"adr-002"}, bus.Supersedes)
+	assert.Equal(t, []string{"adr-001"}, bus.Relates)
+}
+
+func TestParse_MADR(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"0001-use-markdown.md": `---
+status: accepted
+date: 2023-06-01
+tags: [docs, process]
+---
+# Use Markdown for decisions
+
+## Context and Problem Statement
+
+Where do we write decisions down?
+
+## Considered Options
+
+* Markdown
+* Wiki
+
+## Decision Outcome
+
+Chosen option: "Markdown", because it lives with the code.
+
+### Consequences
+
+* Good, because reviews happen in pull requests
+
+## More Information
+
+Discussed in the architecture guild.
+`,
+		"0002-use-yaml.md": `# Use YAML
+
+* Status: superseded by [ADR-0003](0003-use-toml.md)
+* Date: 2020-01-01
+
+## Context and Problem Statement
+
+Config format.
+
+## Decision Outcome
+
+YAML.
+
+### Positive Consequences
+
+* Familiar
+`,
+		"0003-use-toml.md": "# Use TOML\n\n* Status: accepted\n",
+		"adr-template.md":  "# {short title}\n",
+	})
+
+	decisions, err := Parse(dir, FormatMADR, Options{})
+	require.NoError(t, err)
+	require.Len(t, decisions, 3)
+
+	md := decisions[0].ADR
+	assert.Equal(t, "Use Markdown for decisions", md.Name)
+	assert.Equal(t, "accepted", md.Status)
+	assert.Equal(t, "2023-06-01", md.Date)
+	assert.Equal(t, []string{"docs", "process"}, md.Tags)
+	assert.Equal(t, []string{"Where do we write decisions down?"}, md.Context)
+	assert.Equal(t, []string{"* Markdown\n* Wiki"}, md.Alternatives)
+	assert.Equal(t, []string{`Chosen option: "Markdown", because it lives with the code.`}, md.Decision)
+	assert.Equal(t, []string{
+		"* Good, because reviews happen in pull requests",
+		"**More Information**\n\nDiscussed in the architecture guild.",
+	}, md.Consequences, "unknown sections stay with the section before them")
+
+	yml := decisions[1].ADR
+	assert.Equal(t, "superseded", yml.Status)
+	assert.Equal(t, "2020-01-01", yml.Date)
+	assert.Equal(t, []string{"**Positive Consequences**\n\n* Familiar"}, yml.Consequences)
+
+	assert.Equal(t, []string{"adr-002"}, decisions[2].ADR.Supersedes)
+}
+
+func TestParse_Log4brains(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"20200101-use-log4brains.md": `# Use Log4brains to manage the ADRs
+
+- Status: accepted
+- Deciders: Alice, Bob
+- Date: 2020-01-01
+- Tags: doc
+
+Technical Story: the docs are scattered.
+
+## Context and Problem Statement
+
+We want to record decisions.
+`,
+		"20210315-publish-to-pages.md": `# Publish the knowledge base to GitHub Pages
+
+- Status: proposed
+
+## Decision Outcome
+
+Publish it.
+
+## Links
+
+- Relates to [Use Log4brains](20200101-use-log4brains.md)
+- [External](https://example.com)
+`,
+		"template.md": "# [short title]\n",
+		"index.md":    "# Knowledge base\n",
+	})
+
+	decisions, err := Parse(dir, FormatLog4brains, Options{IDs: ids.Scheme{Kind: ids.KindSequential, Prefix: "ADR-", Pad: 4}})
+	require.NoError(t, err)
+	require.Len(t, decisions, 2)
+
+	first := decisions[0].ADR
+	assert.Equal(t, "ADR-0001", first.ID)
+	assert.Equal(t, "Use Log4brains to manage the ADRs", first.Name)
+	assert.Equal(t, "2020-01-01", first.Date)
+	assert.Equal(t, []string{"doc"}, first.Tags)
+	assert.Equal(t, []string{"Technical Story: the docs are scattered.", "We want to record decisions."}, first.Context)
+
+	second := decisions[1].ADR
+	assert.Equal(t, "ADR-0002", second.ID)
+	assert.Equal(t, "proposed", second.Status)
+	assert.Equal(t, []string{"ADR-0001"}, second.Relates)
+	assert.Equal(t, []string{"Publish it."}, second.Decision)
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse(t.TempDir(), "jira", Options{})
+	assert.ErrorContains(t, err, `invalid format "jira": must be one of: adr-tools, log4brains, madr`)
+
+	dir := writeFiles(t, map[string]string{"0001-a.md": "# A\n", "001-b.md": "# B\n"})
+	_, err = Parse(dir, FormatADRTools, Options{})
+	assert.ErrorContains(t, err, "both have number 1")
+
+	_, err = Parse(filepath.Join(dir, "missing"), FormatMADR, Options{})
+	assert.ErrorContains(t, err, "failed to read")
+}
+
+func TestParse_TitleFromFileName(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"0007-use-go-modules.md": "Some text\n"})
+	decisions, err := Parse(dir, FormatADRTools, Options{})
+	require.NoError(t, err)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, "adr-007", decisions[0].ADR.ID)
+	assert.Equal(t, "Use go modules", decisions[0].ADR.Name)
+	assert.Equal(t, "proposed", decisions[0].ADR.Status)
+}
+
+func TestSuggest(t *testing.T) {
+	files := map[string]string{
+		"internal/persistence/db.go": "package db\n\n// Connect opens the PostgreSQL pool\nfunc Connect() {}\n",
+		"internal/cache/redis.go":    "package cache\n",
+		"cmd/main.go":                "package main\n// uses postgresql via db\n",
+	}
+
+	suggestions := Suggest(&model.ADR{Name: "Use PostgreSQL for persistence"}, files, 2)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, Suggestion{File: "internal/persistence/db.go", Line: 3, Matches: []string{"postgresql", "persistence"}}, suggestions[0])
+	assert.Equal(t, "cmd/main.go", suggestions[1].File)
+
+	assert.Empty(t, Suggest(&model.ADR{Name: "Use it"}, files, 3), "no usable words")
+}
@@ -0,0 +1,103 @@
+package importer
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+// Suggestion is a place in the code where an imported decision could be
+// annotated
+type Suggestion struct {
+	File    string   // Path relative to the project root
+	Line    int      // First line mentioning one of the matches
+	Matches []string // Words of the decision's title found in the file
+}
+
+// stopWords are too common in ADR titles to point at code
+var stopWords = map[string]bool{
+	"about": true, "after": true, "also": true, "architecture": true, "decision": true,
+	"decisions": true, "from": true, "have": true, "into": true, "instead": true,
+	"over": true, "record": true, "records": true, "should": true, "than": true,
+	"that": true, "their": true, "them": true, "this": true, "using": true,
+	"what": true, "when": true, "where": true, "which": true, "with": true,
+}
+
+// keywords returns the distinct words of a title worth searching code for
+func keywords(title string) []string {
+	var words []string
+	seen := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) < 4 || stopWords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		words = append(words, w)
+	}
+	return words
+}
+
+// Suggest ranks files, keyed by path, by how many words of the decision's
+// title they mention, in their path or their content, and returns the best
+// n. Files mentioning none of them are never suggested.
+func Suggest(adr *model.ADR, files map[string]string, n int) []Suggestion {
+	words := keywords(adr.Name)
+	if len(words) == 0 {
+		return nil
+	}
+
+	type scored struct {
+		Suggestion
+		score int
+	}
+	var candidates []scored
+	for path, content := range files {
+		lowerPath := strings.ToLower(path)
+		lines := strings.Split(strings.ToLower(content), "\n")
+
+		c := scored{Suggestion: Suggestion{File: path}}
+		for _, w := range words {
+			found := false
+			if strings.Contains(lowerPath, w) {
+				c.score += 2
+				found = true
+			}
+			for i, line := range lines {
+				if strings.Contains(line, w) {
+					c.score++
+					found = true
+					if c.Line == 0 || i+1 < c.Line {
+						c.Line = i + 1
+					}
+					break
+				}
+			}
+			if found {
+				c.Matches = append(c.Matches, w)
+			}
+		}
+		if c.score > 0 {
+			if c.Line == 0 {
+				c.Line = 1
+			}
+			candidates = append(candidates, c)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].File < candidates[j].File
+	})
+
+	var suggestions []Suggestion
+	for i := 0; i < len(candidates) && i < n; i++ {
+		suggestions = append(suggestions, candidates[i].Suggestion)
+	}
+	return suggestions
+}
@@ -0,0 +1,184 @@
+// Package lifecycle defines the statuses decisions can have and the
+// transitions allowed between them.
+package lifecycle
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultStatuses are the statuses decisions can have unless configured
+var DefaultStatuses = []string{"proposed", "accepted", "rejected", "deprecated", "superseded"}
+
+// InitialStatus is the status of decisions whose annotations set none
+const InitialStatus = "proposed"
+
+// DefaultColors are the colors of the default statuses
+var DefaultColors = map[string]string{
+	"proposed":   "#cfe2ff",
+	"accepted":   "#d1e7dd",
+	"deprecated": "#fff3cd",
+	"superseded": "#e2e3e5",
+	"rejected":   "#f8d7da",
+}
+
+// UnknownColor is the color of statuses that aren't in the lifecycle
+const UnknownColor = "#eeeeee"
+
+// palette colors configured statuses that have no color of their own, by
+// their position in the lifecycle
+var palette = []string{"#e0cffc", "#cff4fc", "#ffe5d0", "#d2f4ea", "#f7d6e6", "#fde68a"}
+
+// colorRe matches the colors statuses can be given
+var colorRe = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Lifecycle is the status vocabulary of decisions and which statuses each
+// status may change to
+type Lifecycle struct {
+	Statuses    []string            `yaml:"values"`
+	Transitions map[string][]string `yaml:"transitions,omitempty"` // Statuses each status may change to; any change is allowed if empty
+	Colors      map[string]string   `yaml:"colors,omitempty"`      // Color of each status as #rrggbb, for badges, graphs and the site
+}
+
+// Default returns the default lifecycle: the default statuses, with any
+// change allowed
+func Default() Lifecycle {
+	return Lifecycle{Statuses: append([]string(nil), DefaultStatuses...)}
+}
+
+// UnmarshalYAML fills in the statuses from the default lifecycle if a
+// config leaves them out
+func (l *Lifecycle) UnmarshalYAML(value *yaml.Node) error {
+	type plain Lifecycle
+	var p plain
+	if err := value.Decode(&p); err != nil {
+		return err
+	}
+	if len(p.Statuses) == 0 {
+		p.Statuses = Default().Statuses
+	}
+	*l = Lifecycle(p)
+	return nil
+}
+
+// Validate checks that the statuses are distinct, include the initial one,
+// and that transitions only name them
+func (l Lifecycle) Validate() error {
+	seen := make(map[string]bool)
+	for _, status := range l.Statuses {
+		if status == "" || strings.ContainsAny(status, " \t\n,") {
+			return fmt.Errorf("invalid statuses.values %q: must be a single word", status)
+		}
+		if seen[status] {
+			return fmt.Errorf("invalid statuses.values: %q is listed twice", status)
+		}
+		seen[status] = true
+	}
+	if !seen[InitialStatus] {
+		return fmt.Errorf("invalid statuses.values: must include %q, the status of annotations without one", InitialStatus)
+	}
+
+	for _, status := range sortedKeys(l.Colors) {
+		if !seen[status] {
+			return fmt.Errorf("invalid statuses.colors.%s: unknown status", status)
+		}
+		if !colorRe.MatchString(l.Colors[status]) {
+			return fmt.Errorf("invalid statuses.colors.%s %q: must be a color like #d1e7dd", status, l.Colors[status])
+		}
+	}
+
+	for _, from := range sortedKeys(l.Transitions) {
+		if !seen[from] {
+			return fmt.Errorf("invalid statuses.transitions.%s: unknown status", from)
+		}
+		for _, to := range l.Transitions[from] {
+			if !seen[to] {
+				return fmt.Errorf("invalid statuses.transitions.%s: unknown status %q", from, to)
+			}
+		}
+	}
+	return nil
+}
+
+// Valid reports whether status is one of the statuses
+func (l Lifecycle) Valid(status string) bool {
+	for _, s := range l.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the statuses a decision may change to from status
+func (l Lifecycle) Next(status string) []string {
+	if len(l.Transitions) == 0 {
+		var next []string
+		for _, s := range l.Statuses {
+			if s != status {
+				next = append(next, s)
+			}
+		}
+		return next
+	}
+	return l.Transitions[status]
+}
+
+// Final reports whether a decision can't change from status at all
+func (l Lifecycle) Final(status string) bool {
+	return l.Valid(status) && len(l.Next(status)) == 0
+}
+
+// Color returns the color of a status as #rrggbb: the configured one, that
+// of a default status, or one picked by the status's position among the
+// statuses. Statuses that aren't in the lifecycle get UnknownColor.
+func (l Lifecycle) Color(status string) string {
+	if !l.Valid(status) {
+		status = strings.ToLower(status)
+	}
+	if color, ok := l.Colors[status]; ok {
+		return color
+	}
+	for i, s := range l.Statuses {
+		if s != status {
+			continue
+		}
+		if color, ok := DefaultColors[status]; ok {
+			return color
+		}
+		return palette[i%len(palette)]
+	}
+	return UnknownColor
+}
+
+// CheckTransition returns an error if a decision may not change from one
+// status to another. Changes from unknown statuses aren't checked, since
+// the status itself is the problem.
+func (l Lifecycle) CheckTransition(from, to string) error {
+	if from == to || !l.Valid(from) {
+		return nil
+	}
+	next := l.Next(from)
+	for _, s := range next {
+		if s == to {
+			return nil
+		}
+	}
+	if len(next) == 0 {
+		return fmt.Errorf("can't change status from %s to %s: %s is final, record a new decision instead", from, to, from)
+	}
+	return fmt.Errorf("can't change status from %s to %s: %s may only change to %s", from, to, from, strings.Join(next, ", "))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
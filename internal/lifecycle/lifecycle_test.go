@@ -0,0 +1,74 @@
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// org is a lifecycle with statuses of its own and restricted transitions
+var org = Lifecycle{
+	Statuses: []string{"draft", "proposed", "in-review", "accepted", "rejected", "retired"},
+	Transitions: map[string][]string{
+		"draft":     {"proposed"},
+		"proposed":  {"in-review", "rejected"},
+		"in-review": {"accepted", "rejected", "proposed"},
+		"accepted":  {"retired"},
+	},
+}
+
+func TestLifecycle_CheckTransition(t *testing.T) {
+	assert.NoError(t, org.CheckTransition("in-review", "accepted"))
+	assert.NoError(t, org.CheckTransition("accepted", "accepted"))
+	assert.NoError(t, org.CheckTransition("unknown", "accepted"))
+	assert.EqualError(t, org.CheckTransition("draft", "accepted"), "can't change status from draft to accepted: draft may only change to proposed")
+	assert.EqualError(t, org.CheckTransition("rejected", "accepted"), "can't change status from rejected to accepted: rejected is final, record a new decision instead")
+
+	// Without transitions, any change is allowed
+	assert.NoError(t, Default().CheckTransition("rejected", "accepted"))
+}
+
+func TestLifecycle_Color(t *testing.T) {
+	colored := org
+	colored.Colors = map[string]string{"in-review": "#ffe5b4"}
+
+	assert.Equal(t, "#ffe5b4", colored.Color("in-review"))
+	assert.Equal(t, "#d1e7dd", colored.Color("accepted"))
+	assert.Equal(t, "#d1e7dd", colored.Color("Accepted"))
+	assert.Equal(t, palette[0], colored.Color("draft"))
+	assert.Equal(t, palette[5], colored.Color("retired"))
+	assert.Equal(t, UnknownColor, colored.Color("deprecated"))
+	assert.Equal(t, UnknownColor, Default().Color(""))
+}
+
+func TestLifecycle_Next(t *testing.T) {
+	assert.Equal(t, []string{"accepted", "rejected", "proposed"}, org.Next("in-review"))
+	assert.Empty(t, org.Next("retired"))
+	assert.True(t, org.Final("retired"))
+	assert.False(t, org.Final("accepted"))
+	assert.False(t, org.Final("unknown"))
+
+	assert.Equal(t, []string{"proposed", "rejected", "deprecated", "superseded"}, Default().Next("accepted"))
+	assert.False(t, Default().Final("superseded"))
+}
+
+func TestLifecycle_UnmarshalYAML(t *testing.T) {
+	var l Lifecycle
+	require.NoError(t, yaml.Unmarshal([]byte("transitions:\n  proposed: [accepted]\n"), &l))
+	assert.Equal(t, DefaultStatuses, l.Statuses)
+	assert.Equal(t, map[string][]string{"proposed": {"accepted"}}, l.Transitions)
+}
+
+func TestLifecycle_Validate(t *testing.T) {
+	assert.NoError(t, Default().Validate())
+	assert.NoError(t, org.Validate())
+	assert.ErrorContains(t, Lifecycle{Statuses: []string{"proposed", "in review"}}.Validate(), `invalid statuses.values "in review"`)
+	assert.ErrorContains(t, Lifecycle{Statuses: []string{"proposed", "proposed"}}.Validate(), `"proposed" is listed twice`)
+	assert.ErrorContains(t, Lifecycle{Statuses: []string{"draft"}}.Validate(), `must include "proposed"`)
+	assert.ErrorContains(t, Lifecycle{Statuses: []string{"proposed"}, Colors: map[string]string{"draft": "#ffffff"}}.Validate(), "invalid statuses.colors.draft: unknown status")
+	assert.ErrorContains(t, Lifecycle{Statuses: []string{"proposed"}, Colors: map[string]string{"proposed": "blue"}}.Validate(), `invalid statuses.colors.proposed "blue": must be a color like #d1e7dd`)
+	assert.ErrorContains(t, Lifecycle{Statuses: []string{"proposed"}, Transitions: map[string][]string{"draft": nil}}.Validate(), "invalid statuses.transitions.draft: unknown status")
+	assert.ErrorContains(t, Lifecycle{Statuses: []string{"proposed"}, Transitions: map[string][]string{"proposed": {"done"}}}.Validate(), `invalid statuses.transitions.proposed: unknown status "done"`)
+}
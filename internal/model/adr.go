@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 )
 
 // SourceLocation represents a location in source code
@@ -61,7 +62,14 @@ type ADR struct {
 	Decision     []string         // Merged from all annotations
 	Alternatives []string         // Merged from all annotations
 	Consequences []string         // Merged from all annotations
+	Tags         []string         // Union of all annotation tags
+	Supersedes   []string         // IDs of decisions this one replaces
+	Relates      []string         // IDs of related decisions
 	Locations    []SourceLocation // All code locations
+
+	// CustomFields holds other @decision.* fields, keyed by field name, with
+	// the value of every annotation that sets one
+	CustomFields map[string][]string
 }
 
 // OutputPath returns the file path where this ADR should be written
@@ -72,3 +80,19 @@ func (a *ADR) OutputPath(outputDir string) string {
 	}
 	return filepath.Join(outputDir, a.Category, filename)
 }
+
+// ListValues splits a list-valued annotation field into its items. Items
+// may be comma-separated or given one per line as "- item".
+func ListValues(value string) []string {
+	var items []string
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimLeft(line, "-*"))
+		for _, item := range strings.Split(line, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
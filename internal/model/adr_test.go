@@ -107,3 +107,10 @@ func TestADR_OutputPath(t *testing.T) {
 		})
 	}
 }
+
+func TestListValues(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, ListValues("a, b"))
+	assert.Equal(t, []string{"a", "b", "c"}, ListValues("a\n- b\n* c"))
+	assert.Nil(t, ListValues(""))
+	assert.Nil(t, ListValues("\n-\n"))
+}
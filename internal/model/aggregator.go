@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -27,7 +28,11 @@ func Aggregate(annotations []*Annotation) ([]*ADR, error) {
 				Decision:     []string{},
 				Alternatives: []string{},
 				Consequences: []string{},
+				Tags:         []string{},
+				Supersedes:   []string{},
+				Relates:      []string{},
 				Locations:    []SourceLocation{},
+				CustomFields: map[string][]string{},
 			}
 			adrMap[ann.ID] = adr
 		} else {
@@ -56,6 +61,17 @@ func Aggregate(annotations []*Annotation) ([]*ADR, error) {
 			adr.Consequences = append(adr.Consequences, ann.Consequences)
 		}
 
+		// Union list fields, keeping order of first appearance
+		adr.Tags = appendUnique(adr.Tags, ListValues(ann.CustomFields["tags"])...)
+		adr.Supersedes = appendUnique(adr.Supersedes, ListValues(ann.CustomFields["supersedes"])...)
+		adr.Relates = appendUnique(adr.Relates, ListValues(ann.CustomFields["relates"])...)
+
+		for field, value := range ann.CustomFields {
+			if value = strings.TrimSpace(value); !listFields[field] && value != "" {
+				adr.CustomFields[field] = append(adr.CustomFields[field], value)
+			}
+		}
+
 		// Add location
 		adr.Locations = append(adr.Locations, ann.Location)
 	}
@@ -68,3 +84,23 @@ func Aggregate(annotations []*Annotation) ([]*ADR, error) {
 
 	return adrs, nil
 }
+
+// listFields are the custom fields Aggregate turns into ADR lists
+var listFields = map[string]bool{"tags": true, "supersedes": true, "relates": true}
+
+// appendUnique appends the values not already in list
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range list {
+			if existing == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
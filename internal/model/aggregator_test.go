@@ -86,3 +86,55 @@ func TestAggregate_ConflictingNames(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "conflicting names")
 }
+
+func TestAggregate_ListFields(t *testing.T) {
+	annotations := []*Annotation{
+		{
+			ID:   "adr-5",
+			Name: "Event bus",
+			CustomFields: map[string]string{
+				"tags":       "messaging, infra",
+				"supersedes": "adr-2",
+			},
+			Location: SourceLocation{File: "a.go", Line: 1},
+		},
+		{
+			ID:   "adr-5",
+			Name: "Event bus",
+			CustomFields: map[string]string{
+				"tags":    "\n- infra\n- kafka",
+				"relates": "adr-3, adr-4",
+			},
+			Location: SourceLocation{File: "b.go", Line: 1},
+		},
+	}
+
+	adrs, err := Aggregate(annotations)
+	assert.NoError(t, err)
+	assert.Len(t, adrs, 1)
+	assert.Equal(t, []string{"messaging", "infra", "kafka"}, adrs[0].Tags)
+	assert.Equal(t, []string{"adr-2"}, adrs[0].Supersedes)
+	assert.Equal(t, []string{"adr-3", "adr-4"}, adrs[0].Relates)
+}
+
+func TestAggregate_CustomFields(t *testing.T) {
+	annotations := []*Annotation{
+		{
+			ID:           "adr-5",
+			Name:         "Event bus",
+			CustomFields: map[string]string{"owner": "platform-team", "tags": "infra"},
+			Location:     SourceLocation{File: "a.go", Line: 1},
+		},
+		{
+			ID:           "adr-5",
+			Name:         "Event bus",
+			CustomFields: map[string]string{"owner": "\ndata-team", "ticket": ""},
+			Location:     SourceLocation{File: "b.go", Line: 1},
+		},
+	}
+
+	adrs, err := Aggregate(annotations)
+	assert.NoError(t, err)
+	assert.Len(t, adrs, 1)
+	assert.Equal(t, map[string][]string{"owner": {"platform-team", "data-team"}}, adrs[0].CustomFields)
+}
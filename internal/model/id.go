@@ -0,0 +1,40 @@
+package model
+
+import (
+	"strconv"
+	"unicode"
+)
+
+// IDLess orders ADR IDs with embedded numbers by value, so "adr-2" sorts
+// before "adr-10"
+func IDLess(a, b string) bool {
+	ar, br := []rune(a), []rune(b)
+	i, j := 0, 0
+	for i < len(ar) && j < len(br) {
+		if unicode.IsDigit(ar[i]) && unicode.IsDigit(br[j]) {
+			si := i
+			for i < len(ar) && unicode.IsDigit(ar[i]) {
+				i++
+			}
+			sj := j
+			for j < len(br) && unicode.IsDigit(br[j]) {
+				j++
+			}
+			an, aerr := strconv.ParseUint(string(ar[si:i]), 10, 64)
+			bn, berr := strconv.ParseUint(string(br[sj:j]), 10, 64)
+			if aerr == nil && berr == nil && an != bn {
+				return an < bn
+			}
+			if as, bs := string(ar[si:i]), string(br[sj:j]); as != bs {
+				return as < bs
+			}
+			continue
+		}
+		if ar[i] != br[j] {
+			return ar[i] < br[j]
+		}
+		i++
+		j++
+	}
+	return len(ar)-i < len(br)-j
+}
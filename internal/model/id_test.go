@@ -0,0 +1,14 @@
+package model
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDLess(t *testing.T) {
+	ids := []string{"adr-10", "adr-2", "adr-1", "b-1", "adr-02", "adr"}
+	sort.Slice(ids, func(i, j int) bool { return IDLess(ids[i], ids[j]) })
+	assert.Equal(t, []string{"adr", "adr-1", "adr-02", "adr-2", "adr-10", "b-1"}, ids)
+}
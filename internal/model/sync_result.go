@@ -1,18 +1,27 @@
 package model
 
-// FileChanges tracks what files will be created, modified, or deleted
+// FileChanges tracks what files will be created, modified, moved, or deleted
 type FileChanges struct {
-	Created  []string `json:"created"`
-	Modified []string `json:"modified"`
-	Deleted  []string `json:"deleted"`
+	Created  []string   `json:"created"`
+	Modified []string   `json:"modified"`
+	Moved    []FileMove `json:"moved"`
+	Deleted  []string   `json:"deleted"`
+}
+
+// FileMove records an ADR file relocated because its category changed
+type FileMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
 }
 
 // ADRChange represents a single ADR that will be changed
 type ADRChange struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
-	Action   string `json:"action"` // "create", "update", "delete"
+	Action   string `json:"action"` // "create", "update", "move", "deprecate", "archive", "delete"
 	FilePath string `json:"file_path"`
+	OldPath  string `json:"old_path,omitempty"` // Previous path for "move"
+	Diff     string `json:"diff,omitempty"`     // Unified diff, populated on dry runs
 }
 
 // SyncResult represents the output of sync --dry-run command
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Field is a field of a new annotation block
+type Field struct {
+	Name  string // Field name without the @decision. prefix
+	Value string // Lines separated by "\n"; may be empty
+}
+
+// declarationPattern matches the start of a declaration in common
+// languages, up to the declared name: modifiers, a keyword and, for Go
+// methods, the receiver
+const declarationPattern = `^\s*(?:(?:export|public|private|protected|internal|static|async|pub(?:\([^)]*\))?|abstract|final|default|override|sealed|open|data|unsafe)\s+)*` +
+	`(?:func|type|class|def|interface|struct|fn|function|enum|trait|module|impl|const|var|let|val|object|record)\s+(?:\([^)]*\)\s*)?`
+
+// FormatBlock returns the lines of an annotation block in the comment style
+// of filename, each starting with indent
+func FormatBlock(filename, indent string, fields []Field) []string {
+	commentStyle := detectCommentStyle(filename)
+	continuation := indent + commentStyle + defaultContinuation
+
+	var lines []string
+	for _, f := range fields {
+		var value []string
+		for _, line := range strings.Split(f.Value, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				value = append(value, line)
+			}
+		}
+		lines = append(lines, renderField(indent+commentStyle+" @decision."+f.Name+": ", continuation, value, true, 0)...)
+	}
+	return lines
+}
+
+// InsertBlock inserts an annotation block with fields above line (from 1)
+// of a file, indented like that line, and returns the line the block starts
+// on. A line past the end appends the block. An empty comment line separates
+// the block from annotations right above or below it, which it would
+// otherwise run into.
+func InsertBlock(filename, content string, line int, fields []Field) (string, int, error) {
+	newline := "\n"
+	if strings.Contains(content, "\r\n") {
+		newline = "\r\n"
+	}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	// A trailing newline ends the last line rather than starting another
+	last := len(lines)
+	if last > 0 && lines[last-1] == "" {
+		last--
+	}
+	if line < 1 || line > last+1 {
+		return "", 0, fmt.Errorf("line %d is outside %s (%d lines)", line, filename, last)
+	}
+
+	commentStyle := detectCommentStyle(filename)
+	indent := ""
+	atField := false
+	if line <= last {
+		target := lines[line-1]
+		indent = target[:len(target)-len(strings.TrimLeft(target, " \t"))]
+		_, _, atField = parseAnnotationField(target, commentStyle)
+		if !atField && inAnnotation(lines, line-1, commentStyle) {
+			return "", 0, fmt.Errorf("line %d of %s is inside an annotation block", line, filename)
+		}
+	}
+	separator := indent + commentStyle
+
+	var block []string
+	if line > 1 && inAnnotation(lines, line-2, commentStyle) {
+		block = append(block, separator)
+	}
+	start := line + len(block)
+	block = append(block, FormatBlock(filename, indent, fields)...)
+	if atField {
+		block = append(block, separator)
+	}
+
+	updated := append([]string(nil), lines[:line-1]...)
+	updated = append(updated, block...)
+	updated = append(updated, lines[line-1:]...)
+	return strings.Join(updated, newline), start, nil
+}
+
+// inAnnotation reports whether the line at index i belongs to an annotation
+// block: a field line or a continuation of one
+func inAnnotation(lines []string, i int, commentStyle string) bool {
+	for ; i >= 0; i-- {
+		if _, _, ok := parseAnnotationField(lines[i], commentStyle); ok {
+			return true
+		}
+		if !isContinuationLine(lines[i], commentStyle) {
+			return false
+		}
+	}
+	return false
+}
+
+// FindSymbol returns the line (from 1) declaring symbol, for placing an
+// annotation above it. Decorators and attributes right above the
+// declaration are kept with it, so the line returned is the first of them.
+func FindSymbol(filename, content, symbol string) (int, error) {
+	declarationRe := regexp.MustCompile(declarationPattern + regexp.QuoteMeta(symbol) + `\b`)
+	commentStyle := detectCommentStyle(filename)
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if !declarationRe.MatchString(line) {
+			continue
+		}
+		for i > 0 {
+			above := strings.TrimSpace(lines[i-1])
+			if (!strings.HasPrefix(above, "@") && !strings.HasPrefix(above, "#[")) || strings.HasPrefix(above, commentStyle+" ") {
+				break
+			}
+			i--
+		}
+		return i + 1, nil
+	}
+	return 0, fmt.Errorf("no declaration of %s found in %s", symbol, filename)
+}
@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+// parseString parses content as a file with the given name
+func parseString(t *testing.T, name, content string) []*model.Annotation {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	annotations, err := ParseFile(path)
+	require.NoError(t, err)
+	return annotations
+}
+
+var newFields = []Field{
+	{Name: "id", Value: "adr-004"},
+	{Name: "name", Value: "Use Kafka"},
+	{Name: "context"},
+}
+
+func TestInsertBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		line    int
+		want    string
+		others  []string // IDs of the annotations already in content
+	}{
+		{
+			name:    "indented like the line",
+			file:    "pub.go",
+			content: "package pub\n\ntype P struct{}\n\nfunc (p P) Send() {\n\tp.publish()\n}\n",
+			line:    6,
+			want:    "package pub\n\ntype P struct{}\n\nfunc (p P) Send() {\n\t// @decision.id: adr-004\n\t// @decision.name: Use Kafka\n\t// @decision.context:\n\tp.publish()\n}\n",
+		},
+		{
+			name:    "hash comments and CRLF",
+			file:    "app.py",
+			content: "import os\r\n",
+			line:    1,
+			want:    "# @decision.id: adr-004\r\n# @decision.name: Use Kafka\r\n# @decision.context:\r\nimport os\r\n",
+		},
+		{
+			name:    "below an annotated line",
+			file:    "pub.go",
+			content: "package pub\n\n// @decision.id: adr-002\n// @decision.context: Sends are\n//   batched.\nfunc B() {}\n",
+			line:    6,
+			want:    "package pub\n\n// @decision.id: adr-002\n// @decision.context: Sends are\n//   batched.\n//\n// @decision.id: adr-004\n// @decision.name: Use Kafka\n// @decision.context:\nfunc B() {}\n",
+			others:  []string{"adr-002"},
+		},
+		{
+			name:    "above an annotation",
+			file:    "app.py",
+			content: "# @decision.id: adr-002\ndef b(): pass\n",
+			line:    1,
+			want:    "# @decision.id: adr-004\n# @decision.name: Use Kafka\n# @decision.context:\n#\n# @decision.id: adr-002\ndef b(): pass\n",
+			others:  []string{"adr-002"},
+		},
+		{
+			name:    "appended after the last line",
+			file:    "pub.go",
+			content: "package pub\n",
+			line:    2,
+			want:    "package pub\n// @decision.id: adr-004\n// @decision.name: Use Kafka\n// @decision.context:\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, start, err := InsertBlock(tt.file, tt.content, tt.line, newFields)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			var ids []string
+			for _, ann := range parseString(t, tt.file, got) {
+				ids = append(ids, ann.ID)
+				if ann.ID == "adr-004" {
+					assert.Equal(t, start, ann.Location.Line)
+					assert.Equal(t, "Use Kafka", ann.Name)
+				}
+			}
+			assert.ElementsMatch(t, append(tt.others, "adr-004"), ids)
+		})
+	}
+
+	_, _, err := InsertBlock("pub.go", "package pub\n", 3, newFields)
+	assert.ErrorContains(t, err, "line 3 is outside pub.go (1 lines)")
+
+	_, _, err = InsertBlock("pub.go", "// @decision.id: adr-002\n// @decision.context: Sends are\n//   batched.\nfunc B() {}\n", 3, newFields)
+	assert.ErrorContains(t, err, "line 3 of pub.go is inside an annotation block")
+}
+
+func TestFindSymbol(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		symbol  string
+		want    int
+	}{
+		{"go method", "pub.go", "package pub\n\n// Send sends\nfunc (p *P) Send() {}\n", "Send", 4},
+		{"go type", "pub.go", "package pub\n\nvar sender = 1\n\ntype Sender struct{}\n", "Sender", 5},
+		{"python decorators", "app.py", "import os\n\n@app.route('/')\n@login_required\ndef index():\n    pass\n", "index", 3},
+		{"typescript export", "app.ts", "const x = send();\nexport async function send() {}\n", "send", 2},
+		{"rust attributes", "lib.rs", "#[derive(Debug)]\npub(crate) struct Queue;\n", "Queue", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindSymbol(tt.file, tt.content, tt.symbol)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := FindSymbol("pub.go", "package pub\n\nfunc main() { Send() }\n", "Send")
+	assert.ErrorContains(t, err, "no declaration of Send found in pub.go")
+}
@@ -0,0 +1,253 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldEdit is a change to one field of an annotation block
+type FieldEdit struct {
+	Line  int    // Line the annotation block starts on, as in its location
+	Field string // Field name without the @decision. prefix
+	Value string // New value, lines separated by "\n"; empty removes the field
+}
+
+// fieldOrder is where new fields are inserted: after the last field of the
+// block that comes before them in this order
+var fieldOrder = []string{"id", "name", "status", "status_date", "category", "tags", "supersedes", "relates", "context", "decision", "alternatives", "consequences"}
+
+// defaultContinuation is what follows the comment marker on continuation
+// lines of blocks that have none yet
+const defaultContinuation = "   "
+
+// fieldRange is the lines of one field in an annotation block
+type fieldRange struct {
+	name       string
+	start, end int // Line indexes, end exclusive
+}
+
+// RewriteFields applies edits to the annotation blocks of a file and returns
+// the updated content. Rewritten fields keep the comment style, indentation
+// and continuation indent of the block. Value lines longer than the block's
+// widest line are wrapped, unless the block is all single lines.
+func RewriteFields(filename, content string, edits []FieldEdit) (string, error) {
+	commentStyle := detectCommentStyle(filename)
+
+	newline := "\n"
+	if strings.Contains(content, "\r\n") {
+		newline = "\r\n"
+	}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	byBlock := make(map[int][]FieldEdit)
+	var starts []int
+	for _, e := range edits {
+		if _, ok := byBlock[e.Line]; !ok {
+			starts = append(starts, e.Line)
+		}
+		byBlock[e.Line] = append(byBlock[e.Line], e)
+	}
+	// Bottom-up, so earlier blocks keep their line numbers
+	sort.Sort(sort.Reverse(sort.IntSlice(starts)))
+
+	for _, start := range starts {
+		i := start - 1
+		if i < 0 || i >= len(lines) {
+			return "", fmt.Errorf("no annotation at %s:%d", filename, start)
+		}
+		if _, _, ok := parseAnnotationField(lines[i], commentStyle); !ok {
+			return "", fmt.Errorf("no annotation at %s:%d", filename, start)
+		}
+		end, fields := annotationBlock(lines, i, commentStyle)
+		block := rewriteBlock(lines[i:end], fields, byBlock[start], commentStyle, i)
+
+		updated := append([]string(nil), lines[:i]...)
+		updated = append(updated, block...)
+		lines = append(updated, lines[end:]...)
+	}
+
+	return strings.Join(lines, newline), nil
+}
+
+// annotationBlock returns the end of the annotation block starting at line
+// index start, and the lines of each of its fields
+func annotationBlock(lines []string, start int, commentStyle string) (int, []fieldRange) {
+	var fields []fieldRange
+	i := start
+	for ; i < len(lines); i++ {
+		if field, _, ok := parseAnnotationField(lines[i], commentStyle); ok {
+			fields = append(fields, fieldRange{name: field, start: i, end: i + 1})
+			continue
+		}
+		if isContinuationLine(lines[i], commentStyle) {
+			fields[len(fields)-1].end = i + 1
+			continue
+		}
+		break
+	}
+	return i, fields
+}
+
+// rewriteBlock returns the lines of a block with the edits applied. Field
+// ranges are offset by the index of the block's first line.
+func rewriteBlock(block []string, fields []fieldRange, edits []FieldEdit, commentStyle string, offset int) []string {
+	indent := block[0][:len(block[0])-len(strings.TrimLeft(block[0], " \t"))]
+	continuation := indent + commentStyle + defaultContinuation
+	width := 0
+	for _, f := range fields {
+		if f.end-f.start > 1 {
+			continuation = continuationPrefix(block[f.start+1-offset], commentStyle)
+			for _, line := range block {
+				width = max(width, utf8.RuneCountInString(line))
+			}
+			break
+		}
+	}
+
+	replaced := make(map[string][]string)
+	for _, e := range edits {
+		var lines []string
+		if e.Value != "" {
+			lines = []string{}
+			for _, line := range strings.Split(e.Value, "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					lines = append(lines, line)
+				}
+			}
+		}
+		replaced[e.Field] = lines
+	}
+
+	var out []string
+	done := make(map[string]bool)
+	insert := func(after string) {
+		for _, field := range pendingAfter(after, fields, replaced, done) {
+			prefix := indent + commentStyle + " @decision." + field + ": "
+			out = append(out, renderField(prefix, continuation, replaced[field], true, width)...)
+			done[field] = true
+		}
+	}
+
+	insert("")
+	for _, f := range fields {
+		original := block[f.start-offset : f.end-offset]
+		value, ok := replaced[f.name]
+		switch {
+		case !ok:
+			out = append(out, original...)
+		case done[f.name]:
+			// A repeated field was written with the first one
+		case value != nil:
+			prefix, inline := fieldPrefix(original[0])
+			out = append(out, renderField(prefix, continuation, value, inline, width)...)
+		}
+		done[f.name] = true
+		insert(f.name)
+	}
+	return out
+}
+
+// pendingAfter returns the new fields, in fieldOrder, that go right after
+// the field named after, or at the start of the block when after is empty
+func pendingAfter(after string, fields []fieldRange, replaced map[string][]string, done map[string]bool) []string {
+	present := make(map[string]bool)
+	for _, f := range fields {
+		present[f.name] = true
+	}
+
+	var pending []string
+	for field, value := range replaced {
+		if present[field] || done[field] || value == nil {
+			continue
+		}
+		// The last field of the block that comes before it
+		anchor := ""
+		for _, f := range fields {
+			if orderOf(f.name) < orderOf(field) {
+				anchor = f.name
+			}
+		}
+		if anchor == after {
+			pending = append(pending, field)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if orderOf(pending[i]) != orderOf(pending[j]) {
+			return orderOf(pending[i]) < orderOf(pending[j])
+		}
+		return pending[i] < pending[j]
+	})
+	return pending
+}
+
+// orderOf returns the position of a field in fieldOrder; custom fields come
+// last
+func orderOf(field string) int {
+	for i, f := range fieldOrder {
+		if f == field {
+			return i
+		}
+	}
+	return len(fieldOrder)
+}
+
+// fieldPrefix returns everything on a field line before its value, and
+// whether the value starts on that line rather than on the next
+func fieldPrefix(line string) (string, bool) {
+	colon := strings.Index(line, "@decision.")
+	colon += strings.Index(line[colon:], ":") + 1
+	rest := line[colon:]
+	value := strings.TrimLeft(rest, " \t")
+	if strings.TrimSpace(value) == "" {
+		return line[:colon], false
+	}
+	return line[:colon] + rest[:len(rest)-len(value)], true
+}
+
+// continuationPrefix returns everything on a continuation line before its
+// text
+func continuationPrefix(line, commentStyle string) string {
+	at := strings.Index(line, commentStyle) + len(commentStyle)
+	rest := line[at:]
+	return line[:at] + rest[:len(rest)-len(strings.TrimLeft(rest, " \t"))]
+}
+
+// renderField returns the lines of a field. The first value line goes on
+// the field line if inline, and lines wider than width are wrapped at
+// spaces; width 0 disables wrapping.
+func renderField(prefix, continuation string, value []string, inline bool, width int) []string {
+	var out []string
+	if !inline {
+		out = append(out, strings.TrimRight(prefix, " \t"))
+	}
+	for _, text := range value {
+		lead := continuation
+		if len(out) == 0 {
+			lead = prefix
+		}
+		if width <= 0 || utf8.RuneCountInString(lead+text) <= width {
+			out = append(out, lead+text)
+			continue
+		}
+
+		line, empty := lead, true
+		for _, word := range strings.Fields(text) {
+			if !empty && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+				out = append(out, line)
+				line, empty = continuation, true
+			}
+			if !empty {
+				line += " "
+			}
+			line += word
+			empty = false
+		}
+		out = append(out, line)
+	}
+	if len(out) == 0 {
+		out = append(out, strings.TrimRight(prefix, " \t"))
+	}
+	return out
+}
@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteFields(t *testing.T) {
+	source := `package main
+
+	// @decision.id: adr-1
+	// @decision.name: Use Kafka
+	// @decision.context: We need to publish events to
+	//   several consumers.
+	// @decision.alternatives:
+	//   - SQS
+	//   - RabbitMQ
+	func main() {}
+`
+
+	tests := []struct {
+		name  string
+		file  string
+		src   string
+		edits []FieldEdit
+		want  string
+	}{
+		{
+			name:  "replaces a single line value",
+			file:  "main.go",
+			src:   source,
+			edits: []FieldEdit{{Line: 3, Field: "name", Value: "Use Kafka for events"}},
+			want: `package main
+
+	// @decision.id: adr-1
+	// @decision.name: Use Kafka for events
+	// @decision.context: We need to publish events to
+	//   several consumers.
+	// @decision.alternatives:
+	//   - SQS
+	//   - RabbitMQ
+	func main() {}
+`,
+		},
+		{
+			name:  "keeps continuation lines and wraps long ones",
+			file:  "main.go",
+			src:   source,
+			edits: []FieldEdit{{Line: 3, Field: "context", Value: "We need to publish events to\nseveral consumers that replay them after failures."}},
+			want: `package main
+
+	// @decision.id: adr-1
+	// @decision.name: Use Kafka
+	// @decision.context: We need to publish events to
+	//   several consumers that replay them after
+	//   failures.
+	// @decision.alternatives:
+	//   - SQS
+	//   - RabbitMQ
+	func main() {}
+`,
+		},
+		{
+			name:  "keeps values on their own lines",
+			file:  "main.go",
+			src:   source,
+			edits: []FieldEdit{{Line: 3, Field: "alternatives", Value: "- SQS\n- NATS"}},
+			want: `package main
+
+	// @decision.id: adr-1
+	// @decision.name: Use Kafka
+	// @decision.context: We need to publish events to
+	//   several consumers.
+	// @decision.alternatives:
+	//   - SQS
+	//   - NATS
+	func main() {}
+`,
+		},
+		{
+			name: "adds and removes fields",
+			file: "main.go",
+			src:  source,
+			edits: []FieldEdit{
+				{Line: 3, Field: "status", Value: "accepted"},
+				{Line: 3, Field: "context", Value: ""},
+				{Line: 3, Field: "consequences", Value: "More to operate."},
+			},
+			want: `package main
+
+	// @decision.id: adr-1
+	// @decision.name: Use Kafka
+	// @decision.status: accepted
+	// @decision.alternatives:
+	//   - SQS
+	//   - RabbitMQ
+	// @decision.consequences: More to operate.
+	func main() {}
+`,
+		},
+		{
+			name:  "hash comments and CRLF line endings",
+			file:  "app.py",
+			src:   "# @decision.id: adr-2\r\n# @decision.name: Use Celery\r\n# @decision.status: proposed\r\nimport celery\r\n",
+			edits: []FieldEdit{{Line: 1, Field: "status", Value: "accepted"}},
+			want:  "# @decision.id: adr-2\r\n# @decision.name: Use Celery\r\n# @decision.status: accepted\r\nimport celery\r\n",
+		},
+		{
+			name:  "single line blocks are not wrapped",
+			file:  "main.go",
+			src:   "// @decision.id: adr-3\n// @decision.name: Short\n",
+			edits: []FieldEdit{{Line: 1, Field: "context", Value: "A context that is much longer than any line of the annotation block.\nSecond line."}},
+			want:  "// @decision.id: adr-3\n// @decision.name: Short\n// @decision.context: A context that is much longer than any line of the annotation block.\n//   Second line.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RewriteFields(tt.file, tt.src, tt.edits)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRewriteFields_SeveralBlocks(t *testing.T) {
+	src := `// @decision.id: adr-1
+// @decision.name: First
+// @decision.context: One.
+func a() {}
+
+// @decision.id: adr-2
+// @decision.name: Second
+func b() {}
+`
+	got, err := RewriteFields("main.go", src, []FieldEdit{
+		{Line: 1, Field: "context", Value: "One\nand a half."},
+		{Line: 6, Field: "status", Value: "deprecated"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `// @decision.id: adr-1
+// @decision.name: First
+// @decision.context: One
+//   and a half.
+func a() {}
+
+// @decision.id: adr-2
+// @decision.name: Second
+// @decision.status: deprecated
+func b() {}
+`, got)
+}
+
+func TestRewriteFields_NoAnnotation(t *testing.T) {
+	_, err := RewriteFields("main.go", "package main\n", []FieldEdit{{Line: 1, Field: "status", Value: "accepted"}})
+	assert.ErrorContains(t, err, "no annotation at main.go:1")
+}
@@ -229,29 +229,11 @@ func ScanDirectory(rootDir string, excludePatterns []string) ([]*model.Annotatio
 			return nil
 		}
 
-		// Get relative path
-		relPath, err := filepath.Rel(rootDir, path)
+		annotations, err := ScanFile(rootDir, path, excludePatterns)
 		if err != nil {
 			return err
 		}
 
-		// Check if path should be excluded
-		if shouldExclude(relPath, excludePatterns) {
-			return nil
-		}
-
-		// Parse file for annotations
-		annotations, err := ParseFile(path)
-		if err != nil {
-			// Skip files that can't be parsed
-			return nil
-		}
-
-		// Update relative paths in annotations
-		for _, ann := range annotations {
-			ann.Location.File = relPath
-		}
-
 		allAnnotations = append(allAnnotations, annotations...)
 		return nil
 	})
@@ -259,8 +241,38 @@ func ScanDirectory(rootDir string, excludePatterns []string) ([]*model.Annotatio
 	return allAnnotations, err
 }
 
-// shouldExclude checks if a path matches any exclude pattern
-func shouldExclude(path string, patterns []string) bool {
+// ScanFile parses a single file below rootDir for annotations.
+// Annotation locations are made relative to rootDir. Excluded files and
+// files that can't be parsed yield no annotations.
+func ScanFile(rootDir, path string, excludePatterns []string) ([]*model.Annotation, error) {
+	// Get relative path
+	relPath, err := filepath.Rel(rootDir, path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if path should be excluded
+	if ShouldExclude(relPath, excludePatterns) {
+		return nil, nil
+	}
+
+	// Parse file for annotations
+	annotations, err := ParseFile(path)
+	if err != nil {
+		// Skip files that can't be parsed
+		return nil, nil
+	}
+
+	// Update relative paths in annotations
+	for _, ann := range annotations {
+		ann.Location.File = relPath
+	}
+
+	return annotations, nil
+}
+
+// ShouldExclude checks if a path matches any exclude pattern
+func ShouldExclude(path string, patterns []string) bool {
 	for _, pattern := range patterns {
 		matched, err := doublestar.Match(pattern, path)
 		if err == nil && matched {
@@ -2,29 +2,32 @@ package parser
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/weaby/adr-buddy/internal/lifecycle"
 )
 
-var validStatuses = map[string]bool{
-	"proposed":   true,
-	"accepted":   true,
-	"rejected":   true,
-	"deprecated": true,
-	"superseded": true,
+// ValidateStatus checks if a status value is one of the default statuses
+func ValidateStatus(status string, strict bool) error {
+	return ValidateStatusIn(status, lifecycle.DefaultStatuses, strict)
 }
 
-// ValidateStatus checks if a status value is valid
-func ValidateStatus(status string, strict bool) error {
+// ValidateStatusIn checks if a status value is one of statuses
+func ValidateStatusIn(status string, statuses []string, strict bool) error {
 	if status == "" {
 		return nil // Empty is OK, will default to "proposed"
 	}
 
-	if !validStatuses[status] {
-		if strict {
-			return fmt.Errorf("invalid status %q: must be one of: proposed, accepted, rejected, deprecated, superseded", status)
+	for _, s := range statuses {
+		if s == status {
+			return nil
 		}
-		// Just a warning in non-strict mode
-		fmt.Printf("WARNING: Unknown status %q\n", status)
 	}
+	if strict {
+		return fmt.Errorf("invalid status %q: must be one of: %s", status, strings.Join(statuses, ", "))
+	}
+	// Just a warning in non-strict mode
+	fmt.Printf("WARNING: Unknown status %q\n", status)
 
 	return nil
 }
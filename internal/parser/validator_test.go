@@ -33,3 +33,10 @@ func TestValidateStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateStatusIn(t *testing.T) {
+	statuses := []string{"draft", "proposed", "in-review", "retired"}
+
+	assert.NoError(t, ValidateStatusIn("in-review", statuses, true))
+	assert.EqualError(t, ValidateStatusIn("accepted", statuses, true), `invalid status "accepted": must be one of: draft, proposed, in-review, retired`)
+}
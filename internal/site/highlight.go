@@ -0,0 +1,177 @@
+package site
+
+import (
+	"html/template"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// language describes just enough of a language's syntax to highlight it
+type language struct {
+	lineComments []string
+	blockComment [2]string
+	quotes       string // Characters that start a string literal
+	keywords     map[string]bool
+}
+
+func keywordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+var (
+	cLike = language{
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "\"'`",
+	}
+	hashLike = language{
+		lineComments: []string{"#"},
+		quotes:       "\"'",
+	}
+)
+
+// languages are the highlighters by file extension
+var languages = map[string]language{
+	".go":   withKeywords(cLike, "break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false"),
+	".js":   withKeywords(cLike, "async await break case catch class const continue default delete do else export extends finally for function if import in instanceof let new null return super switch this throw true false try typeof undefined var void while yield"),
+	".ts":   withKeywords(cLike, "abstract async await break case catch class const continue default delete do else enum export extends finally for function if implements import in instanceof interface let new null private protected public readonly return super switch this throw true false try type typeof undefined var void while yield"),
+	".java": withKeywords(cLike, "abstract boolean break byte case catch char class const continue default do double else enum extends final finally float for if implements import instanceof int interface long new null package private protected public return short static super switch synchronized this throw throws true false try void volatile while"),
+	".kt":   withKeywords(cLike, "as break class continue do else false for fun if in interface is null object package return super this throw true try typealias val var when while"),
+	".rs":   withKeywords(cLike, "as async await break const continue crate else enum extern false fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while"),
+	".c":    withKeywords(cLike, "auto break case char const continue default do double else enum extern float for goto if int long register return short signed sizeof static struct switch typedef union unsigned void volatile while"),
+	".cpp":  withKeywords(cLike, "auto bool break case catch char class const continue default delete do double else enum explicit extern false float for friend if inline int long namespace new nullptr operator private protected public return short signed sizeof static struct switch template this throw true try typedef typename union unsigned using virtual void volatile while"),
+	".cs":   withKeywords(cLike, "abstract as base bool break case catch class const continue default do double else enum event false finally float for foreach if interface internal is namespace new null object override private protected public readonly return sealed static string struct switch this throw true try using var virtual void while"),
+	".py":   withKeywords(hashLike, "and as assert async await break class continue def del elif else except False finally for from global if import in is lambda None nonlocal not or pass raise return True try while with yield"),
+	".rb":   withKeywords(hashLike, "alias and begin break case class def do else elsif end ensure false for if in module next nil not or redo rescue retry return self super then true undef unless until when while yield"),
+	".sh":   withKeywords(hashLike, "case do done elif else esac export fi for function if in local return then until while"),
+	".yaml": withKeywords(hashLike, "true false null"),
+	".sql": {
+		lineComments: []string{"--"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       "'\"",
+		keywords:     keywordSet("select from where and or not insert into values update set delete create table alter drop index join left right inner outer on as group by order having limit null primary key references"),
+	},
+}
+
+func init() {
+	for ext, alias := range map[string]string{".jsx": ".js", ".tsx": ".ts", ".h": ".c", ".hpp": ".cpp", ".cc": ".cpp", ".kts": ".kt", ".bash": ".sh", ".yml": ".yaml"} {
+		languages[ext] = languages[alias]
+	}
+}
+
+func withKeywords(l language, words string) language {
+	l.keywords = keywordSet(words)
+	return l
+}
+
+// highlight returns the code as HTML, with comments, strings, numbers and
+// keywords wrapped in spans for styling. Files in languages it doesn't know
+// are only escaped.
+func highlight(file, code string) template.HTML {
+	lang, ok := languages[strings.ToLower(filepath.Ext(file))]
+	if !ok {
+		return template.HTML(template.HTMLEscapeString(code))
+	}
+
+	var b strings.Builder
+	span := func(class, text string) {
+		b.WriteString(`<span class="` + class + `">`)
+		b.WriteString(template.HTMLEscapeString(text))
+		b.WriteString("</span>")
+	}
+
+	for i := 0; i < len(code); {
+		rest := code[i:]
+
+		if lang.blockComment[0] != "" && strings.HasPrefix(rest, lang.blockComment[0]) {
+			end := strings.Index(rest[len(lang.blockComment[0]):], lang.blockComment[1])
+			n := len(rest)
+			if end >= 0 {
+				n = len(lang.blockComment[0]) + end + len(lang.blockComment[1])
+			}
+			span("c", rest[:n])
+			i += n
+			continue
+		}
+
+		if lineComment(lang, rest) {
+			n := strings.IndexByte(rest, '\n')
+			if n < 0 {
+				n = len(rest)
+			}
+			span("c", rest[:n])
+			i += n
+			continue
+		}
+
+		c := rest[0]
+		switch {
+		case strings.IndexByte(lang.quotes, c) >= 0:
+			n := stringLen(rest)
+			span("s", rest[:n])
+			i += n
+		case c >= '0' && c <= '9':
+			n := identLen(rest)
+			span("n", rest[:n])
+			i += n
+		case c == '_' || c >= 0x80 || unicode.IsLetter(rune(c)):
+			n := identLen(rest)
+			if lang.keywords[rest[:n]] {
+				span("k", rest[:n])
+			} else {
+				b.WriteString(template.HTMLEscapeString(rest[:n]))
+			}
+			i += n
+		default:
+			b.WriteString(template.HTMLEscapeString(rest[:1]))
+			i++
+		}
+	}
+
+	return template.HTML(b.String())
+}
+
+func lineComment(lang language, s string) bool {
+	for _, prefix := range lang.lineComments {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringLen returns the length of the string literal s starts with. Strings
+// end at the closing quote or, except for backquoted ones, the line.
+func stringLen(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote != '`':
+			i++
+		case s[i] == quote:
+			return i + 1
+		case s[i] == '\n' && quote != '`':
+			return i
+		}
+	}
+	return len(s)
+}
+
+// identLen returns the length of the identifier or number s starts with
+func identLen(s string) int {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '_' && c < 0x80 && !unicode.IsLetter(rune(c)) && !unicode.IsDigit(rune(c)) {
+			if i == 0 {
+				return 1
+			}
+			return i
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,188 @@
+package site
+
+import "html/template"
+
+// style is shared by every page. Status colors come from the lifecycle,
+// see statusStyle.
+const style = `
+:root { --fg: #1f2328; --muted: #59636e; --border: #d1d9e0; --bg-code: #f6f8fa; --link: #0969da; }
+* { box-sizing: border-box; }
+body { margin: 0 auto; max-width: 960px; padding: 2rem 1rem; color: var(--fg); font: 16px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; }
+a { color: var(--link); text-decoration: none; }
+a:hover { text-decoration: underline; }
+h1 { margin-top: 0; }
+h2 { border-bottom: 1px solid var(--border); padding-bottom: .3rem; }
+nav { margin-bottom: 1rem; }
+.meta { color: var(--muted); }
+.meta span { margin-right: 1rem; }
+.status { display: inline-block; padding: 0 .5rem; border-radius: 1rem; font-size: .85rem; background: #eee; }
+.filters { display: flex; gap: .5rem; margin-bottom: 1rem; flex-wrap: wrap; }
+.filters input { flex: 1; min-width: 12rem; }
+.filters input, .filters select { padding: .4rem; font: inherit; border: 1px solid var(--border); border-radius: 6px; }
+table { width: 100%; border-collapse: collapse; }
+th, td { text-align: left; padding: .4rem .6rem; border-bottom: 1px solid var(--border); }
+.prose { white-space: pre-line; }
+.empty { color: var(--muted); }
+.tags span { display: inline-block; margin-right: .3rem; padding: 0 .4rem; border: 1px solid var(--border); border-radius: 4px; font-size: .85rem; }
+.snippet { margin: 1rem 0; border: 1px solid var(--border); border-radius: 6px; overflow: hidden; }
+.snippet header { padding: .3rem .6rem; background: var(--bg-code); border-bottom: 1px solid var(--border); font-family: ui-monospace, monospace; font-size: .85rem; }
+.snippet .code { display: flex; overflow-x: auto; }
+.snippet pre { margin: 0; padding: .6rem; font: .85rem/1.45 ui-monospace, SFMono-Regular, Menlo, monospace; }
+.snippet .lines { color: var(--muted); text-align: right; user-select: none; border-right: 1px solid var(--border); }
+.c { color: #6e7781; font-style: italic; }
+.s { color: #0a3069; }
+.n { color: #0550ae; }
+.k { color: #cf222e; }
+`
+
+// liveReload reloads the page when the server reports a new version. The
+// first message is the version the page was rendered from.
+const liveReload = `{{define "live-reload"}}{{if .LiveReload}}
+<script>
+(function () {
+  var version;
+  new EventSource({{.LiveReload}}).onmessage = function (e) {
+    if (version !== undefined && e.data !== version) location.reload();
+    version = e.data;
+  };
+})();
+</script>
+{{- end}}{{end}}`
+
+var indexTmpl = template.Must(template.New("index").Parse(liveReload + `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+` + Marker + `
+<title>{{.Title}}</title>
+<style>` + style + `{{.StatusStyle}}</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="filters">
+<input id="search" type="search" placeholder="Search decisions" aria-label="Search decisions">
+<select id="status" aria-label="Status">
+<option value="">All statuses</option>
+{{- range .Statuses}}
+<option value="{{.Value}}">{{.Label}}</option>
+{{- end}}
+</select>
+{{- if .Categories}}
+<select id="category" aria-label="Category">
+<option value="">All categories</option>
+{{- range .Categories}}
+<option value="{{.}}">{{.}}</option>
+{{- end}}
+</select>
+{{- end}}
+</div>
+<table>
+<thead><tr><th>ID</th><th>Title</th><th>Status</th><th>Category</th></tr></thead>
+<tbody>
+{{- range .ADRs}}
+<tr data-search="{{.Search}}" data-status="{{.Status}}" data-category="{{.Category}}">
+<td><a href="{{.URL}}">{{.ID}}</a></td>
+<td><a href="{{.URL}}">{{.Name}}</a></td>
+<td><span class="status status-{{.Status}}">{{.StatusLabel}}</span></td>
+<td>{{.Category}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+<p class="empty" id="empty"{{if .ADRs}} hidden{{end}}>No decisions match.</p>
+<script>
+(function () {
+  var search = document.getElementById("search");
+  var status = document.getElementById("status");
+  var category = document.getElementById("category");
+  var rows = document.querySelectorAll("tbody tr");
+  var params = new URLSearchParams(location.search);
+  search.value = params.get("q") || "";
+  status.value = params.get("status") || "";
+  if (category) category.value = params.get("category") || "";
+
+  function apply() {
+    var terms = search.value.toLowerCase().split(/\s+/).filter(Boolean);
+    var shown = 0;
+    rows.forEach(function (row) {
+      var match = (!status.value || row.dataset.status === status.value) &&
+        (!category || !category.value || row.dataset.category === category.value ||
+          row.dataset.category.indexOf(category.value + "/") === 0) &&
+        terms.every(function (t) { return row.dataset.search.indexOf(t) >= 0; });
+      row.hidden = !match;
+      if (match) shown++;
+    });
+    document.getElementById("empty").hidden = shown > 0;
+
+    // Keep the filters in the URL, so they survive reloads and can be shared
+    var query = new URLSearchParams();
+    if (search.value) query.set("q", search.value);
+    if (status.value) query.set("status", status.value);
+    if (category && category.value) query.set("category", category.value);
+    history.replaceState(null, "", query.toString() ? "?" + query : location.pathname);
+  }
+
+  [search, status, category].forEach(function (el) {
+    if (el) el.addEventListener("input", apply);
+  });
+  apply();
+})();
+</script>
+{{- template "live-reload" .}}
+</body>
+</html>
+`))
+
+var pageTmpl = template.Must(template.New("page").Parse(liveReload + `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+` + Marker + `
+<title>{{.Title}}</title>
+<style>` + style + `{{.StatusStyle}}</style>
+</head>
+<body>
+<nav><a href="` + IndexFile + `">&larr; All decisions</a></nav>
+<h1>{{.ADR.ID}}: {{.ADR.Name}}</h1>
+<p class="meta">
+<span class="status status-{{.ADR.Status}}">{{.Status}}</span>
+{{- if .ADR.Category}}
+<span><a href="` + IndexFile + `?category={{.ADR.Category}}">{{.ADR.Category}}</a></span>
+{{- end}}
+{{- if .ADR.Date}}
+<span>{{.ADR.Date}}</span>
+{{- end}}
+</p>
+{{- if .ADR.Tags}}
+<p class="tags">{{range .ADR.Tags}}<span>{{.}}</span>{{end}}</p>
+{{- end}}
+{{- if .Relations}}
+<ul class="relations">
+{{- range .Relations}}
+<li>{{.Label}}: {{range $i, $l := .Links}}{{if $i}}, {{end}}{{if $l.URL}}<a href="{{$l.URL}}">{{$l.ID}}{{if $l.Name}}: {{$l.Name}}{{end}}</a>{{else}}{{$l.ID}} (not found){{end}}{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- range .Sections}}
+<h2>{{.Heading}}</h2>
+{{- range .Paragraphs}}
+<p class="prose">{{.}}</p>
+{{- end}}
+{{- end}}
+{{- if .Snippets}}
+<h2>{{.Locations}}</h2>
+{{- range .Snippets}}
+<section class="snippet">
+<header>{{.Location}}</header>
+{{- if .Code}}
+<div class="code"><pre class="lines">{{.Numbers}}</pre><pre><code>{{.Code}}</code></pre></div>
+{{- end}}
+</section>
+{{- end}}
+{{- end}}
+{{- template "live-reload" .}}
+</body>
+</html>
+`))
@@ -0,0 +1,308 @@
+// Package site renders the decisions as a static HTML site: an index page
+// with search and filters, and a page per ADR. Pages are self-contained,
+// with styles and scripts inline, so the site works offline and from
+// file:// URLs.
+package site
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/weaby/adr-buddy/internal/graph"
+	"github.com/weaby/adr-buddy/internal/lifecycle"
+	"github.com/weaby/adr-buddy/internal/model"
+	adrtemplate "github.com/weaby/adr-buddy/internal/template"
+)
+
+// Marker is in the head of every generated page. Pages without it were not
+// written by adr-buddy and are never overwritten or removed.
+const Marker = `<meta name="generator" content="adr-buddy">`
+
+// IndexFile is the name of the index page
+const IndexFile = "index.html"
+
+// DefaultSnippetLines is how many lines of code are shown per location
+const DefaultSnippetLines = 15
+
+// Options controls how the site is rendered
+type Options struct {
+	Title        string // Title of the index page
+	Locale       string // Locale of section headings and status names
+	SnippetLines int    // Lines of code shown per location, DefaultSnippetLines if zero
+
+	// Lifecycle colors the statuses; the default lifecycle if nil
+	Lifecycle *lifecycle.Lifecycle
+
+	// Files maps each code location to the absolute path of its file, for
+	// snippets. Locations are relative to their scan path, so they can't
+	// be resolved without it.
+	Files map[model.SourceLocation]string
+
+	// LiveReload is the URL of a server-sent events stream. When set, pages
+	// reload whenever the stream reports a new version.
+	LiveReload string
+}
+
+// link is a link to another decision; URL is empty if it has no page
+type link struct {
+	ID   string
+	Name string
+	URL  string
+}
+
+// relation is a kind of relation and the decisions it links to
+type relation struct {
+	Label string
+	Links []link
+}
+
+// snippet is the code around a code location
+type snippet struct {
+	Location string
+	Numbers  string        // Line numbers, one per line
+	Code     template.HTML // Highlighted code, empty if the file can't be read
+}
+
+// section is a prose section of a decision
+type section struct {
+	Heading    string
+	Paragraphs []string
+}
+
+type pageData struct {
+	Title       string
+	LiveReload  string
+	StatusStyle template.CSS
+	ADR         *model.ADR
+	Status      string
+	Sections    []section
+	Relations   []relation
+	Snippets    []snippet
+	Locations   string
+}
+
+type indexData struct {
+	Title       string
+	LiveReload  string
+	StatusStyle template.CSS
+	ADRs        []indexEntry
+	Statuses    []option
+	Categories  []string
+}
+
+// option is a choice in a filter
+type option struct {
+	Value string
+	Label string
+}
+
+type indexEntry struct {
+	*model.ADR
+	StatusLabel string
+	URL         string
+	Search      string // Lower-cased text the search box matches against
+}
+
+// PageName returns the file name of an ADR's page
+func PageName(id string) string {
+	return strings.NewReplacer("/", "_", `\`, "_").Replace(id) + ".html"
+}
+
+// Build renders the site for adrs and returns the pages keyed by file name.
+// All pages are in one directory, so links between them are file names.
+func Build(adrs []*model.ADR, opts Options) (map[string][]byte, error) {
+	if opts.SnippetLines <= 0 {
+		opts.SnippetLines = DefaultSnippetLines
+	}
+	if opts.Title == "" {
+		opts.Title = "Architecture Decision Records"
+	}
+	locale, ok := adrtemplate.LookupLocale(opts.Locale)
+	if !ok {
+		locale, _ = adrtemplate.LookupLocale(adrtemplate.DefaultLocale)
+	}
+
+	sorted := append([]*model.ADR(nil), adrs...)
+	sort.Slice(sorted, func(i, j int) bool { return model.IDLess(sorted[i].ID, sorted[j].ID) })
+
+	pages := make(map[string][]byte, len(sorted)+1)
+	relations := relationsByID(sorted)
+
+	colors := statusStyle(sorted, opts.Lifecycle)
+	index := indexData{Title: opts.Title, LiveReload: opts.LiveReload, StatusStyle: colors}
+	statuses := make(map[string]bool)
+	categories := make(map[string]bool)
+	for _, adr := range sorted {
+		index.ADRs = append(index.ADRs, indexEntry{
+			ADR:         adr,
+			StatusLabel: locale.StatusLabel(adr.Status),
+			URL:         PageName(adr.ID),
+			Search:      strings.ToLower(strings.Join([]string{adr.ID, adr.Name, adr.Category, strings.Join(adr.Tags, " "), strings.Join(adr.Context, " "), strings.Join(adr.Decision, " ")}, " ")),
+		})
+		statuses[adr.Status] = true
+		if adr.Category != "" {
+			categories[adr.Category] = true
+		}
+
+		page := pageData{
+			Title:       adr.ID + ": " + adr.Name,
+			LiveReload:  opts.LiveReload,
+			StatusStyle: colors,
+			ADR:         adr,
+			Status:      locale.StatusLabel(adr.Status),
+			Relations:   relations[adr.ID],
+			Locations:   locale.Locations,
+		}
+		for _, s := range []struct {
+			heading    string
+			paragraphs []string
+		}{
+			{locale.Headings.Context, adr.Context},
+			{locale.Headings.Decision, adr.Decision},
+			{locale.Headings.Alternatives, adr.Alternatives},
+			{locale.Headings.Consequences, adr.Consequences},
+		} {
+			if len(s.paragraphs) > 0 {
+				page.Sections = append(page.Sections, section{Heading: s.heading, Paragraphs: s.paragraphs})
+			}
+		}
+		for _, loc := range adr.Locations {
+			page.Snippets = append(page.Snippets, readSnippet(opts.Files[loc], loc, opts.SnippetLines))
+		}
+
+		var buf bytes.Buffer
+		if err := pageTmpl.Execute(&buf, page); err != nil {
+			return nil, fmt.Errorf("failed to render page for %s: %w", adr.ID, err)
+		}
+		pages[PageName(adr.ID)] = buf.Bytes()
+	}
+
+	for status := range statuses {
+		index.Statuses = append(index.Statuses, option{Value: status, Label: locale.StatusLabel(status)})
+	}
+	sort.Slice(index.Statuses, func(i, j int) bool { return index.Statuses[i].Value < index.Statuses[j].Value })
+	for category := range categories {
+		index.Categories = append(index.Categories, category)
+	}
+	sort.Strings(index.Categories)
+
+	var buf bytes.Buffer
+	if err := indexTmpl.Execute(&buf, index); err != nil {
+		return nil, fmt.Errorf("failed to render index page: %w", err)
+	}
+	pages[IndexFile] = buf.Bytes()
+
+	return pages, nil
+}
+
+// classRe matches statuses that can be used in a CSS class name as is
+var classRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// statusStyle returns the CSS rules coloring the statuses of adrs, with the
+// colors of statuses, or of the default lifecycle if it is nil
+func statusStyle(adrs []*model.ADR, statuses *lifecycle.Lifecycle) template.CSS {
+	l := lifecycle.Default()
+	if statuses != nil {
+		l = *statuses
+	}
+	seen := make(map[string]bool)
+	var rules []string
+	for _, adr := range adrs {
+		if seen[adr.Status] || !classRe.MatchString(adr.Status) {
+			continue
+		}
+		seen[adr.Status] = true
+		rules = append(rules, fmt.Sprintf(".status-%s { background: %s; }\n", adr.Status, l.Color(adr.Status)))
+	}
+	sort.Strings(rules)
+	return template.CSS(strings.Join(rules, ""))
+}
+
+// relationsByID returns the relations of every decision, from the same
+// edges the decision graph has
+func relationsByID(adrs []*model.ADR) map[string][]relation {
+	g := graph.Build(adrs)
+	nodes := make(map[string]graph.Node)
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+	linkTo := func(id string) link {
+		l := link{ID: id, Name: nodes[id].Name}
+		if !nodes[id].Missing {
+			l.URL = PageName(id)
+		}
+		return l
+	}
+
+	labels := []string{"Supersedes", "Superseded by", "Related", "Shares code with"}
+	byID := make(map[string]map[string][]link)
+	add := func(id, label, other string) {
+		if byID[id] == nil {
+			byID[id] = make(map[string][]link)
+		}
+		byID[id][label] = append(byID[id][label], linkTo(other))
+	}
+	for _, e := range g.Edges {
+		switch e.Kind {
+		case graph.EdgeSupersedes:
+			add(e.From, "Supersedes", e.To)
+			add(e.To, "Superseded by", e.From)
+		case graph.EdgeRelates:
+			add(e.From, "Related", e.To)
+			add(e.To, "Related", e.From)
+		case graph.EdgeSharesCode:
+			add(e.From, "Shares code with", e.To)
+			add(e.To, "Shares code with", e.From)
+		}
+	}
+
+	relations := make(map[string][]relation, len(byID))
+	for id, links := range byID {
+		for _, label := range labels {
+			if len(links[label]) > 0 {
+				sort.Slice(links[label], func(i, j int) bool { return model.IDLess(links[label][i].ID, links[label][j].ID) })
+				relations[id] = append(relations[id], relation{Label: label, Links: links[label]})
+			}
+		}
+	}
+	return relations
+}
+
+// readSnippet returns up to n lines of code of the file at path, starting
+// at the location
+func readSnippet(path string, loc model.SourceLocation, n int) snippet {
+	s := snippet{Location: loc.String()}
+	if path == "" {
+		return s
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s
+	}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	start := loc.Line - 1
+	if start < 0 || start >= len(lines) {
+		return s
+	}
+	end := start + n
+	if end > len(lines) {
+		end = len(lines)
+	}
+	for end > start+1 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+
+	numbers := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		numbers = append(numbers, fmt.Sprint(i+1))
+	}
+	s.Numbers = strings.Join(numbers, "\n")
+	s.Code = highlight(loc.File, strings.Join(lines[start:end], "\n"))
+	return s
+}
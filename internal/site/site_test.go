@@ -0,0 +1,121 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weaby/adr-buddy/internal/lifecycle"
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+func TestBuild(t *testing.T) {
+	rootDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "bus.go"), []byte(`package bus
+
+// @decision.id: adr-2
+// @decision.name: Event bus
+func Publish(topic string) error {
+	return nil
+}
+`), 0644))
+
+	adrs := []*model.ADR{
+		{
+			ID:         "adr-2",
+			Name:       "Event bus",
+			Status:     "accepted",
+			Category:   "platform",
+			Date:       "2024-01-15",
+			Context:    []string{"Polling <is> slow"},
+			Supersedes: []string{"adr-1"},
+			Relates:    []string{"adr-9"},
+			Locations:  []model.SourceLocation{{File: "bus.go", Line: 3}},
+		},
+		{ID: "adr-1", Name: "Polling", Status: "superseded", Category: "platform"},
+	}
+
+	pages, err := Build(adrs, Options{Files: map[model.SourceLocation]string{{File: "bus.go", Line: 3}: filepath.Join(rootDir, "bus.go")}})
+	require.NoError(t, err)
+	assert.Len(t, pages, 3)
+
+	index := string(pages[IndexFile])
+	assert.Contains(t, index, Marker)
+	assert.Contains(t, index, `<a href="adr-1.html">Polling</a>`)
+	assert.Contains(t, index, `<option value="platform">platform</option>`)
+	assert.Less(t, strings.Index(index, "adr-1.html"), strings.Index(index, "adr-2.html"))
+	assert.NotContains(t, index, "https://", "pages load nothing from the network")
+
+	page := string(pages["adr-2.html"])
+	assert.Contains(t, page, "<h1>adr-2: Event bus</h1>")
+	assert.Contains(t, page, "<h2>Context</h2>")
+	assert.Contains(t, page, "Polling &lt;is&gt; slow")
+	assert.Contains(t, page, `Supersedes: <a href="adr-1.html">adr-1: Polling</a>`)
+	assert.Contains(t, page, "Related: adr-9 (not found)")
+	assert.Contains(t, page, "<span>2024-01-15</span>")
+	assert.Contains(t, page, "<header>bus.go:3</header>")
+	assert.Contains(t, page, `<span class="k">func</span> Publish`)
+	assert.Contains(t, page, `<pre class="lines">3
+4
+5
+6
+7</pre>`)
+
+	assert.Contains(t, string(pages["adr-1.html"]), `Superseded by: <a href="adr-2.html">adr-2: Event bus</a>`)
+}
+
+func TestBuild_Locale(t *testing.T) {
+	pages, err := Build([]*model.ADR{{ID: "adr-1", Name: "Test", Status: "accepted", Context: []string{"Text"}}}, Options{Locale: "de"})
+	require.NoError(t, err)
+	assert.Contains(t, string(pages["adr-1.html"]), "<h2>Kontext</h2>")
+	assert.Contains(t, string(pages[IndexFile]), `<option value="accepted">angenommen</option>`)
+}
+
+func TestBuild_StatusColors(t *testing.T) {
+	statuses := lifecycle.Lifecycle{Statuses: []string{"proposed", "in-review"}, Colors: map[string]string{"in-review": "#ffe5b4"}}
+	adrs := []*model.ADR{{ID: "adr-1", Name: "Test", Status: "in-review"}, {ID: "adr-2", Name: "Other", Status: "proposed"}}
+	pages, err := Build(adrs, Options{Lifecycle: &statuses})
+	require.NoError(t, err)
+	for _, page := range []string{IndexFile, "adr-1.html"} {
+		assert.Contains(t, string(pages[page]), ".status-in-review { background: #ffe5b4; }\n.status-proposed { background: #cfe2ff; }\n</style>")
+	}
+}
+
+func TestBuild_MissingSource(t *testing.T) {
+	adrs := []*model.ADR{{ID: "adr-1", Name: "Test", Locations: []model.SourceLocation{{File: "gone.go", Line: 1}}}}
+	pages, err := Build(adrs, Options{Files: map[model.SourceLocation]string{{File: "gone.go", Line: 1}: filepath.Join(t.TempDir(), "gone.go")}})
+	require.NoError(t, err)
+	page := string(pages["adr-1.html"])
+	assert.Contains(t, page, "<header>gone.go:1</header>")
+	assert.NotContains(t, page, `<div class="code">`)
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		code string
+		want string
+	}{
+		{"keyword and string", "a.go", `return "<b>"`, `<span class="k">return</span> <span class="s">&#34;&lt;b&gt;&#34;</span>`},
+		{"line comment", "a.py", "x = 1 # note", `x = <span class="n">1</span> <span class="c"># note</span>`},
+		{"block comment", "a.js", "/* a\nb */ let", "<span class=\"c\">/* a\nb */</span> <span class=\"k\">let</span>"},
+		{"escaped quote", "a.go", `"a\"b" x`, `<span class="s">&#34;a\&#34;b&#34;</span> x`},
+		{"unknown language", "a.txt", "if <x>", "if &lt;x&gt;"},
+		{"identifier containing keyword", "a.go", "format", "format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, string(highlight(tt.file, tt.code)))
+		})
+	}
+}
+
+func TestPageName(t *testing.T) {
+	assert.Equal(t, "adr-001.html", PageName("adr-001"))
+	assert.Equal(t, "a_b.html", PageName("a/b"))
+}
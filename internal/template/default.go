@@ -3,52 +3,52 @@ package template
 // DefaultTemplate returns the embedded default ADR template
 func DefaultTemplate() string {
 	return `# {{.ID}}: {{.Name}}
-
-**Status:** {{.Status}}
-**Date:** {{.Date}}
-{{if .Category}}**Category:** {{.Category}}{{end}}
-
-## Context
+{{if not .FrontMatter}}
+**{{.Locale.Labels.Status}}:** {{.Locale.StatusLabel .Status}}
+**{{.Locale.Labels.Date}}:** {{.Date}}
+{{if .Category}}**{{.Locale.Labels.Category}}:** {{.Category}}{{end}}
+{{end}}
+## {{.Locale.Headings.Context}}
 {{if .Context}}
 {{range .Context}}
 {{.}}
 
 {{end}}
 {{else}}
-<!-- TODO: Add context - what is the issue we're facing? -->
+<!-- {{.Locale.Todo}}: {{.Locale.Placeholders.Context}} -->
 {{end}}
 
-## Decision
+## {{.Locale.Headings.Decision}}
 {{if .Decision}}
 {{range .Decision}}
 {{.}}
 
 {{end}}
 {{else}}
-<!-- TODO: Document the decision and rationale -->
+<!-- {{.Locale.Todo}}: {{.Locale.Placeholders.Decision}} -->
 {{end}}
 
-## Alternatives Considered
+## {{.Locale.Headings.Alternatives}}
 {{if .Alternatives}}
 {{range .Alternatives}}
 {{.}}
 
 {{end}}
 {{else}}
-<!-- TODO: What alternatives were considered and why were they rejected? -->
+<!-- {{.Locale.Todo}}: {{.Locale.Placeholders.Alternatives}} -->
 {{end}}
 
-## Consequences
+## {{.Locale.Headings.Consequences}}
 {{if .Consequences}}
 {{range .Consequences}}
 {{.}}
 
 {{end}}
 {{else}}
-<!-- TODO: What are the positive/negative outcomes? -->
+<!-- {{.Locale.Todo}}: {{.Locale.Placeholders.Consequences}} -->
 {{end}}
 
-## Code Locations
+## {{.Locale.Locations}}
 {{range .Locations}}
 - {{.File}}:{{.Line}}
 {{end}}
@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weaby/adr-buddy/internal/model"
 )
 
 func TestDefaultTemplate(t *testing.T) {
@@ -12,8 +15,12 @@ func TestDefaultTemplate(t *testing.T) {
 	assert.NotEmpty(t, tmpl)
 	assert.Contains(t, tmpl, "{{.ID}}")
 	assert.Contains(t, tmpl, "{{.Name}}")
-	assert.Contains(t, tmpl, "## Context")
-	assert.Contains(t, tmpl, "## Decision")
-	assert.Contains(t, tmpl, "## Consequences")
-	assert.Contains(t, tmpl, "## Code Locations")
+
+	// Headings come from the locale, English by default
+	rendered, err := Render(&model.ADR{ID: "adr-1", Name: "Test"}, tmpl)
+	require.NoError(t, err)
+	assert.Contains(t, rendered, "## Context")
+	assert.Contains(t, rendered, "## Decision")
+	assert.Contains(t, rendered, "## Consequences")
+	assert.Contains(t, rendered, "## Code Locations")
 }
@@ -0,0 +1,105 @@
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+// frontMatterRe matches a YAML front matter block at the start of a file
+var frontMatterRe = regexp.MustCompile(`(?s)\A---[ \t]*\r?\n(.*?\r?\n)?---[ \t]*(?:\r?\n|\z)`)
+
+// Metadata is the YAML front matter of an ADR
+type Metadata struct {
+	ID        string     `yaml:"id"`
+	Name      string     `yaml:"name"`
+	Status    string     `yaml:"status"`
+	Category  string     `yaml:"category,omitempty"`
+	Date      string     `yaml:"date"`
+	Tags      []string   `yaml:"tags,omitempty"`
+	Relations *Relations `yaml:"relations,omitempty"`
+	Locations []Location `yaml:"locations,omitempty"`
+}
+
+// Relations links an ADR to other decisions by ID
+type Relations struct {
+	Supersedes []string `yaml:"supersedes,omitempty"`
+	Relates    []string `yaml:"relates,omitempty"`
+}
+
+// Location is a code location in front matter
+type Location struct {
+	File string `yaml:"file"`
+	Line int    `yaml:"line"`
+}
+
+// NewMetadata returns the front matter for an ADR
+func NewMetadata(adr *model.ADR) *Metadata {
+	meta := &Metadata{
+		ID:       adr.ID,
+		Name:     adr.Name,
+		Status:   adr.Status,
+		Category: adr.Category,
+		Date:     adr.Date,
+		Tags:     adr.Tags,
+	}
+	if len(adr.Supersedes) > 0 || len(adr.Relates) > 0 {
+		meta.Relations = &Relations{Supersedes: adr.Supersedes, Relates: adr.Relates}
+	}
+	for _, loc := range adr.Locations {
+		meta.Locations = append(meta.Locations, Location{File: loc.File, Line: loc.Line})
+	}
+	return meta
+}
+
+// renderFrontMatter formats metadata as a YAML front matter block
+func renderFrontMatter(meta *Metadata) (string, error) {
+	data, err := yaml.Marshal(meta)
+	if err != nil {
+		return "", fmt.Errorf("failed to render front matter: %w", err)
+	}
+	return "---\n" + string(data) + "---\n\n", nil
+}
+
+// splitFrontMatter separates a YAML front matter block from the body.
+// Content without front matter, or with front matter that isn't valid
+// YAML, is returned whole with nil metadata.
+func splitFrontMatter(content string) (*Metadata, string) {
+	loc := frontMatterRe.FindStringSubmatchIndex(content)
+	if loc == nil {
+		return nil, content
+	}
+
+	var meta Metadata
+	if loc[2] >= 0 {
+		if err := yaml.Unmarshal([]byte(content[loc[2]:loc[3]]), &meta); err != nil {
+			return nil, content
+		}
+	}
+	return &meta, content[loc[1]:]
+}
+
+// setFrontMatterStatus rewrites the status key of a front matter block
+func setFrontMatterStatus(content, status string) (string, bool) {
+	loc := frontMatterRe.FindStringIndex(content)
+	if loc == nil {
+		return content, false
+	}
+
+	statusRe := regexp.MustCompile(`(?m)^(status:[ \t]*)[^\n]*`)
+	block := content[:loc[1]]
+	match := statusRe.FindStringSubmatchIndex(block)
+	if match == nil {
+		return content, false
+	}
+
+	value, err := yaml.Marshal(status)
+	if err != nil {
+		return content, false
+	}
+	return block[:match[3]] + strings.TrimSpace(string(value)) + block[match[1]:] + content[loc[1]:], true
+}
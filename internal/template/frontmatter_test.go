@@ -0,0 +1,160 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+func frontMatterADR() *model.ADR {
+	return &model.ADR{
+		ID:         "adr-7",
+		Name:       "Event bus",
+		Status:     "accepted",
+		Category:   "backend",
+		Date:       "2026-01-17",
+		Context:    []string{"Services need to react to orders"},
+		Tags:       []string{"messaging", "kafka"},
+		Supersedes: []string{"adr-2"},
+		Relates:    []string{"adr-3"},
+		Locations:  []model.SourceLocation{{File: "src/bus.go", Line: 12}},
+	}
+}
+
+func TestRenderWithOptions_FrontMatter(t *testing.T) {
+	result, err := RenderWithOptions(frontMatterADR(), DefaultTemplate(), Options{FrontMatter: true})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(result, `---
+id: adr-7
+name: Event bus
+status: accepted
+category: backend
+date: "2026-01-17"
+tags:
+    - messaging
+    - kafka
+relations:
+    supersedes:
+        - adr-2
+    relates:
+        - adr-3
+locations:
+    - file: src/bus.go
+      line: 12
+---
+
+# adr-7: Event bus
+`), result)
+
+	// Metadata lives in front matter only; prose stays in the body
+	assert.NotContains(t, result, "**Status:**")
+	assert.Contains(t, result, "## Context\n\n\nServices need to react to orders")
+}
+
+func TestRenderWithOptions_NoFrontMatter(t *testing.T) {
+	withOpts, err := RenderWithOptions(frontMatterADR(), DefaultTemplate(), Options{})
+	require.NoError(t, err)
+	plain, err := Render(frontMatterADR(), DefaultTemplate())
+	require.NoError(t, err)
+
+	assert.Equal(t, plain, withOpts)
+	assert.False(t, strings.HasPrefix(plain, "---"))
+	assert.Contains(t, plain, "**Status:** accepted")
+}
+
+func TestParseExistingADR_FrontMatter(t *testing.T) {
+	content, err := RenderWithOptions(frontMatterADR(), DefaultTemplate(), Options{FrontMatter: true})
+	require.NoError(t, err)
+
+	parsed := ParseExistingADR(content)
+	require.NotNil(t, parsed.Metadata)
+	assert.Equal(t, "adr-7", parsed.Frontmatter["ID"])
+	assert.Equal(t, "Event bus", parsed.Frontmatter["Name"])
+	assert.Equal(t, "accepted", parsed.Frontmatter["Status"])
+	assert.Equal(t, "2026-01-17", parsed.Frontmatter["Date"])
+	assert.Equal(t, "backend", parsed.Frontmatter["Category"])
+	assert.Equal(t, []string{"messaging", "kafka"}, parsed.Metadata.Tags)
+	assert.Equal(t, []string{"adr-2"}, parsed.Metadata.Relations.Supersedes)
+	assert.Equal(t, []string{"adr-3"}, parsed.Metadata.Relations.Relates)
+	assert.Equal(t, []Location{{File: "src/bus.go", Line: 12}}, parsed.Metadata.Locations)
+	assert.Equal(t, "Services need to react to orders", parsed.Sections["Context"])
+}
+
+func TestParseExistingADR_UnquotedDate(t *testing.T) {
+	parsed := ParseExistingADR("---\nid: adr-1\ndate: 2025-03-04\n---\n# adr-1: X\n")
+	assert.Equal(t, "2025-03-04", parsed.Frontmatter["Date"])
+}
+
+func TestParseExistingADR_InvalidFrontMatter(t *testing.T) {
+	parsed := ParseExistingADR("---\nid: [unclosed\n---\n# adr-1: X\n\n**Date:** 2025-03-04\n")
+	assert.Nil(t, parsed.Metadata)
+	assert.Equal(t, "adr-1", parsed.Frontmatter["ID"])
+	assert.Equal(t, "2025-03-04", parsed.Frontmatter["Date"])
+}
+
+func TestMergeWithOptions_FrontMatter(t *testing.T) {
+	existing := `---
+id: adr-7
+name: Event bus
+status: proposed
+date: "2025-11-02"
+tags:
+    - old-tag
+---
+
+# adr-7: Event bus
+
+## Context
+
+<!-- TODO: Add context - what is the issue we're facing? -->
+
+## Decision
+
+Hand-written decision.
+`
+	adr := frontMatterADR()
+	result, err := MergeWithOptions(adr, existing, DefaultTemplate(), Options{FrontMatter: true})
+	require.NoError(t, err)
+
+	assert.Contains(t, result, "status: accepted")
+	assert.Contains(t, result, `date: "2025-11-02"`)
+	assert.NotContains(t, result, "old-tag")
+	assert.Contains(t, result, "- kafka")
+	assert.Contains(t, result, "Hand-written decision.")
+	assert.Equal(t, 1, strings.Count(result, "\n---\n"))
+
+	again, err := MergeWithOptions(adr, result, DefaultTemplate(), Options{FrontMatter: true})
+	require.NoError(t, err)
+	assert.Equal(t, result, again)
+}
+
+func TestMergeWithOptions_SwitchModes(t *testing.T) {
+	adr := frontMatterADR()
+	adr.Context = nil
+
+	plain := "# adr-7: Event bus\n\n**Status:** proposed\n**Date:** 2025-11-02\n\n## Context\nWritten by hand.\n"
+
+	withFrontMatter, err := MergeWithOptions(adr, plain, DefaultTemplate(), Options{FrontMatter: true})
+	require.NoError(t, err)
+	assert.Contains(t, withFrontMatter, `date: "2025-11-02"`)
+	assert.Contains(t, withFrontMatter, "Written by hand.")
+	assert.NotContains(t, withFrontMatter, "**Date:**")
+
+	back, err := MergeWithOptions(adr, withFrontMatter, DefaultTemplate(), Options{})
+	require.NoError(t, err)
+	assert.False(t, strings.HasPrefix(back, "---"))
+	assert.Contains(t, back, "**Date:** 2025-11-02")
+	assert.Contains(t, back, "Written by hand.")
+}
+
+func TestSetStatus_FrontMatter(t *testing.T) {
+	content := "---\nid: adr-1\nstatus: accepted\n---\n\n# adr-1: Test\n\n## Context\nstatus: not this one\n"
+
+	updated, ok := SetStatus(content, "deprecated")
+	assert.True(t, ok)
+	assert.Equal(t, "---\nid: adr-1\nstatus: deprecated\n---\n\n# adr-1: Test\n\n## Context\nstatus: not this one\n", updated)
+}
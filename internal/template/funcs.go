@@ -0,0 +1,249 @@
+package template
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/weaby/adr-buddy/internal/lifecycle"
+)
+
+// dateLayouts are the formats date accepts for string input, most specific first
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// FuncMap returns the functions available to ADR templates. Functions that
+// transform a value take it as their last argument so they work in pipelines,
+// e.g. {{.Context | join "\n\n"}}.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"join":           join,
+		"upper":          strings.ToUpper,
+		"lower":          strings.ToLower,
+		"title":          title,
+		"trim":           strings.TrimSpace,
+		"replace":        replace,
+		"date":           date,
+		"relpath":        relpath,
+		"slug":           slug,
+		"markdownEscape": markdownEscape,
+		"indent":         indent,
+		"default":        defaultValue,
+		"statusBadge":    statusBadge,
+	}
+}
+
+// join joins the elements of a slice with sep. A string is returned as is.
+func join(sep string, items any) string {
+	return strings.Join(toStrings(items), sep)
+}
+
+// toStrings formats every element of a slice or array with fmt.Sprint
+func toStrings(items any) []string {
+	if items == nil {
+		return nil
+	}
+	if s, ok := items.([]string); ok {
+		return s
+	}
+
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []string{fmt.Sprint(items)}
+	}
+
+	out := make([]string, v.Len())
+	for i := range out {
+		out[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return out
+}
+
+// title upper-cases the first letter of every word, treating spaces,
+// hyphens and underscores as word boundaries
+func title(s string) string {
+	runes := []rune(s)
+	start := true
+	for i, r := range runes {
+		if start {
+			runes[i] = unicode.ToUpper(r)
+		}
+		start = unicode.IsSpace(r) || r == '-' || r == '_'
+	}
+	return string(runes)
+}
+
+// replace replaces every occurrence of old with new in s
+func replace(old, new, s string) string {
+	return strings.ReplaceAll(s, old, new)
+}
+
+// date formats value with a Go time layout. value may be a time.Time or a
+// string in RFC 3339 or YYYY-MM-DD form; other strings are returned unchanged.
+func date(layout string, value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(layout)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(layout)
+	case string:
+		for _, l := range dateLayouts {
+			if t, err := time.Parse(l, strings.TrimSpace(v)); err == nil {
+				return t.Format(layout)
+			}
+		}
+		return v
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// relpath returns the slash-separated path to target relative to the
+// directory base, e.g. for linking from an ADR to a source file
+func relpath(base, target string) string {
+	base = path.Clean(toSlash(base))
+	target = path.Clean(toSlash(target))
+
+	if path.IsAbs(base) != path.IsAbs(target) {
+		return target
+	}
+
+	baseParts := splitPath(base)
+	targetParts := splitPath(target)
+
+	common := 0
+	for common < len(baseParts) && common < len(targetParts) && baseParts[common] == targetParts[common] {
+		common++
+	}
+
+	var parts []string
+	for range baseParts[common:] {
+		parts = append(parts, "..")
+	}
+	parts = append(parts, targetParts[common:]...)
+
+	if len(parts) == 0 {
+		return "."
+	}
+	return strings.Join(parts, "/")
+}
+
+func toSlash(p string) string {
+	return strings.ReplaceAll(p, "\\", "/")
+}
+
+// splitPath splits a cleaned slash path into its elements
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" || p == "." {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// slug lower-cases s and joins runs of letters and digits with hyphens,
+// matching the anchors GitHub generates for headings closely enough for
+// ADR titles
+func slug(s string) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingHyphen = true
+	}
+
+	return b.String()
+}
+
+// markdownEscape backslash-escapes characters with inline meaning in
+// Markdown, and a leading '#', so text renders literally
+func markdownEscape(s string) string {
+	var b strings.Builder
+	lineStart := true
+
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune("\\`*_[]<>|~", r):
+			b.WriteByte('\\')
+		case r == '#' && lineStart:
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+		lineStart = r == '\n'
+	}
+
+	return b.String()
+}
+
+// indent prefixes every non-empty line of s with n spaces
+func indent(n int, s string) string {
+	if n <= 0 {
+		return s
+	}
+
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// defaultValue returns def when value is empty: nil, false, zero, or an
+// empty string, slice or map
+func defaultValue(def, value any) any {
+	if isEmpty(value) {
+		return def
+	}
+	return value
+}
+
+func isEmpty(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+// statusBadge renders status as a shields.io badge image in Markdown,
+// colored as in the default lifecycle
+func statusBadge(status string) string {
+	return statusBadgeIn(lifecycle.Default(), status)
+}
+
+// statusBadgeIn renders a status badge colored as in statuses. Statuses
+// that aren't in it get a neutral color.
+func statusBadgeIn(statuses lifecycle.Lifecycle, status string) string {
+	status = strings.TrimSpace(status)
+	color := strings.TrimPrefix(statuses.Color(status), "#")
+
+	// shields.io uses '-' as separator; literal '-' and '_' are doubled
+	label := url.PathEscape(strings.NewReplacer("-", "--", "_", "__", " ", "_").Replace(status))
+	return fmt.Sprintf("![Status: %s](https://img.shields.io/badge/status-%s-%s)", status, label, color)
+}
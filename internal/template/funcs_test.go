@@ -0,0 +1,154 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaby/adr-buddy/internal/lifecycle"
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+func TestJoin(t *testing.T) {
+	assert.Equal(t, "a, b, c", join(", ", []string{"a", "b", "c"}))
+	assert.Equal(t, "", join(", ", []string{}))
+	assert.Equal(t, "", join(", ", nil))
+	assert.Equal(t, "solo", join(", ", "solo"))
+
+	locations := []model.SourceLocation{{File: "a.go", Line: 1}, {File: "b.go", Line: 2}}
+	assert.Equal(t, "a.go:1 b.go:2", join(" ", locations))
+}
+
+func TestTitle(t *testing.T) {
+	assert.Equal(t, "Accepted", title("accepted"))
+	assert.Equal(t, "Work-In Progress", title("work-in progress"))
+	assert.Equal(t, "Needs_Review", title("needs_review"))
+	assert.Equal(t, "", title(""))
+}
+
+func TestReplace(t *testing.T) {
+	assert.Equal(t, "a_b_c", replace("-", "_", "a-b-c"))
+}
+
+func TestDate(t *testing.T) {
+	assert.Equal(t, "January 17, 2026", date("January 2, 2006", "2026-01-17"))
+	assert.Equal(t, "17.01.2026", date("02.01.2006", "2026-01-17T10:30:00Z"))
+	assert.Equal(t, "not a date", date("2006", "not a date"))
+	assert.Equal(t, "", date("2006", ""))
+
+	ts := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2026/03/04", date("2006/01/02", ts))
+	assert.Equal(t, "2026/03/04", date("2006/01/02", &ts))
+}
+
+func TestRelpath(t *testing.T) {
+	tests := []struct {
+		base, target, want string
+	}{
+		{"decisions", "src/app.go", "../src/app.go"},
+		{"decisions/backend", "src/app.go", "../../src/app.go"},
+		{"decisions", "decisions/adr-1.md", "adr-1.md"},
+		{".", "src/app.go", "src/app.go"},
+		{"decisions", "decisions", "."},
+		{"./decisions/", "./src//app.go", "../src/app.go"},
+		{"/abs/decisions", "/abs/src/app.go", "../src/app.go"},
+		{"decisions", "/abs/src/app.go", "/abs/src/app.go"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, relpath(tt.base, tt.target), "relpath(%q, %q)", tt.base, tt.target)
+	}
+}
+
+func TestSlug(t *testing.T) {
+	assert.Equal(t, "adr-1-use-postgresql", slug("adr-1: Use PostgreSQL"))
+	assert.Equal(t, "caching-redis-vs-memcached", slug("  Caching: Redis vs. Memcached!  "))
+	assert.Equal(t, "café-au-lait", slug("Café au lait"))
+	assert.Equal(t, "", slug("---"))
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	assert.Equal(t, `use \*pino\* \_now\_`, markdownEscape("use *pino* _now_"))
+	assert.Equal(t, `\[link\](url) \<b\> a\|b \`+"`code\\`", markdownEscape("[link](url) <b> a|b `code`"))
+	assert.Equal(t, `\# not a heading`+"\n"+`\# nor this, but # this stays`, markdownEscape("# not a heading\n# nor this, but # this stays"))
+	assert.Equal(t, `C:\\path`, markdownEscape(`C:\path`))
+	assert.Equal(t, "plain text", markdownEscape("plain text"))
+}
+
+func TestIndent(t *testing.T) {
+	assert.Equal(t, "  a\n\n  b", indent(2, "a\n\nb"))
+	assert.Equal(t, "    x\n", indent(4, "x\n"))
+	assert.Equal(t, "a\nb", indent(0, "a\nb"))
+}
+
+func TestDefaultValue(t *testing.T) {
+	assert.Equal(t, "none", defaultValue("none", ""))
+	assert.Equal(t, "none", defaultValue("none", nil))
+	assert.Equal(t, "none", defaultValue("none", []string{}))
+	assert.Equal(t, "none", defaultValue("none", 0))
+	assert.Equal(t, "none", defaultValue("none", false))
+	assert.Equal(t, "set", defaultValue("none", "set"))
+	assert.Equal(t, []string{"a"}, defaultValue("none", []string{"a"}))
+	assert.Equal(t, 3, defaultValue("none", 3))
+}
+
+func TestStatusBadge(t *testing.T) {
+	assert.Equal(t, "![Status: accepted](https://img.shields.io/badge/status-accepted-d1e7dd)", statusBadge("accepted"))
+	assert.Equal(t, "![Status: Proposed](https://img.shields.io/badge/status-Proposed-cfe2ff)", statusBadge("Proposed"))
+	assert.Equal(t, "![Status: on-hold](https://img.shields.io/badge/status-on--hold-eeeeee)", statusBadge("on-hold"))
+	assert.Equal(t, "![Status: in review](https://img.shields.io/badge/status-in_review-eeeeee)", statusBadge("in review"))
+	assert.Equal(t, "![Status: a/b](https://img.shields.io/badge/status-a%2Fb-eeeeee)", statusBadge("a/b"))
+
+	// Configured statuses get their own colors
+	statuses := lifecycle.Lifecycle{Statuses: []string{"proposed", "on-hold"}, Colors: map[string]string{"on-hold": "#ffe5b4"}}
+	assert.Equal(t, "![Status: on-hold](https://img.shields.io/badge/status-on--hold-ffe5b4)", statusBadgeIn(statuses, "on-hold"))
+	out, err := RenderWithOptions(&model.ADR{ID: "adr-1", Status: "on-hold"}, "{{statusBadge .Status}}", Options{Lifecycle: &statuses})
+	require.NoError(t, err)
+	assert.Equal(t, "![Status: on-hold](https://img.shields.io/badge/status-on--hold-ffe5b4)", out)
+}
+
+func TestFuncMap_InTemplates(t *testing.T) {
+	tmpl := `# {{.ID}}: {{.Name | upper}}
+Status: {{.Status | title}} {{statusBadge .Status}}
+Date: {{date "Jan 2, 2006" .Date}}
+Category: {{.Category | default "uncategorized" | lower}}
+Anchor: #{{slug .Name}}
+
+## Context
+{{.Context | join "\n\n" | trim | indent 2}}
+
+## Code Locations
+{{range .Locations}}- [{{.File}}]({{relpath "decisions" .File}})
+{{end}}`
+
+	adr := &model.ADR{
+		ID:        "adr-1",
+		Name:      "Use Pino",
+		Status:    "accepted",
+		Date:      "2026-01-17",
+		Context:   []string{"Fast", "Structured"},
+		Locations: []model.SourceLocation{{File: "src/logger.js", Line: 10}},
+	}
+
+	rendered, err := Render(adr, tmpl)
+	require.NoError(t, err)
+	assert.Contains(t, rendered, "# adr-1: USE PINO")
+	assert.Contains(t, rendered, "Status: Accepted ![Status: accepted]")
+	assert.Contains(t, rendered, "Date: Jan 17, 2026")
+	assert.Contains(t, rendered, "Category: uncategorized")
+	assert.Contains(t, rendered, "Anchor: #use-pino")
+	assert.Contains(t, rendered, "  Fast\n\n  Structured")
+	assert.Contains(t, rendered, "- [src/logger.js](../src/logger.js)")
+
+	// The merge path uses the same functions
+	merged, err := Merge(adr, "# adr-1: Use Pino\n\n**Date:** 2025-12-01\n", tmpl)
+	require.NoError(t, err)
+	assert.Contains(t, merged, "Date: Dec 1, 2025")
+	assert.Contains(t, merged, "- [src/logger.js](../src/logger.js)")
+}
+
+func TestFuncMap_UnknownFunction(t *testing.T) {
+	_, err := Render(&model.ADR{ID: "adr-1"}, "{{nope .ID}}")
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package template
+
+import "bytes"
+
+// IndexEntry is one ADR listed in an index
+type IndexEntry struct {
+	ID       string
+	Name     string
+	Status   string
+	Category string
+	Date     string
+	Link     string // Path of the ADR file relative to the index
+}
+
+// IndexGroup is the ADRs of one category in an index
+type IndexGroup struct {
+	Category string // Empty for ADRs without a category
+	Link     string // Path of the category's own index, empty if it has none
+	Entries  []IndexEntry
+}
+
+// Index is what index templates execute against
+type Index struct {
+	Category string       // Category the index covers, empty for the top-level index
+	Entries  []IndexEntry // Every ADR, sorted by ID
+	Groups   []IndexGroup // ADRs grouped by category, sorted by category
+	Graph    string       // Mermaid diagram of how the decisions relate, if enabled
+}
+
+// DefaultIndexTemplate returns the built-in index template
+func DefaultIndexTemplate() string {
+	return `# {{if .Category}}{{.Category}} Decisions{{else}}Architecture Decision Records{{end}}
+{{if .Graph}}
+## Decision Graph
+
+` + "```mermaid\n{{.Graph}}```" + `
+{{end}}{{range .Groups}}
+## {{if .Link}}[{{.Category}}]({{.Link}}){{else if .Category}}{{.Category}}{{else}}Uncategorized{{end}}
+
+| ID | Title | Status | Category | Date |
+|----|-------|--------|----------|------|
+{{range .Entries}}| [{{.ID}}]({{.Link}}) | [{{markdownEscape .Name}}]({{.Link}}) | {{.Status}} | {{.Category}} | {{.Date}} |
+{{end}}{{else}}
+No decisions yet.
+{{end}}`
+}
+
+// RenderIndex renders an index using the provided template. Partials in
+// opts are available to it like to ADR templates.
+func RenderIndex(index *Index, tmplStr string, opts Options) (string, error) {
+	tmpl, err := newTemplate(tmplStr, opts.Partials)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.execute(&buf, index, opts.lifecycle()); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,48 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderIndex_Default(t *testing.T) {
+	index := &Index{
+		Groups: []IndexGroup{
+			{Entries: []IndexEntry{{ID: "adr-1", Name: "Logging", Status: "accepted", Date: "2026-01-17", Link: "adr-1.md"}}},
+			{Category: "api", Link: "api/README.md", Entries: []IndexEntry{{ID: "adr-2", Name: "A|B", Status: "proposed", Category: "api", Date: "2026-01-18", Link: "api/adr-2.md"}}},
+		},
+	}
+
+	result, err := RenderIndex(index, DefaultIndexTemplate(), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, `# Architecture Decision Records
+
+## Uncategorized
+
+| ID | Title | Status | Category | Date |
+|----|-------|--------|----------|------|
+| [adr-1](adr-1.md) | [Logging](adr-1.md) | accepted |  | 2026-01-17 |
+
+## [api](api/README.md)
+
+| ID | Title | Status | Category | Date |
+|----|-------|--------|----------|------|
+| [adr-2](api/adr-2.md) | [A\|B](api/adr-2.md) | proposed | api | 2026-01-18 |
+`, result)
+}
+
+func TestRenderIndex_Empty(t *testing.T) {
+	result, err := RenderIndex(&Index{Category: "api"}, DefaultIndexTemplate(), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "# api Decisions\n\nNo decisions yet.\n", result)
+}
+
+func TestRenderIndex_Custom(t *testing.T) {
+	index := &Index{Entries: []IndexEntry{{ID: "adr-1"}, {ID: "adr-2"}}}
+
+	result, err := RenderIndex(index, `{{template "count" .}}`, Options{Partials: map[string]string{"count": "{{len .Entries}} decisions"}})
+	require.NoError(t, err)
+	assert.Equal(t, "2 decisions", result)
+}
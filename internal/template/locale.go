@@ -0,0 +1,186 @@
+package template
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is the locale used when none is configured
+const DefaultLocale = "en"
+
+// Locale holds the text the default template renders in one language:
+// section headings, placeholder text, metadata labels and status names.
+// Templates reach it as {{.Locale}}.
+type Locale struct {
+	Code         string
+	Name         string
+	Headings     Sections // Headings of the prose sections
+	Placeholders Sections // Prompts rendered in empty prose sections
+	Locations    string   // Heading of the code locations section
+	Labels       Labels
+	Todo         string            // Marks a placeholder, e.g. "TODO" in <!-- TODO: ... -->
+	Statuses     map[string]string // Status names, keyed by the status annotations use
+}
+
+// Sections holds a piece of text for each prose field
+type Sections struct {
+	Context      string
+	Decision     string
+	Alternatives string
+	Consequences string
+}
+
+// Labels are the names of the metadata lines, e.g. "**Status:** accepted"
+type Labels struct {
+	Status   string
+	Date     string
+	Category string
+}
+
+// StatusLabel returns the localised name of a status, or the status itself
+// if the locale has no name for it
+func (l *Locale) StatusLabel(status string) string {
+	if label, ok := l.Statuses[strings.ToLower(status)]; ok {
+		return label
+	}
+	return status
+}
+
+// locales are the supported languages, keyed by code
+var locales = map[string]*Locale{
+	"en": {
+		Code: "en",
+		Name: "English",
+		Headings: Sections{
+			Context:      "Context",
+			Decision:     "Decision",
+			Alternatives: "Alternatives Considered",
+			Consequences: "Consequences",
+		},
+		Placeholders: Sections{
+			Context:      "Add context - what is the issue we're facing?",
+			Decision:     "Document the decision and rationale",
+			Alternatives: "What alternatives were considered and why were they rejected?",
+			Consequences: "What are the positive/negative outcomes?",
+		},
+		Locations: "Code Locations",
+		Labels:    Labels{Status: "Status", Date: "Date", Category: "Category"},
+		Todo:      "TODO",
+	},
+	"de": {
+		Code: "de",
+		Name: "Deutsch",
+		Headings: Sections{
+			Context:      "Kontext",
+			Decision:     "Entscheidung",
+			Alternatives: "Betrachtete Alternativen",
+			Consequences: "Konsequenzen",
+		},
+		Placeholders: Sections{
+			Context:      "Kontext beschreiben - vor welchem Problem stehen wir?",
+			Decision:     "Entscheidung und Begründung dokumentieren",
+			Alternatives: "Welche Alternativen wurden betrachtet und warum wurden sie verworfen?",
+			Consequences: "Welche positiven und negativen Folgen hat die Entscheidung?",
+		},
+		Locations: "Code-Stellen",
+		Labels:    Labels{Status: "Status", Date: "Datum", Category: "Kategorie"},
+		Todo:      "OFFEN",
+		Statuses: map[string]string{
+			"proposed":   "vorgeschlagen",
+			"accepted":   "angenommen",
+			"deprecated": "veraltet",
+			"superseded": "ersetzt",
+			"rejected":   "abgelehnt",
+		},
+	},
+	"ja": {
+		Code: "ja",
+		Name: "日本語",
+		Headings: Sections{
+			Context:      "背景",
+			Decision:     "決定",
+			Alternatives: "検討した代替案",
+			Consequences: "結果",
+		},
+		Placeholders: Sections{
+			Context:      "背景を記述してください。どのような課題に直面していますか?",
+			Decision:     "決定内容とその理由を記述してください",
+			Alternatives: "どのような代替案を検討し、なぜ採用しなかったのですか?",
+			Consequences: "この決定による良い影響と悪い影響は何ですか?",
+		},
+		Locations: "コードの場所",
+		Labels:    Labels{Status: "ステータス", Date: "日付", Category: "カテゴリ"},
+		Todo:      "未記入",
+		Statuses: map[string]string{
+			"proposed":   "提案",
+			"accepted":   "承認",
+			"deprecated": "非推奨",
+			"superseded": "置換",
+			"rejected":   "却下",
+		},
+	},
+}
+
+// LookupLocale returns the locale with the given code
+func LookupLocale(code string) (*Locale, bool) {
+	l, ok := locales[strings.ToLower(strings.TrimSpace(code))]
+	return l, ok
+}
+
+// LocaleCodes returns the codes of the supported locales, sorted
+func LocaleCodes() []string {
+	codes := make([]string, 0, len(locales))
+	for code := range locales {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// localeFor returns the locale with the given code, English if it's empty
+// or unknown
+func localeFor(code string) *Locale {
+	if l, ok := LookupLocale(code); ok {
+		return l
+	}
+	return locales[DefaultLocale]
+}
+
+// canonicalStatus maps a status name in any locale back to the status
+// annotations use, e.g. "angenommen" to "accepted"
+func canonicalStatus(label string) string {
+	for _, code := range LocaleCodes() {
+		for status, name := range locales[code].Statuses {
+			if strings.EqualFold(name, label) {
+				return status
+			}
+		}
+	}
+	return label
+}
+
+// labelPattern returns a regexp alternation matching a metadata label in
+// any locale, e.g. "Date|Datum|日付"
+func labelPattern(label func(Labels) string) string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, code := range LocaleCodes() {
+		name := label(locales[code].Labels)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, regexp.QuoteMeta(name))
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// sectionsByField returns the text of each prose field, keyed by field name
+func sectionsByField(s Sections) map[string]string {
+	return map[string]string{
+		"Context":      s.Context,
+		"Decision":     s.Decision,
+		"Alternatives": s.Alternatives,
+		"Consequences": s.Consequences,
+	}
+}
@@ -0,0 +1,107 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+func TestRender_Locale(t *testing.T) {
+	adr := &model.ADR{ID: "adr-1", Name: "Test", Status: "accepted", Date: "2026-01-17", Category: "backend"}
+
+	result, err := RenderWithOptions(adr, DefaultTemplate(), Options{Locale: "de"})
+	require.NoError(t, err)
+	assert.Contains(t, result, "**Status:** angenommen\n**Datum:** 2026-01-17\n**Kategorie:** backend")
+	assert.Contains(t, result, "## Kontext\n")
+	assert.Contains(t, result, "<!-- OFFEN: Entscheidung und Begründung dokumentieren -->")
+	assert.Contains(t, result, "## Code-Stellen\n")
+
+	result, err = RenderWithOptions(adr, DefaultTemplate(), Options{Locale: "ja"})
+	require.NoError(t, err)
+	assert.Contains(t, result, "**ステータス:** 承認\n**日付:** 2026-01-17")
+	assert.Contains(t, result, "## 検討した代替案\n")
+}
+
+func TestParseExistingADR_Localised(t *testing.T) {
+	content := `# adr-1: Test
+
+**Status:** veraltet
+**Datum:** 2025-03-01
+**Kategorie:** backend
+
+## Kontext
+
+Von Hand geschrieben.
+
+## Entscheidung
+
+<!-- OFFEN: Entscheidung und Begründung dokumentieren -->
+`
+	parsed := ParseExistingADR(content)
+	assert.Equal(t, "deprecated", parsed.Frontmatter["Status"])
+	assert.Equal(t, "2025-03-01", parsed.Frontmatter["Date"])
+	assert.Equal(t, "backend", parsed.Frontmatter["Category"])
+	assert.Equal(t, "Von Hand geschrieben.", parsed.Sections["Context"])
+	assert.True(t, IsPlaceholder(parsed.Sections["Decision"]))
+}
+
+func TestMerge_Locale(t *testing.T) {
+	opts := Options{Locale: "ja"}
+	adr := &model.ADR{ID: "adr-1", Name: "Test", Status: "proposed", Date: "2026-01-17", Decision: []string{"Use Go"}}
+
+	existing := "# adr-1: Test\n\n**ステータス:** 提案\n**日付:** 2025-03-01\n\n## 背景\n\n手書きの背景。\n\n## 決定\n\n<!-- 未記入: 決定内容とその理由を記述してください -->\n"
+
+	result, err := MergeWithOptions(adr, existing, DefaultTemplate(), opts)
+	require.NoError(t, err)
+	assert.Contains(t, result, "**日付:** 2025-03-01")
+	assert.Contains(t, result, "手書きの背景。")
+	assert.Contains(t, result, "Use Go")
+	assert.Contains(t, result, "<!-- 未記入: この決定による良い影響と悪い影響は何ですか? -->")
+}
+
+func TestMerge_SwitchLocale(t *testing.T) {
+	english := "# adr-1: Test\n\n**Status:** accepted\n**Date:** 2025-03-01\n\n## Context\n\nWritten by hand.\n\n## Decision\n\n<!-- TODO: Document the decision and rationale -->\n"
+	adr := &model.ADR{ID: "adr-1", Name: "Test", Status: "accepted", Date: "2026-01-17"}
+
+	result, err := MergeWithOptions(adr, english, DefaultTemplate(), Options{Locale: "de"})
+	require.NoError(t, err)
+	assert.Contains(t, result, "**Datum:** 2025-03-01")
+	assert.Equal(t, "Written by hand.", ParseExistingADR(result).Sections["Context"])
+	assert.Contains(t, result, "<!-- OFFEN: Entscheidung und Begründung dokumentieren -->")
+	assert.NotContains(t, result, "TODO")
+}
+
+func TestSetStatusWithOptions_Locale(t *testing.T) {
+	content := "# adr-1: Test\n\n**Status:** angenommen\n**Datum:** 2025-03-01\n"
+
+	updated, ok := SetStatusWithOptions(content, "deprecated", Options{Locale: "de"})
+	assert.True(t, ok)
+	assert.Contains(t, updated, "**Status:** veraltet\n")
+
+	withFrontMatter := "---\nid: adr-1\nstatus: accepted\n---\n\n# adr-1: Test\n\n**ステータス:** 承認\n"
+	updated, ok = SetStatusWithOptions(withFrontMatter, "deprecated", Options{Locale: "ja"})
+	assert.True(t, ok)
+	assert.Contains(t, updated, "status: deprecated\n")
+	assert.Contains(t, updated, "**ステータス:** 非推奨\n")
+}
+
+func TestValidate_Locales(t *testing.T) {
+	for _, code := range LocaleCodes() {
+		problems := Validate(DefaultTemplate(), Options{Locale: code})
+		assert.Empty(t, problems, code)
+	}
+}
+
+func TestLookupLocale(t *testing.T) {
+	l, ok := LookupLocale(" DE ")
+	require.True(t, ok)
+	assert.Equal(t, "de", l.Code)
+	assert.Equal(t, "custom", l.StatusLabel("custom"))
+
+	_, ok = LookupLocale("xx")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"de", "en", "ja"}, LocaleCodes())
+}
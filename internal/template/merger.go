@@ -5,72 +5,356 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
-	"text/template"
+	"sync"
 
 	"github.com/weaby/adr-buddy/internal/model"
 )
 
+// proseFields are the ADR fields holding prose that may be written by hand
+var proseFields = []string{"Context", "Decision", "Alternatives", "Consequences"}
+
+// headingAliases are headings that common ADR formats use for each prose
+// field. Headings from the built-in presets are added to these, so content
+// survives switching between formats.
+var headingAliases = map[string][]string{
+	"Context":      {"Context", "Context and Problem Statement", "Problem Statement", "Background"},
+	"Decision":     {"Decision", "Decision Outcome"},
+	"Alternatives": {"Alternatives Considered", "Alternatives", "Considered Options", "Options"},
+	"Consequences": {"Consequences"},
+}
+
+// Metadata lines in the body, e.g. "**Status:** accepted" or "**Datum:** 2026-01-17"
+var (
+	statusLabelRe   = regexp.MustCompile(`(\*\*(?:` + labelPattern(func(l Labels) string { return l.Status }) + `):\*\*[ \t]*)(.+)`)
+	dateRe          = regexp.MustCompile(`(?m)\*\*(?:` + labelPattern(func(l Labels) string { return l.Date }) + `):\*\*\s*(.+)|^(?:` + labelPattern(func(l Labels) string { return l.Date }) + `):[ \t]*(.+)$`)
+	categoryLabelRe = regexp.MustCompile(`\*\*(?:` + labelPattern(func(l Labels) string { return l.Category }) + `):\*\*\s*(.+)`)
+)
+
+var (
+	knownHeadingsOnce sync.Once
+	knownHeadings     map[string][]string
+)
+
 // ParsedADR represents a parsed existing ADR file
 type ParsedADR struct {
 	Frontmatter map[string]string
 	Sections    map[string]string
+	Metadata    *Metadata // YAML front matter, nil if the file has none
 }
 
-// ParseExistingADR parses an existing ADR markdown file
+// ParseExistingADR parses an existing ADR markdown file, recognising the
+// section headings of every built-in preset
 func ParseExistingADR(content string) *ParsedADR {
+	return parseExistingADR(content, nil)
+}
+
+// ParseExistingADRWithOptions parses an existing ADR like ParseExistingADR,
+// looking for each prose section under the heading tmpl renders it with first
+func ParseExistingADRWithOptions(content, tmpl string, opts Options) *ParsedADR {
+	headings, _ := sectionHeadings(tmpl, opts)
+	return parseExistingADR(content, headings)
+}
+
+// parseExistingADR parses an existing ADR, looking for each prose field
+// under the heading the current template uses before trying known aliases
+func parseExistingADR(content string, headings map[string]string) *ParsedADR {
 	parsed := &ParsedADR{
 		Frontmatter: make(map[string]string),
 		Sections:    make(map[string]string),
 	}
 
-	// Parse frontmatter (Status, Date, Category)
-	statusRe := regexp.MustCompile(`\*\*Status:\*\*\s*(.+)`)
-	dateRe := regexp.MustCompile(`\*\*Date:\*\*\s*(.+)`)
-	categoryRe := regexp.MustCompile(`\*\*Category:\*\*\s*(.+)`)
+	parsed.Metadata, content = splitFrontMatter(content)
+
+	// Parse title heading (# ID: Name)
+	titleRe := regexp.MustCompile(`(?m)^# ([^:\s]+):\s*(.+)$`)
+	if match := titleRe.FindStringSubmatch(content); match != nil {
+		parsed.Frontmatter["ID"] = match[1]
+		parsed.Frontmatter["Name"] = strings.TrimSpace(match[2])
+	}
 
-	if match := statusRe.FindStringSubmatch(content); match != nil {
-		parsed.Frontmatter["Status"] = strings.TrimSpace(match[1])
+	// Parse frontmatter (Status, Date, Category), labelled in any locale
+	if match := statusLabelRe.FindStringSubmatch(content); match != nil {
+		parsed.Frontmatter["Status"] = canonicalStatus(strings.TrimSpace(match[2]))
 	}
 	if match := dateRe.FindStringSubmatch(content); match != nil {
-		parsed.Frontmatter["Date"] = strings.TrimSpace(match[1])
+		parsed.Frontmatter["Date"] = strings.TrimSpace(match[1] + match[2])
 	}
-	if match := categoryRe.FindStringSubmatch(content); match != nil {
+	if match := categoryLabelRe.FindStringSubmatch(content); match != nil {
 		parsed.Frontmatter["Category"] = strings.TrimSpace(match[1])
 	}
 
-	// Parse sections (Context, Decision, Consequences)
-	// Match from ## SectionName until next ## or end of string
-	parseSections := []string{"Context", "Decision", "Consequences"}
-	for _, sectionName := range parseSections {
-		// Pattern: ## SectionName followed by content until next ## or end
-		pattern := fmt.Sprintf(`(?s)## %s\s*\n(.*?)(?:## |\z)`, sectionName)
-		re := regexp.MustCompile(pattern)
-		if match := re.FindStringSubmatch(content); match != nil {
-			parsed.Sections[sectionName] = strings.TrimSpace(match[1])
+	// Front matter takes precedence over metadata in the body
+	if meta := parsed.Metadata; meta != nil {
+		for key, value := range map[string]string{
+			"ID":       meta.ID,
+			"Name":     meta.Name,
+			"Status":   meta.Status,
+			"Date":     meta.Date,
+			"Category": meta.Category,
+		} {
+			if value != "" {
+				parsed.Frontmatter[key] = value
+			}
+		}
+	}
+
+	mdHeadings := findHeadings(content)
+
+	// Formats such as Nygard's keep the status in its own section
+	if _, ok := parsed.Frontmatter["Status"]; !ok {
+		if start, end, ok := statusLine(content, mdHeadings); ok {
+			parsed.Frontmatter["Status"] = canonicalStatus(content[start:end])
+		}
+	}
+
+	// Parse prose sections. Each runs until the next heading of the same or
+	// higher level, or the next heading that starts another prose section.
+	candidates := make(map[string][]string)
+	isSection := make(map[string]bool)
+	for _, field := range proseFields {
+		if heading := headings[field]; heading != "" {
+			candidates[field] = append(candidates[field], heading)
+		}
+		candidates[field] = append(candidates[field], sectionAliases()[field]...)
+		for _, heading := range candidates[field] {
+			isSection[strings.ToLower(heading)] = true
+		}
+	}
+
+	used := make(map[int]bool)
+	for _, field := range proseFields {
+		for _, heading := range candidates[field] {
+			i := indexHeading(mdHeadings, heading, used)
+			if i < 0 {
+				continue
+			}
+			used[i] = true
+
+			end := len(content)
+			for _, next := range mdHeadings[i+1:] {
+				if next.level <= mdHeadings[i].level || isSection[strings.ToLower(next.title)] {
+					end = next.start
+					break
+				}
+			}
+			parsed.Sections[field] = strings.TrimSpace(content[mdHeadings[i].body:end])
+			break
 		}
 	}
 
 	return parsed
 }
 
-// isPlaceholder checks if a section contains only a TODO placeholder
-func isPlaceholder(content string) bool {
+// sectionAliases returns the headings known for each prose field: the
+// built-in aliases, the headings of every locale, and the headings the
+// presets render
+func sectionAliases() map[string][]string {
+	knownHeadingsOnce.Do(func() {
+		knownHeadings = make(map[string][]string)
+		for field, aliases := range headingAliases {
+			knownHeadings[field] = append(knownHeadings[field], aliases...)
+		}
+		for _, code := range LocaleCodes() {
+			for field, heading := range sectionsByField(locales[code].Headings) {
+				if indexFold(knownHeadings[field], heading) < 0 {
+					knownHeadings[field] = append(knownHeadings[field], heading)
+				}
+			}
+		}
+		for _, preset := range Presets() {
+			headings, err := sectionHeadings(preset.Template, Options{})
+			if err != nil {
+				continue
+			}
+			for field, heading := range headings {
+				if indexFold(knownHeadings[field], heading) < 0 {
+					knownHeadings[field] = append(knownHeadings[field], heading)
+				}
+			}
+		}
+	})
+	return knownHeadings
+}
+
+func indexFold(list []string, s string) int {
+	for i, item := range list {
+		if strings.EqualFold(item, s) {
+			return i
+		}
+	}
+	return -1
+}
+
+// mdHeading is an ATX heading in a markdown document
+type mdHeading struct {
+	level int
+	title string
+	start int // Offset of the heading line
+	body  int // Offset just past the heading line
+}
+
+var headingRe = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)[ \t]*#*[ \t]*$`)
+
+// findHeadings returns the headings of a markdown document in order,
+// skipping lines inside fenced code blocks
+func findHeadings(content string) []mdHeading {
+	var headings []mdHeading
+	inFence := false
+
+	for offset := 0; offset < len(content); {
+		end := strings.IndexByte(content[offset:], '\n')
+		next := len(content)
+		if end >= 0 {
+			next = offset + end + 1
+		}
+		line := strings.TrimRight(content[offset:next], "\r\n")
+
+		trimmed := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		} else if !inFence {
+			if match := headingRe.FindStringSubmatch(line); match != nil {
+				headings = append(headings, mdHeading{
+					level: len(match[1]),
+					title: match[2],
+					start: offset,
+					body:  next,
+				})
+			}
+		}
+
+		offset = next
+	}
+
+	return headings
+}
+
+// indexHeading finds the first unused level 2+ heading matching title
+func indexHeading(headings []mdHeading, title string, used map[int]bool) int {
+	for i, h := range headings {
+		if h.level > 1 && !used[i] && strings.EqualFold(h.title, title) {
+			return i
+		}
+	}
+	return -1
+}
+
+// statusLine locates the first non-empty line of a "Status" section
+func statusLine(content string, headings []mdHeading) (int, int, bool) {
+	i := indexHeading(headings, "Status", nil)
+	if i < 0 {
+		return 0, 0, false
+	}
+
+	end := len(content)
+	if i+1 < len(headings) {
+		end = headings[i+1].start
+	}
+
+	for offset := headings[i].body; offset < end; {
+		lineEnd := strings.IndexByte(content[offset:end], '\n')
+		next := end
+		if lineEnd >= 0 {
+			next = offset + lineEnd
+		}
+		line := content[offset:next]
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			start := offset + strings.Index(line, trimmed)
+			return start, start + len(trimmed), true
+		}
+		offset = next + 1
+	}
+
+	return 0, 0, false
+}
+
+// sectionHeadings finds the heading each prose field is rendered under by
+// rendering tmpl with a marker in every field
+func sectionHeadings(tmpl string, opts Options) (map[string]string, error) {
+	marker := func(field string) []string {
+		return []string{"adrbuddyprobe" + strings.ToLower(field)}
+	}
+	probe := &model.ADR{
+		ID:           "adr-0",
+		Name:         "Probe",
+		Status:       "proposed",
+		Category:     "probe",
+		Date:         "2006-01-02",
+		Context:      marker("Context"),
+		Decision:     marker("Decision"),
+		Alternatives: marker("Alternatives"),
+		Consequences: marker("Consequences"),
+		Locations:    []model.SourceLocation{{File: "probe.go", Line: 1}},
+	}
+
+	rendered, err := RenderWithOptions(probe, tmpl, Options{Partials: opts.Partials, Locale: opts.Locale})
+	if err != nil {
+		return nil, err
+	}
+
+	headings := findHeadings(rendered)
+	lower := strings.ToLower(rendered)
+	result := make(map[string]string)
+
+	for _, field := range proseFields {
+		at := strings.Index(lower, marker(field)[0])
+		if at < 0 {
+			continue
+		}
+		for i := len(headings) - 1; i >= 0; i-- {
+			if headings[i].start < at {
+				if headings[i].level > 1 {
+					result[field] = headings[i].title
+				}
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// IsPlaceholder checks if a section contains only a placeholder, marked
+// as in "<!-- TODO: ... -->" in any locale
+func IsPlaceholder(content string) bool {
 	trimmed := strings.TrimSpace(content)
-	return strings.Contains(trimmed, "<!-- TODO:")
+	for _, code := range LocaleCodes() {
+		if strings.Contains(trimmed, "<!-- "+locales[code].Todo+":") {
+			return true
+		}
+	}
+	return false
 }
 
 // Merge intelligently merges an ADR with existing content
 // Rules:
 // 1. Preserve Date from existing file
-// 2. For each section (Context, Decision, Consequences):
+// 2. For each section (Context, Decision, Alternatives, Consequences):
 //   - If annotation provides content → use annotation content (replace)
 //   - If annotation empty AND section has manual content → preserve manual content
 //   - If annotation empty AND section is placeholder → keep placeholder
 //
 // 3. Status: Always use status from annotation (updates allowed)
 // 4. Locations: Always regenerate from current annotations
+//
+// Sections are found under the headings the template renders them with,
+// falling back to the headings of the built-in presets.
 func Merge(adr *model.ADR, existingContent string, tmpl string) (string, error) {
-	parsed := ParseExistingADR(existingContent)
+	return MergeWithOptions(adr, existingContent, tmpl, Options{})
+}
+
+// MergeWithOptions merges an ADR with existing content like Merge, rendering
+// with the given options. The date is kept from either front matter or the
+// body, so files can switch between the two.
+func MergeWithOptions(adr *model.ADR, existingContent string, tmpl string, opts Options) (string, error) {
+	t, err := newTemplate(tmpl, opts.Partials)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	// A template that can't render the probe still merges using the aliases
+	headings, _ := sectionHeadings(tmpl, opts)
+	parsed := parseExistingADR(existingContent, headings)
 
 	// Create merged ADR
 	merged := &model.ADR{
@@ -81,8 +365,13 @@ func Merge(adr *model.ADR, existingContent string, tmpl string) (string, error)
 		Date:         parsed.Frontmatter["Date"], // Preserve existing date
 		Context:      adr.Context,
 		Decision:     adr.Decision,
+		Alternatives: adr.Alternatives,
 		Consequences: adr.Consequences,
+		Tags:         adr.Tags,
+		Supersedes:   adr.Supersedes,
+		Relates:      adr.Relates,
 		Locations:    adr.Locations, // Always use new locations
+		CustomFields: adr.CustomFields,
 	}
 
 	// If Date is empty in existing, use new date
@@ -90,49 +379,60 @@ func Merge(adr *model.ADR, existingContent string, tmpl string) (string, error)
 		merged.Date = adr.Date
 	}
 
-	// Smart merge for Context
-	if len(adr.Context) == 0 {
-		// Annotation provides no context
-		existingContext := parsed.Sections["Context"]
-		if existingContext != "" && !isPlaceholder(existingContext) {
-			// Preserve manual content
-			merged.Context = []string{existingContext}
+	// Smart merge: where the annotation provides nothing, preserve manual
+	// content; placeholders are left empty so the template renders them again
+	sections := map[string]*[]string{
+		"Context":      &merged.Context,
+		"Decision":     &merged.Decision,
+		"Alternatives": &merged.Alternatives,
+		"Consequences": &merged.Consequences,
+	}
+	for _, field := range proseFields {
+		if len(*sections[field]) > 0 {
+			continue
+		}
+		existing := parsed.Sections[field]
+		if existing != "" && !IsPlaceholder(existing) {
+			*sections[field] = []string{existing}
 		}
-		// Otherwise keep empty (will render placeholder)
 	}
 
-	// Smart merge for Decision
-	if len(adr.Decision) == 0 {
-		// Annotation provides no decision
-		existingDecision := parsed.Sections["Decision"]
-		if existingDecision != "" && !isPlaceholder(existingDecision) {
-			// Preserve manual content
-			merged.Decision = []string{existingDecision}
-		}
-		// Otherwise keep empty (will render placeholder)
+	var buf bytes.Buffer
+	if err := t.execute(&buf, newTemplateData(merged, opts), opts.lifecycle()); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	// Smart merge for Consequences
-	if len(adr.Consequences) == 0 {
-		// Annotation provides no consequences
-		existingConsequences := parsed.Sections["Consequences"]
-		if existingConsequences != "" && !isPlaceholder(existingConsequences) {
-			// Preserve manual content
-			merged.Consequences = []string{existingConsequences}
+	return withFrontMatter(merged, buf.String(), opts)
+}
+
+// SetStatus rewrites the status of an existing ADR: in its front matter, and
+// in its status line or the first line of its Status section. Returns the updated content and whether a status
+// was found.
+func SetStatus(content, status string) (string, bool) {
+	return SetStatusWithOptions(content, status, Options{})
+}
+
+// SetStatusWithOptions rewrites the status like SetStatus, naming it in the
+// body in the given locale. Front matter always holds the status as is.
+func SetStatusWithOptions(content, status string, opts Options) (string, bool) {
+	label := localeFor(opts.Locale).StatusLabel(status)
+	if updated, ok := setFrontMatterStatus(content, status); ok {
+		// Keep a status line in the body, if any, in step
+		if body, ok := setBodyStatus(updated, label); ok {
+			return body, true
 		}
-		// Otherwise keep empty (will render placeholder)
+		return updated, true
 	}
+	return setBodyStatus(content, label)
+}
 
-	// Render the merged ADR using the template
-	t, err := template.New("adr").Parse(tmpl)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse template: %w", err)
+// setBodyStatus rewrites the status line or Status section of the body
+func setBodyStatus(content, status string) (string, bool) {
+	if loc := statusLabelRe.FindStringSubmatchIndex(content); loc != nil {
+		return content[:loc[3]] + status + content[loc[1]:], true
 	}
-
-	var buf bytes.Buffer
-	if err := t.Execute(&buf, merged); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
+	if start, end, ok := statusLine(content, findHeadings(content)); ok {
+		return content[:start] + status + content[end:], true
 	}
-
-	return buf.String(), nil
+	return content, false
 }
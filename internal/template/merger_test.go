@@ -28,6 +28,8 @@ Some manual consequences.
 
 	parsed := ParseExistingADR(existing)
 
+	assert.Equal(t, "adr-1", parsed.Frontmatter["ID"])
+	assert.Equal(t, "Test Decision", parsed.Frontmatter["Name"])
 	assert.Equal(t, "accepted", parsed.Frontmatter["Status"])
 	assert.Equal(t, "2026-01-15", parsed.Frontmatter["Date"])
 	assert.Contains(t, parsed.Sections["Context"], "Existing context")
@@ -35,6 +37,31 @@ Some manual consequences.
 	assert.Contains(t, parsed.Sections["Consequences"], "manual consequences")
 }
 
+func TestParseExistingADRWithOptions(t *testing.T) {
+	tmpl := `# {{.ID}}: {{.Name}}
+
+## Why
+{{range .Context}}{{.}}
+{{end}}
+## Outcome
+{{range .Decision}}{{.}}
+{{end}}`
+	existing := `# adr-1: Test Decision
+
+## Why
+Because we must.
+
+## Outcome
+We did it.
+`
+
+	parsed := ParseExistingADRWithOptions(existing, tmpl, Options{})
+
+	assert.Equal(t, "Because we must.", parsed.Sections["Context"])
+	assert.Equal(t, "We did it.", parsed.Sections["Decision"])
+	assert.NotContains(t, ParseExistingADR(existing).Sections, "Context")
+}
+
 func TestMerge(t *testing.T) {
 	adr := &model.ADR{
 		ID:           "adr-1",
@@ -131,6 +158,25 @@ Manual decision added.
 	assert.Contains(t, result, "backend/service.go:10")
 }
 
+func TestMerge_CustomFields(t *testing.T) {
+	adr := &model.ADR{
+		ID:           "adr-3",
+		Name:         "With Owner",
+		Status:       "accepted",
+		Date:         "2026-01-17",
+		CustomFields: map[string][]string{"owner": {"payments"}},
+	}
+	tmpl := "# {{.ID}}: {{.Name}}\n\n**Owner:** {{index .CustomFields \"owner\" | join \", \"}}\n\n## Context\n{{.Context | join \"\\n\\n\"}}\n"
+
+	existingContent := "# adr-3: With Owner\n\n**Owner:** platform\n\n## Context\nManual context.\n"
+
+	result, err := Merge(adr, existingContent, tmpl)
+
+	assert.NoError(t, err)
+	assert.Contains(t, result, "**Owner:** payments")
+	assert.Contains(t, result, "Manual context.")
+}
+
 func TestMerge_AllPlaceholders(t *testing.T) {
 	adr := &model.ADR{
 		ID:           "adr-3",
@@ -255,8 +301,19 @@ func TestIsPlaceholder(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := isPlaceholder(tt.content)
+			result := IsPlaceholder(tt.content)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+func TestSetStatus(t *testing.T) {
+	content := "# adr-1: Test\n\n**Status:** accepted\n**Date:** 2026-01-15\n"
+
+	updated, ok := SetStatus(content, "deprecated")
+	assert.True(t, ok)
+	assert.Contains(t, updated, "**Status:** deprecated\n**Date:** 2026-01-15")
+
+	_, ok = SetStatus("# No status here\n", "deprecated")
+	assert.False(t, ok)
+}
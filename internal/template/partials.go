@@ -0,0 +1,166 @@
+package template
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/weaby/adr-buddy/internal/lifecycle"
+)
+
+// extendsRe matches the directive a template starts with to extend a base
+// template: {{/* extends "base" */}}
+var extendsRe = regexp.MustCompile(`\A\s*\{\{-?\s*/\*\s*extends\s+"([^"]+)"\s*\*/\s*-?\}\}`)
+
+// compiled is a parsed template together with the partials it uses
+type compiled struct {
+	tmpl  *template.Template
+	entry string // Name of the template to execute
+}
+
+// execute runs the template against data, with statusBadge coloring
+// statuses as statuses does
+func (c *compiled) execute(w io.Writer, data any, statuses lifecycle.Lifecycle) error {
+	c.tmpl.Funcs(template.FuncMap{"statusBadge": func(status string) string { return statusBadgeIn(statuses, status) }})
+	return c.tmpl.ExecuteTemplate(w, c.entry, data)
+}
+
+// Extends returns the name of the base template tmplStr extends, if any
+func Extends(tmplStr string) (string, bool) {
+	match := extendsRe.FindStringSubmatch(tmplStr)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// newTemplate parses tmplStr with the template function library and the
+// partials it uses. A template that extends a base executes the base, with
+// the blocks it defines replacing the base's; its own text outside
+// {{define}} is ignored. Only the partials the template refers to, directly
+// or through other partials, are parsed, so one template's overrides never
+// leak into another.
+func newTemplate(tmplStr string, partials map[string]string) (*compiled, error) {
+	chain, err := extendsChain(tmplStr, partials)
+	if err != nil {
+		return nil, err
+	}
+
+	needed := make(map[string]bool)
+	for {
+		c, err := parseSet(tmplStr, chain, needed, partials)
+		if err != nil {
+			return nil, err
+		}
+
+		added := false
+		for _, name := range undefinedTemplates(c.tmpl) {
+			if _, ok := partials[name]; ok && !needed[name] {
+				needed[name] = true
+				added = true
+			}
+		}
+		if !added {
+			return c, nil
+		}
+	}
+}
+
+// extendsChain returns the base templates tmplStr extends, nearest first
+func extendsChain(tmplStr string, partials map[string]string) ([]string, error) {
+	var chain []string
+	seen := make(map[string]bool)
+
+	for base, ok := Extends(tmplStr); ok; base, ok = Extends(partials[base]) {
+		if seen[base] {
+			return nil, fmt.Errorf("template %q extends itself", base)
+		}
+		if _, exists := partials[base]; !exists {
+			return nil, fmt.Errorf("extends unknown template %q", base)
+		}
+		seen[base] = true
+		chain = append(chain, base)
+	}
+
+	return chain, nil
+}
+
+// parseSet parses the needed partials, then the base templates from the
+// outermost in, then tmplStr, so that later definitions override earlier ones
+func parseSet(tmplStr string, chain []string, needed map[string]bool, partials map[string]string) (*compiled, error) {
+	inChain := make(map[string]bool)
+	for _, base := range chain {
+		inChain[base] = true
+	}
+
+	var names []string
+	for name := range needed {
+		if !inChain[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for i := len(chain) - 1; i >= 0; i-- {
+		names = append(names, chain[i])
+	}
+
+	t := template.New("adr").Funcs(FuncMap())
+	for _, name := range names {
+		if _, err := t.New(name).Parse(partials[name]); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := t.Parse(tmplStr); err != nil {
+		return nil, err
+	}
+
+	c := &compiled{tmpl: t, entry: "adr"}
+	if len(chain) > 0 {
+		c.entry = chain[len(chain)-1]
+	}
+	return c, nil
+}
+
+// undefinedTemplates returns the names of templates referred to with
+// {{template}} that are not defined in the set
+func undefinedTemplates(t *template.Template) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.IfNode:
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.TemplateNode:
+			if t.Lookup(n.Name) == nil && !seen[n.Name] {
+				seen[n.Name] = true
+				names = append(names, n.Name)
+			}
+		}
+	}
+
+	for _, defined := range t.Templates() {
+		if defined.Tree != nil {
+			walk(defined.Tree.Root)
+		}
+	}
+	return names
+}
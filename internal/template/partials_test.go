@@ -0,0 +1,117 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+const baseTemplate = `{{template "header" .}}
+## Context
+{{block "context" .}}{{range .Context}}{{.}}
+{{end}}{{end}}
+## Decision
+{{block "decision" .}}{{range .Decision}}{{.}}
+{{end}}{{end}}
+{{template "footer" .}}`
+
+func testPartials() map[string]string {
+	return map[string]string{
+		"base":   baseTemplate,
+		"header": "# {{.ID}}: {{.Name}}\n",
+		"footer": "Maintained by the platform team.\n",
+	}
+}
+
+func TestRender_Partials(t *testing.T) {
+	adr := &model.ADR{ID: "adr-1", Name: "Test", Context: []string{"Ctx"}, Decision: []string{"Dec"}}
+	tmpl := `{{template "header" .}}
+## Decision
+{{range .Decision}}{{.}}{{end}}
+`
+
+	result, err := RenderWithOptions(adr, tmpl, Options{Partials: testPartials()})
+	require.NoError(t, err)
+	assert.Equal(t, "# adr-1: Test\n\n## Decision\nDec\n", result)
+}
+
+func TestRender_Extends(t *testing.T) {
+	adr := &model.ADR{ID: "adr-1", Name: "Test", Context: []string{"Ctx"}, Decision: []string{"Dec"}}
+	tmpl := `{{/* extends "base" */}}
+{{define "decision"}}We decided: {{join ", " .Decision}}
+{{end}}`
+
+	result, err := RenderWithOptions(adr, tmpl, Options{Partials: testPartials()})
+	require.NoError(t, err)
+	assert.Equal(t, "# adr-1: Test\n\n## Context\nCtx\n\n## Decision\nWe decided: Dec\n\nMaintained by the platform team.\n", result)
+}
+
+func TestRender_ExtendsChain(t *testing.T) {
+	partials := testPartials()
+	partials["security"] = "{{/* extends \"base\" */}}{{define \"context\"}}Threats: {{join \", \" .Context}}\n{{end}}"
+	adr := &model.ADR{ID: "adr-1", Name: "Test", Context: []string{"Ctx"}, Decision: []string{"Dec"}}
+
+	result, err := RenderWithOptions(adr, `{{/* extends "security" */}}{{define "decision"}}Chosen.
+{{end}}`, Options{Partials: partials})
+	require.NoError(t, err)
+	assert.Contains(t, result, "Threats: Ctx\n")
+	assert.Contains(t, result, "## Decision\nChosen.\n")
+}
+
+func TestRender_OverridesDoNotLeak(t *testing.T) {
+	partials := testPartials()
+	// Not referenced by the template, so its override must not apply
+	partials["other"] = `{{define "decision"}}Leaked{{end}}`
+	adr := &model.ADR{ID: "adr-1", Name: "Test", Decision: []string{"Dec"}}
+
+	result, err := RenderWithOptions(adr, `{{/* extends "base" */}}`, Options{Partials: partials})
+	require.NoError(t, err)
+	assert.NotContains(t, result, "Leaked")
+	assert.Contains(t, result, "Dec\n")
+}
+
+func TestRender_ExtendsErrors(t *testing.T) {
+	adr := &model.ADR{ID: "adr-1", Name: "Test"}
+
+	_, err := RenderWithOptions(adr, `{{/* extends "missing" */}}`, Options{Partials: testPartials()})
+	assert.ErrorContains(t, err, `extends unknown template "missing"`)
+
+	loop := map[string]string{"a": `{{/* extends "b" */}}`, "b": `{{/* extends "a" */}}`}
+	_, err = RenderWithOptions(adr, `{{/* extends "a" */}}`, Options{Partials: loop})
+	assert.ErrorContains(t, err, "extends itself")
+}
+
+func TestMerge_Extends(t *testing.T) {
+	opts := Options{Partials: testPartials()}
+	tmpl := `{{/* extends "base" */}}{{define "decision"}}{{if .Decision}}{{range .Decision}}{{.}}
+{{end}}{{else}}<!-- TODO: decide -->
+{{end}}{{end}}`
+	existing := "# adr-1: Test\n\n## Context\nCtx\n\n## Decision\nWritten by hand.\n\nMaintained by the platform team.\n"
+	adr := &model.ADR{ID: "adr-1", Name: "Test", Context: []string{"Ctx"}}
+
+	result, err := MergeWithOptions(adr, existing, tmpl, opts)
+	require.NoError(t, err)
+	assert.Contains(t, result, "## Decision\nWritten by hand.\n")
+}
+
+func TestValidate_PartialError(t *testing.T) {
+	partials := map[string]string{"header": "# {{.ID}}\n{{nope}}\n"}
+
+	problems := Validate(`{{template "header" .}}`, Options{Partials: partials})
+	require.Len(t, problems, 1)
+	assert.Equal(t, "header", problems[0].Template)
+	assert.Equal(t, 2, problems[0].Line)
+	assert.Equal(t, 3, problems[0].Column)
+}
+
+func TestExtends(t *testing.T) {
+	base, ok := Extends("  {{- /* extends \"base\" */ -}}\nrest")
+	assert.True(t, ok)
+	assert.Equal(t, "base", base)
+
+	_, ok = Extends("# {{.ID}}\n{{/* extends \"base\" */}}")
+	assert.False(t, ok)
+}
@@ -0,0 +1,282 @@
+package template
+
+import (
+	"sort"
+	"strings"
+)
+
+// PresetPrefix marks a built-in template in the template setting,
+// e.g. "preset:madr"
+const PresetPrefix = "preset:"
+
+// Preset is a built-in ADR format
+type Preset struct {
+	Name        string
+	Description string
+	Template    string
+}
+
+// presets are the built-in formats, keyed by name
+var presets = map[string]Preset{
+	"default": {
+		Name:        "default",
+		Description: "ADR Buddy's own format with context, decision, alternatives and consequences",
+		Template:    DefaultTemplate(),
+	},
+	"madr": {
+		Name:        "madr",
+		Description: "Markdown Architectural Decision Records (MADR) 3.0",
+		Template:    madrTemplate,
+	},
+	"nygard": {
+		Name:        "nygard",
+		Description: "Michael Nygard's original ADR format: status, context, decision, consequences",
+		Template:    nygardTemplate,
+	},
+	"y-statement": {
+		Name:        "y-statement",
+		Description: "Y-statement: in the context of, we decided for, and neglected, to achieve/accepting",
+		Template:    yStatementTemplate,
+	},
+	"business-case": {
+		Name:        "business-case",
+		Description: "Business case with background, recommendation, options, and costs, benefits and risks",
+		Template:    businessCaseTemplate,
+	},
+}
+
+// Presets returns the built-in formats sorted by name
+func Presets() []Preset {
+	list := make([]Preset, 0, len(presets))
+	for _, p := range presets {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// LookupPreset returns the built-in format with the given name
+func LookupPreset(name string) (Preset, bool) {
+	p, ok := presets[strings.ToLower(strings.TrimSpace(name))]
+	return p, ok
+}
+
+// PresetNames returns the names of the built-in formats, sorted
+func PresetNames() []string {
+	var names []string
+	for _, p := range Presets() {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// PresetName returns the preset named by a template setting such as
+// "preset:madr", and whether the setting names a preset at all
+func PresetName(setting string) (string, bool) {
+	if !strings.HasPrefix(setting, PresetPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(setting, PresetPrefix), true
+}
+
+const madrTemplate = `# {{.ID}}: {{.Name}}
+{{if not .FrontMatter}}
+**Status:** {{.Status}}
+**Date:** {{.Date}}
+{{if .Category}}**Category:** {{.Category}}{{end}}
+{{end}}
+## Context and Problem Statement
+{{if .Context}}
+{{range .Context}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: Describe the context and problem statement, e.g., in free form using two to three sentences or in the form of an illustrative story. -->
+{{end}}
+
+## Considered Options
+{{if .Alternatives}}
+{{range .Alternatives}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: List the options that were considered. -->
+{{end}}
+
+## Decision Outcome
+{{if .Decision}}
+{{range .Decision}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: Chosen option, because justification. -->
+{{end}}
+
+### Consequences
+{{if .Consequences}}
+{{range .Consequences}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: Good, because ... Bad, because ... -->
+{{end}}
+
+## More Information
+
+Code locations:
+{{range .Locations}}
+- {{.File}}:{{.Line}}
+{{end}}
+`
+
+const nygardTemplate = `# {{.ID}}: {{.Name}}
+{{if not .FrontMatter}}
+**Date:** {{.Date}}
+{{end}}
+## Status
+
+{{.Status}}
+
+## Context
+{{if .Context}}
+{{range .Context}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: What is the issue that we're seeing that is motivating this decision or change? -->
+{{end}}
+
+## Decision
+{{if .Decision}}
+{{range .Decision}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: What is the change that we're proposing and/or doing? -->
+{{end}}
+
+## Consequences
+{{if .Consequences}}
+{{range .Consequences}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: What becomes easier or more difficult to do because of this change? -->
+{{end}}
+
+## Code Locations
+{{range .Locations}}
+- {{.File}}:{{.Line}}
+{{end}}
+`
+
+const yStatementTemplate = `# {{.ID}}: {{.Name}}
+{{if not .FrontMatter}}
+**Status:** {{.Status}}
+**Date:** {{.Date}}
+{{if .Category}}**Category:** {{.Category}}{{end}}
+{{end}}
+## In the Context Of
+{{if .Context}}
+{{range .Context}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: In the context of <use case or component>, facing <concern> -->
+{{end}}
+
+## We Decided For
+{{if .Decision}}
+{{range .Decision}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: We decided for <option> -->
+{{end}}
+
+## And Neglected
+{{if .Alternatives}}
+{{range .Alternatives}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: And neglected <other options> -->
+{{end}}
+
+## To Achieve, Accepting
+{{if .Consequences}}
+{{range .Consequences}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: To achieve <quality or benefit>, accepting <downside> -->
+{{end}}
+
+## Code Locations
+{{range .Locations}}
+- {{.File}}:{{.Line}}
+{{end}}
+`
+
+const businessCaseTemplate = `# {{.ID}}: {{.Name}}
+{{if not .FrontMatter}}
+**Status:** {{.Status}}
+**Date:** {{.Date}}
+{{if .Category}}**Category:** {{.Category}}{{end}}
+{{end}}
+## Background
+{{if .Context}}
+{{range .Context}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: What business need or opportunity prompts this decision? Who are the stakeholders? -->
+{{end}}
+
+## Recommendation
+{{if .Decision}}
+{{range .Decision}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: What do we recommend, and why is it the best value? -->
+{{end}}
+
+## Options Considered
+{{if .Alternatives}}
+{{range .Alternatives}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: What options were evaluated, including doing nothing? -->
+{{end}}
+
+## Costs, Benefits and Risks
+{{if .Consequences}}
+{{range .Consequences}}
+{{.}}
+
+{{end}}
+{{else}}
+<!-- TODO: What will this cost, what do we gain, and what could go wrong? -->
+{{end}}
+
+## Code Locations
+{{range .Locations}}
+- {{.File}}:{{.Line}}
+{{end}}
+`
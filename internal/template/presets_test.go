@@ -0,0 +1,135 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+// handWritten renders an ADR with the preset and replaces every section
+// placeholder with hand-written content
+func handWritten(t *testing.T, tmpl string) string {
+	t.Helper()
+
+	adr := &model.ADR{
+		ID:        "adr-1",
+		Name:      "Use Pino",
+		Status:    "proposed",
+		Date:      "2026-01-10",
+		Locations: []model.SourceLocation{{File: "src/logger.js", Line: 3}},
+	}
+	content, err := Render(adr, tmpl)
+	require.NoError(t, err)
+
+	headings, err := sectionHeadings(tmpl, Options{})
+	require.NoError(t, err)
+	parsed := parseExistingADR(content, headings)
+
+	for field := range headings {
+		placeholder := parsed.Sections[field]
+		require.True(t, IsPlaceholder(placeholder), "section %s should start as a placeholder", field)
+		content = strings.Replace(content, placeholder, "Hand-written "+field+".\n\n#### Details\n\nMore about "+field+".", 1)
+	}
+	return content
+}
+
+func TestPresets(t *testing.T) {
+	names := PresetNames()
+	assert.Equal(t, []string{"business-case", "default", "madr", "nygard", "y-statement"}, names)
+
+	for _, preset := range Presets() {
+		headings, err := sectionHeadings(preset.Template, Options{})
+		require.NoError(t, err, preset.Name)
+		assert.Contains(t, headings, "Context", preset.Name)
+		assert.Contains(t, headings, "Decision", preset.Name)
+		assert.Contains(t, headings, "Consequences", preset.Name)
+	}
+
+	madr, ok := LookupPreset("MADR")
+	require.True(t, ok)
+	assert.Equal(t, "madr", madr.Name)
+
+	_, ok = LookupPreset("unknown")
+	assert.False(t, ok)
+}
+
+func TestPresetName(t *testing.T) {
+	name, ok := PresetName("preset:madr")
+	assert.True(t, ok)
+	assert.Equal(t, "madr", name)
+
+	_, ok = PresetName(".adr-buddy/template.md")
+	assert.False(t, ok)
+}
+
+func TestMerge_PresetsRoundTrip(t *testing.T) {
+	adr := &model.ADR{
+		ID:        "adr-1",
+		Name:      "Use Pino",
+		Status:    "accepted",
+		Date:      "2026-01-17",
+		Locations: []model.SourceLocation{{File: "src/logger.js", Line: 5}},
+	}
+
+	for _, from := range Presets() {
+		existing := handWritten(t, from.Template)
+		fromHeadings, err := sectionHeadings(from.Template, Options{})
+		require.NoError(t, err)
+
+		for _, to := range Presets() {
+			t.Run(from.Name+"->"+to.Name, func(t *testing.T) {
+				result, err := Merge(adr, existing, to.Template)
+				require.NoError(t, err)
+
+				toHeadings, err := sectionHeadings(to.Template, Options{})
+				require.NoError(t, err)
+
+				for field := range fromHeadings {
+					if _, rendered := toHeadings[field]; !rendered {
+						continue
+					}
+					assert.Contains(t, result, "Hand-written "+field+".")
+					assert.Contains(t, result, "More about "+field+".")
+				}
+				assert.NotContains(t, result, "src/logger.js:3")
+				assert.Contains(t, result, "src/logger.js:5")
+				assert.Contains(t, result, "2026-01-10")
+				assert.Contains(t, result, "accepted")
+
+				// Merging the result again changes nothing
+				again, err := Merge(adr, result, to.Template)
+				require.NoError(t, err)
+				assert.Equal(t, result, again)
+			})
+		}
+	}
+}
+
+func TestParseExistingADR_NygardStatus(t *testing.T) {
+	existing := "# adr-1: Test\n\n**Date:** 2026-01-15\n\n## Status\n\nAccepted\n\n## Context\nSome context.\n"
+
+	parsed := ParseExistingADR(existing)
+	assert.Equal(t, "Accepted", parsed.Frontmatter["Status"])
+	assert.Equal(t, "2026-01-15", parsed.Frontmatter["Date"])
+	assert.Equal(t, "Some context.", parsed.Sections["Context"])
+
+	updated, ok := SetStatus(existing, "deprecated")
+	assert.True(t, ok)
+	assert.Contains(t, updated, "## Status\n\ndeprecated\n\n## Context")
+}
+
+func TestParseExistingADR_PlainDate(t *testing.T) {
+	parsed := ParseExistingADR("# 1. Record decisions\n\nDate: 2016-02-12\n")
+	assert.Equal(t, "2016-02-12", parsed.Frontmatter["Date"])
+}
+
+func TestParseExistingADR_Subheadings(t *testing.T) {
+	existing := "# adr-1: Test\n\n## Context\nIntro.\n\n### Forces\nSpeed.\n\n```\n## not a heading\n```\n\n## Decision\nGo.\n"
+
+	parsed := ParseExistingADR(existing)
+	assert.Equal(t, "Intro.\n\n### Forces\nSpeed.\n\n```\n## not a heading\n```", parsed.Sections["Context"])
+	assert.Equal(t, "Go.", parsed.Sections["Decision"])
+}
@@ -2,22 +2,71 @@ package template
 
 import (
 	"bytes"
-	"text/template"
+	"strings"
 
+	"github.com/weaby/adr-buddy/internal/lifecycle"
 	"github.com/weaby/adr-buddy/internal/model"
 )
 
+// Options controls how ADRs are rendered
+type Options struct {
+	FrontMatter bool                 // Emit metadata as YAML front matter before the body
+	Partials    map[string]string    // Named templates the template can include or extend, by name
+	Locale      string               // Language of headings, placeholders and labels; English if empty
+	Lifecycle   *lifecycle.Lifecycle // Statuses and transitions templates can refer to; the default ones if nil
+}
+
+// templateData is what templates execute against. The ADR's fields are
+// promoted, so templates refer to them directly as {{.ID}}.
+type templateData struct {
+	*model.ADR
+	FrontMatter bool                // Metadata is in front matter; templates may omit it from the body
+	Locale      *Locale             // Localised headings, placeholders and labels
+	Lifecycle   lifecycle.Lifecycle // Statuses, e.g. {{.Lifecycle.Next .Status}} for those the ADR may change to
+}
+
+// newTemplateData returns the data to execute a template against for an ADR
+func newTemplateData(adr *model.ADR, opts Options) templateData {
+	return templateData{ADR: adr, FrontMatter: opts.FrontMatter, Locale: localeFor(opts.Locale), Lifecycle: opts.lifecycle()}
+}
+
+// lifecycle returns the lifecycle of the options, or the default one
+func (o Options) lifecycle() lifecycle.Lifecycle {
+	if o.Lifecycle != nil {
+		return *o.Lifecycle
+	}
+	return lifecycle.Default()
+}
+
 // Render renders an ADR using the provided template
 func Render(adr *model.ADR, tmplStr string) (string, error) {
-	tmpl, err := template.New("adr").Parse(tmplStr)
+	return RenderWithOptions(adr, tmplStr, Options{})
+}
+
+// RenderWithOptions renders an ADR using the provided template and options
+func RenderWithOptions(adr *model.ADR, tmplStr string, opts Options) (string, error) {
+	tmpl, err := newTemplate(tmplStr, opts.Partials)
 	if err != nil {
 		return "", err
 	}
 
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, adr); err != nil {
+	if err := tmpl.execute(&buf, newTemplateData(adr, opts), opts.lifecycle()); err != nil {
 		return "", err
 	}
 
-	return buf.String(), nil
+	return withFrontMatter(adr, buf.String(), opts)
+}
+
+// withFrontMatter prepends the ADR's front matter to body if enabled
+func withFrontMatter(adr *model.ADR, body string, opts Options) (string, error) {
+	if !opts.FrontMatter {
+		return body, nil
+	}
+
+	header, err := renderFrontMatter(NewMetadata(adr))
+	if err != nil {
+		return "", err
+	}
+	return header + strings.TrimLeft(body, "\n"), nil
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/weaby/adr-buddy/internal/lifecycle"
 	"github.com/weaby/adr-buddy/internal/model"
 )
 
@@ -54,3 +55,21 @@ func TestRender_WithPlaceholders(t *testing.T) {
 	assert.Contains(t, result, "<!-- TODO: Document the decision")
 	assert.Contains(t, result, "<!-- TODO: What are the positive/negative outcomes")
 }
+
+func TestRenderWithOptions_Lifecycle(t *testing.T) {
+	adr := &model.ADR{ID: "adr-3", Name: "Use gRPC", Status: "in-review"}
+	tmpl := `{{.Status}} -> {{join " | " (.Lifecycle.Next .Status)}}{{if .Lifecycle.Final "retired"}} (retired is final){{end}}`
+
+	result, err := RenderWithOptions(adr, tmpl, Options{Lifecycle: &lifecycle.Lifecycle{
+		Statuses:    []string{"proposed", "in-review", "accepted", "retired"},
+		Transitions: map[string][]string{"proposed": {"in-review"}, "in-review": {"accepted", "proposed"}, "accepted": {"retired"}},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, "in-review -> accepted | proposed (retired is final)", result)
+
+	// Without a lifecycle, the default statuses apply
+	adr.Status = "accepted"
+	result, err = RenderWithOptions(adr, tmpl, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "accepted -> proposed | rejected | deprecated | superseded", result)
+}
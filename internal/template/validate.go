@@ -0,0 +1,198 @@
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/weaby/adr-buddy/internal/model"
+)
+
+// Severities of template problems
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Problem is an issue found while validating a template
+type Problem struct {
+	Template string // Partial the problem is in, empty for the template itself
+	Line     int    // 1-based, 0 if unknown
+	Column   int    // 1-based, 0 if unknown
+	Message  string
+	Severity string
+}
+
+// Format prefixes the message with the template name and position,
+// e.g. ".adr-buddy/template.md:3:5: function "nope" not defined"
+func (p Problem) Format(name string) string {
+	switch {
+	case p.Line > 0 && p.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", name, p.Line, p.Column, p.Message)
+	case p.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", name, p.Line, p.Message)
+	default:
+		return fmt.Sprintf("%s: %s", name, p.Message)
+	}
+}
+
+// templateErrRe splits a text/template error into position and message:
+// "template: adr:3:5: executing ..." or "template: header:3: unexpected ..."
+var templateErrRe = regexp.MustCompile(`^template: ([^:]*):(\d+)(?::(\d+))?: (?s)(.*)$`)
+
+// quotedRe finds the first quoted token in an error message
+var quotedRe = regexp.MustCompile(`"([^"]+)"`)
+
+// SyntheticADR returns an ADR with every field populated, for trying out templates
+func SyntheticADR() *model.ADR {
+	return &model.ADR{
+		ID:           "adr-042",
+		Name:         "Synthetic decision",
+		Status:       "accepted",
+		Category:     "platform/api",
+		Date:         "2026-01-17",
+		Context:      []string{"Synthetic context, first paragraph.", "Synthetic context, second paragraph."},
+		Decision:     []string{"Synthetic decision, first paragraph.", "Synthetic decision, second paragraph."},
+		Alternatives: []string{"Synthetic alternatives, first paragraph.", "Synthetic alternatives, second paragraph."},
+		Consequences: []string{"Synthetic consequences, first paragraph.", "Synthetic consequences, second paragraph."},
+		Tags:         []string{"alpha", "beta"},
+		Supersedes:   []string{"adr-041"},
+		Relates:      []string{"adr-040"},
+		Locations: []model.SourceLocation{
+			{File: "src/api/server.go", Line: 12},
+			{File: "src/api/client.go", Line: 40},
+		},
+	}
+}
+
+// Validate checks that a template parses, renders an ADR with every field
+// set, and renders it in a form sync can read back when merging. Problems
+// with severity error mean the template can't be used.
+func Validate(tmplStr string, opts Options) []Problem {
+	if _, err := newTemplate(tmplStr, opts.Partials); err != nil {
+		return []Problem{templateProblem(tmplStr, opts.Partials, err)}
+	}
+
+	adr := SyntheticADR()
+	rendered, err := RenderWithOptions(adr, tmplStr, opts)
+	if err != nil {
+		return []Problem{templateProblem(tmplStr, opts.Partials, err)}
+	}
+
+	var problems []Problem
+	warn := func(format string, args ...any) {
+		problems = append(problems, Problem{Message: fmt.Sprintf(format, args...), Severity: SeverityWarning})
+	}
+
+	headings, _ := sectionHeadings(tmplStr, opts)
+	parsed := parseExistingADR(rendered, headings)
+	lower := strings.ToLower(rendered)
+
+	if parsed.Frontmatter["ID"] != adr.ID {
+		warn("does not render a %q heading or front matter, so sync can't recognise existing ADR files when they become orphaned or move", "# {{.ID}}: {{.Name}}")
+	}
+	if strings.Contains(rendered, adr.Date) && parsed.Frontmatter["Date"] != adr.Date {
+		warn("renders the date in a form sync can't read back, so it is reset on every sync; use %q", "**Date:** {{.Date}}")
+	}
+
+	byHeading := make(map[string]string)
+	for _, field := range proseFields {
+		paragraphs := fieldValues(adr, field)
+		if !strings.Contains(lower, strings.ToLower(paragraphs[0])) {
+			warn("does not render .%s; annotation content for it will not appear", field)
+			continue
+		}
+
+		heading, ok := headings[field]
+		if !ok {
+			problems = append(problems, Problem{
+				Message:  fmt.Sprintf(".%s is not rendered under a \"##\" heading, so hand-written content in it would be lost on sync", field),
+				Severity: SeverityError,
+			})
+			continue
+		}
+
+		if other, shared := byHeading[strings.ToLower(heading)]; shared {
+			warn(".%s and .%s share the heading %q; hand-written content there is read back as .%s only", other, field, heading, other)
+			continue
+		}
+		byHeading[strings.ToLower(heading)] = field
+
+		section := strings.ToLower(parsed.Sections[field])
+		for _, p := range paragraphs {
+			if !strings.Contains(section, strings.ToLower(p)) {
+				problems = append(problems, Problem{
+					Message:  fmt.Sprintf("sync can't read .%s back from the %q section, so hand-written content in it would be lost on sync", field, heading),
+					Severity: SeverityError,
+				})
+				break
+			}
+		}
+	}
+
+	return problems
+}
+
+// HasErrors reports whether any problem is an error
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldValues returns the paragraphs of a prose field
+func fieldValues(adr *model.ADR, field string) []string {
+	switch field {
+	case "Context":
+		return adr.Context
+	case "Decision":
+		return adr.Decision
+	case "Alternatives":
+		return adr.Alternatives
+	default:
+		return adr.Consequences
+	}
+}
+
+// templateProblem converts a text/template error into a positioned problem,
+// in the template itself or in one of its partials. Parse errors only carry
+// a line; the column is found from the first quoted token in the message
+// where possible.
+func templateProblem(tmplStr string, partials map[string]string, err error) Problem {
+	problem := Problem{Message: err.Error(), Severity: SeverityError}
+
+	match := templateErrRe.FindStringSubmatch(err.Error())
+	if match == nil {
+		return problem
+	}
+
+	if source, ok := partials[match[1]]; ok && match[1] != "adr" {
+		problem.Template = match[1]
+		tmplStr = source
+	}
+	problem.Line, _ = strconv.Atoi(match[2])
+	problem.Message = strings.NewReplacer("template.templateData", "ADR", "*model.ADR", "ADR").Replace(match[4])
+
+	// Execution errors carry a 0-based byte offset within the line
+	if match[3] != "" {
+		offset, _ := strconv.Atoi(match[3])
+		problem.Column = offset + 1
+	}
+
+	if problem.Column == 0 {
+		lines := strings.Split(tmplStr, "\n")
+		if problem.Line >= 1 && problem.Line <= len(lines) {
+			if token := quotedRe.FindStringSubmatch(problem.Message); token != nil {
+				if at := strings.Index(lines[problem.Line-1], token[1]); at >= 0 {
+					problem.Column = at + 1
+				}
+			}
+		}
+	}
+
+	return problem
+}
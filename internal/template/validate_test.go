@@ -0,0 +1,89 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_Presets(t *testing.T) {
+	for _, preset := range Presets() {
+		for _, frontMatter := range []bool{false, true} {
+			problems := Validate(preset.Template, Options{FrontMatter: frontMatter})
+			assert.False(t, HasErrors(problems), "%s: %v", preset.Name, problems)
+		}
+	}
+}
+
+func TestValidate_ParseError(t *testing.T) {
+	problems := Validate("# {{.ID}}: {{.Name}}\n\n  {{nope .Status}}\n", Options{})
+
+	require.Len(t, problems, 1)
+	assert.Equal(t, Problem{Line: 3, Column: 5, Message: `function "nope" not defined`, Severity: SeverityError}, problems[0])
+	assert.Equal(t, `t.md:3:5: function "nope" not defined`, problems[0].Format("t.md"))
+}
+
+func TestValidate_UnclosedAction(t *testing.T) {
+	problems := Validate("# {{.ID}}\n{{if .Context}}\n", Options{})
+
+	require.Len(t, problems, 1)
+	assert.Equal(t, SeverityError, problems[0].Severity)
+	assert.Equal(t, 3, problems[0].Line)
+	assert.Contains(t, problems[0].Message, "unexpected EOF")
+}
+
+func TestValidate_ExecutionError(t *testing.T) {
+	problems := Validate("# {{.ID}}: {{.Name}}\nab {{.Missing}}\n", Options{})
+
+	require.Len(t, problems, 1)
+	assert.Equal(t, 2, problems[0].Line)
+	assert.Equal(t, 6, problems[0].Column)
+	assert.Contains(t, problems[0].Message, "can't evaluate field Missing in type ADR")
+}
+
+func TestValidate_SectionsNotReadable(t *testing.T) {
+	// Context is rendered, but not under a heading the merger can find
+	tmpl := `# {{.ID}}: {{.Name}}
+
+**Context:** {{join " " .Context}}
+
+## Decision
+{{range .Decision}}{{.}}
+{{end}}
+## Alternatives
+{{range .Alternatives}}{{.}}
+{{end}}
+## Consequences
+{{range .Consequences}}{{.}}
+{{end}}
+`
+	problems := Validate(tmpl, Options{})
+	require.True(t, HasErrors(problems))
+	assert.Contains(t, problems[0].Message, ".Context is not rendered under a")
+}
+
+func TestValidate_Warnings(t *testing.T) {
+	tmpl := `Decision {{.ID}} from _{{.Date}}_
+
+## Context
+{{range .Context}}{{.}}
+{{end}}
+{{range .Decision}}{{.}}
+{{end}}
+`
+	problems := Validate(tmpl, Options{})
+	assert.False(t, HasErrors(problems))
+
+	var messages []string
+	for _, p := range problems {
+		assert.Equal(t, SeverityWarning, p.Severity)
+		messages = append(messages, p.Message)
+	}
+	assert.Len(t, messages, 5)
+	assert.Contains(t, messages[0], "heading or front matter")
+	assert.Contains(t, messages[1], "date in a form sync can't read back")
+	assert.Contains(t, messages[2], ".Context and .Decision share the heading \"Context\"")
+	assert.Contains(t, messages[3], "does not render .Alternatives")
+	assert.Contains(t, messages[4], "does not render .Consequences")
+}
@@ -0,0 +1,267 @@
+package txn
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// opKind identifies a staged file operation
+type opKind int
+
+const (
+	opWrite opKind = iota
+	opRemove
+	opRename
+)
+
+// op is a single staged file operation
+type op struct {
+	kind    opKind
+	path    string
+	target  string // Destination for renames
+	content []byte
+	perm    fs.FileMode
+}
+
+// undo restores the state a single applied operation replaced
+type undo func() error
+
+// Transaction stages file writes, removals and renames and applies them as a
+// unit: if any operation fails, every operation already applied is reverted.
+// Each write goes to a temporary file in the target directory that is then
+// renamed into place, so a crash never leaves a truncated file behind.
+type Transaction struct {
+	ops []op
+}
+
+// New returns an empty transaction
+func New() *Transaction {
+	return &Transaction{}
+}
+
+// Write stages writing content to path, creating parent directories as needed
+func (t *Transaction) Write(path string, content []byte, perm fs.FileMode) {
+	t.ops = append(t.ops, op{kind: opWrite, path: path, content: content, perm: perm})
+}
+
+// Remove stages deleting the file at path
+func (t *Transaction) Remove(path string) {
+	t.ops = append(t.ops, op{kind: opRemove, path: path})
+}
+
+// Rename stages moving the file at from to to, creating parent directories as needed
+func (t *Transaction) Rename(from, to string) {
+	t.ops = append(t.ops, op{kind: opRename, path: from, target: to})
+}
+
+// Len returns the number of staged operations
+func (t *Transaction) Len() int {
+	return len(t.ops)
+}
+
+// Commit applies all staged operations in order. On failure it rolls back
+// the operations already applied and returns the original error, joined with
+// any error encountered while rolling back.
+func (t *Transaction) Commit() error {
+	var undos []undo
+
+	for _, o := range t.ops {
+		u, err := apply(o)
+		if err != nil {
+			if rbErr := rollback(undos); rbErr != nil {
+				return errors.Join(err, fmt.Errorf("rollback failed: %w", rbErr))
+			}
+			return err
+		}
+		undos = append(undos, u)
+	}
+
+	t.ops = nil
+	return nil
+}
+
+// rollback runs undos in reverse order, continuing past failures
+func rollback(undos []undo) error {
+	var errs []error
+	for i := len(undos) - 1; i >= 0; i-- {
+		if err := undos[i](); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// apply performs a single operation and returns how to revert it
+func apply(o op) (undo, error) {
+	switch o.kind {
+	case opWrite:
+		return applyWrite(o)
+	case opRemove:
+		return applyRemove(o)
+	case opRename:
+		return applyRename(o)
+	}
+	return nil, fmt.Errorf("unknown operation for %s", o.path)
+}
+
+func applyWrite(o op) (undo, error) {
+	undoDirs, err := mkdirAll(filepath.Dir(o.path))
+	if err != nil {
+		return nil, err
+	}
+
+	previous, info, err := readExisting(o.path)
+	if err != nil {
+		undoDirs()
+		return nil, err
+	}
+
+	if err := WriteFile(o.path, o.content, o.perm); err != nil {
+		undoDirs()
+		return nil, err
+	}
+
+	return func() error {
+		if info == nil {
+			if err := os.Remove(o.path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return undoDirs()
+		}
+		return WriteFile(o.path, previous, info.Mode().Perm())
+	}, nil
+}
+
+func applyRemove(o op) (undo, error) {
+	previous, info, err := readExisting(o.path)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("failed to remove %s: %w", o.path, fs.ErrNotExist)
+	}
+
+	if err := os.Remove(o.path); err != nil {
+		return nil, fmt.Errorf("failed to remove %s: %w", o.path, err)
+	}
+
+	return func() error {
+		return WriteFile(o.path, previous, info.Mode().Perm())
+	}, nil
+}
+
+func applyRename(o op) (undo, error) {
+	if _, err := os.Stat(o.target); err == nil {
+		return nil, fmt.Errorf("failed to move %s: %s already exists", o.path, o.target)
+	}
+
+	undoDirs, err := mkdirAll(filepath.Dir(o.target))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(o.path, o.target); err != nil {
+		undoDirs()
+		return nil, fmt.Errorf("failed to move %s: %w", o.path, err)
+	}
+
+	return func() error {
+		if err := os.Rename(o.target, o.path); err != nil {
+			return err
+		}
+		return undoDirs()
+	}, nil
+}
+
+// readExisting returns the content and info of path, or nil info if it
+// does not exist
+func readExisting(path string) ([]byte, fs.FileInfo, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		return nil, nil, fmt.Errorf("%s is a directory", path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, info, nil
+}
+
+// mkdirAll creates dir and any missing parents, returning a function that
+// removes exactly the directories it created
+func mkdirAll(dir string) (func() error, error) {
+	var created []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		}
+		created = append(created, d)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	return func() error {
+		// Deepest first; created is already ordered that way
+		for _, d := range created {
+			if err := os.Remove(d); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+// WriteFile atomically replaces path with content by writing a temporary
+// file in the same directory, syncing it and renaming it over path
+func WriteFile(path string, content []byte, perm fs.FileMode) error {
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	tmpPath := tmp.Name()
+
+	// Remove the temporary file on any failure below
+	ok := false
+	defer func() {
+		if !ok {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	ok = true
+	return nil
+}
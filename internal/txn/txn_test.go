@@ -0,0 +1,102 @@
+package txn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommit(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	existing := filepath.Join(tmpDir, "existing.md")
+	removed := filepath.Join(tmpDir, "removed.md")
+	renamed := filepath.Join(tmpDir, "renamed.md")
+	require.NoError(t, os.WriteFile(existing, []byte("old"), 0644))
+	require.NoError(t, os.WriteFile(removed, []byte("bye"), 0644))
+	require.NoError(t, os.WriteFile(renamed, []byte("moving"), 0644))
+
+	tx := New()
+	tx.Write(existing, []byte("new"), 0644)
+	tx.Write(filepath.Join(tmpDir, "nested", "dir", "created.md"), []byte("hello"), 0644)
+	tx.Remove(removed)
+	tx.Rename(renamed, filepath.Join(tmpDir, "archive", "renamed.md"))
+	assert.Equal(t, 4, tx.Len())
+
+	require.NoError(t, tx.Commit())
+
+	content, err := os.ReadFile(existing)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(content))
+
+	content, err = os.ReadFile(filepath.Join(tmpDir, "nested", "dir", "created.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+
+	assert.NoFileExists(t, removed)
+	assert.NoFileExists(t, renamed)
+	assert.FileExists(t, filepath.Join(tmpDir, "archive", "renamed.md"))
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(tmpDir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), ".tmp-")
+	}
+}
+
+func TestCommit_RollsBackOnFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	existing := filepath.Join(tmpDir, "existing.md")
+	removed := filepath.Join(tmpDir, "removed.md")
+	renamed := filepath.Join(tmpDir, "renamed.md")
+	require.NoError(t, os.WriteFile(existing, []byte("old"), 0644))
+	require.NoError(t, os.WriteFile(removed, []byte("keep me"), 0644))
+	require.NoError(t, os.WriteFile(renamed, []byte("stay"), 0644))
+
+	// A regular file where a directory is needed makes the last write fail
+	blocker := filepath.Join(tmpDir, "blocker")
+	require.NoError(t, os.WriteFile(blocker, []byte("x"), 0644))
+
+	tx := New()
+	tx.Write(existing, []byte("new"), 0644)
+	tx.Write(filepath.Join(tmpDir, "created", "file.md"), []byte("hello"), 0644)
+	tx.Remove(removed)
+	tx.Rename(renamed, filepath.Join(tmpDir, "archive", "renamed.md"))
+	tx.Write(filepath.Join(blocker, "fail.md"), []byte("boom"), 0644)
+
+	err := tx.Commit()
+	require.Error(t, err)
+
+	content, err := os.ReadFile(existing)
+	require.NoError(t, err)
+	assert.Equal(t, "old", string(content))
+
+	content, err = os.ReadFile(removed)
+	require.NoError(t, err)
+	assert.Equal(t, "keep me", string(content))
+
+	assert.FileExists(t, renamed)
+	assert.NoDirExists(t, filepath.Join(tmpDir, "created"))
+	assert.NoDirExists(t, filepath.Join(tmpDir, "archive"))
+}
+
+func TestWriteFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "file.md")
+
+	require.NoError(t, WriteFile(path, []byte("first"), 0644))
+	require.NoError(t, WriteFile(path, []byte("second"), 0600))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
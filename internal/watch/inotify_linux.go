@@ -0,0 +1,189 @@
+//go:build linux
+
+package watch
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// inotifyMask selects the events that indicate a file's content or presence changed
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_CREATE | syscall.IN_DELETE |
+	syscall.IN_MODIFY | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_DELETE_SELF
+
+// inotifyWatcher watches directory trees with Linux inotify. inotify is not
+// recursive, so every directory gets its own watch and new directories are
+// added as they appear.
+type inotifyWatcher struct {
+	opts   Options
+	fd     int
+	file   *os.File
+	events chan string
+	done   chan struct{}
+	once   sync.Once
+
+	mu      sync.Mutex
+	watches map[int32]string // Watch descriptor -> directory
+}
+
+func newNative(opts Options) (Watcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify unavailable: %w", err)
+	}
+
+	w := &inotifyWatcher{
+		opts: opts,
+		fd:   fd,
+		// A non-blocking descriptor lets Close interrupt a pending Read.
+		// File.Fd must not be called as it switches back to blocking mode.
+		file:    os.NewFile(uintptr(fd), "inotify"),
+		events:  make(chan string),
+		done:    make(chan struct{}),
+		watches: make(map[int32]string),
+	}
+
+	for _, root := range opts.Roots {
+		if _, err := w.addTree(root); err != nil {
+			w.file.Close()
+			return nil, err
+		}
+	}
+
+	go w.run()
+	return w, nil
+}
+
+func (w *inotifyWatcher) Events() <-chan string {
+	return w.events
+}
+
+func (w *inotifyWatcher) Backend() string {
+	return "inotify"
+}
+
+func (w *inotifyWatcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.file.Close()
+	})
+	return err
+}
+
+// addTree adds watches for dir and every non-ignored directory below it.
+// Returns the files found, so callers can report files that were created
+// before the watch on a new directory was in place.
+func (w *inotifyWatcher) addTree(dir string) ([]string, error) {
+	var files []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+
+		if path != dir && w.isIgnored(path, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !d.IsDir() {
+			files = append(files, path)
+			return nil
+		}
+
+		wd, err := syscall.InotifyAddWatch(w.fd, path, inotifyMask)
+		if err != nil {
+			// ENOSPC means the watch limit is exhausted; let New fall back to polling
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+
+		w.mu.Lock()
+		w.watches[int32(wd)] = path
+		w.mu.Unlock()
+		return nil
+	})
+
+	return files, err
+}
+
+func (w *inotifyWatcher) isIgnored(path string, isDir bool) bool {
+	for _, root := range w.opts.Roots {
+		if path == root {
+			return false
+		}
+	}
+	return w.opts.Ignore(path, isDir)
+}
+
+func (w *inotifyWatcher) run() {
+	defer close(w.events)
+
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameBytes := buf[offset+syscall.SizeofInotifyEvent : offset+syscall.SizeofInotifyEvent+int(event.Len)]
+			name := string(bytes.TrimRight(nameBytes, "\x00"))
+			offset += syscall.SizeofInotifyEvent + int(event.Len)
+
+			for _, path := range w.handle(event, name) {
+				select {
+				case w.events <- path:
+				case <-w.done:
+					return
+				}
+			}
+		}
+	}
+}
+
+// handle turns a raw event into changed paths, keeping directory watches
+// in step with the tree
+func (w *inotifyWatcher) handle(event *syscall.InotifyEvent, name string) []string {
+	if event.Mask&syscall.IN_Q_OVERFLOW != 0 {
+		// Events were lost; report the roots so the consumer rescans everything
+		return w.opts.Roots
+	}
+
+	w.mu.Lock()
+	dir, ok := w.watches[event.Wd]
+	if event.Mask&syscall.IN_IGNORED != 0 {
+		delete(w.watches, event.Wd)
+	}
+	w.mu.Unlock()
+
+	if !ok || name == "" {
+		return nil
+	}
+
+	path := filepath.Join(dir, name)
+	isDir := event.Mask&syscall.IN_ISDIR != 0
+	if w.isIgnored(path, isDir) {
+		return nil
+	}
+
+	if isDir && event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+		files, _ := w.addTree(path)
+		return append([]string{path}, files...)
+	}
+
+	return []string{path}
+}
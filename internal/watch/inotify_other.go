@@ -0,0 +1,10 @@
+//go:build !linux
+
+package watch
+
+import "errors"
+
+// newNative is unavailable on this platform; New falls back to polling
+func newNative(opts Options) (Watcher, error) {
+	return nil, errors.New("native file watching is not supported on this platform")
+}
@@ -0,0 +1,128 @@
+package watch
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// fileState is what the poller compares between snapshots
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// poller detects changes by periodically walking the roots and comparing
+// modification times and sizes. Works on every file system.
+type poller struct {
+	opts   Options
+	events chan string
+	done   chan struct{}
+	once   sync.Once
+	state  map[string]fileState
+}
+
+func newPoller(opts Options) (*poller, error) {
+	p := &poller{
+		opts:   opts,
+		events: make(chan string),
+		done:   make(chan struct{}),
+	}
+	p.state = p.snapshot()
+
+	go p.run()
+	return p, nil
+}
+
+func (p *poller) Events() <-chan string {
+	return p.events
+}
+
+func (p *poller) Backend() string {
+	return "polling"
+}
+
+func (p *poller) Close() error {
+	p.once.Do(func() { close(p.done) })
+	return nil
+}
+
+func (p *poller) run() {
+	defer close(p.events)
+
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			next := p.snapshot()
+			for _, path := range diffSnapshots(p.state, next) {
+				select {
+				case p.events <- path:
+				case <-p.done:
+					return
+				}
+			}
+			p.state = next
+		}
+	}
+}
+
+// snapshot records the state of every file below the roots
+func (p *poller) snapshot() map[string]fileState {
+	state := make(map[string]fileState)
+
+	for _, root := range p.opts.Roots {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// Unreadable entries are skipped; a vanished root is simply empty
+				if d != nil && d.IsDir() && path != root {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if path != root && p.opts.Ignore(path, d.IsDir()) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			state[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+			return nil
+		})
+	}
+
+	return state
+}
+
+// diffSnapshots returns paths that were added, removed or modified
+func diffSnapshots(prev, next map[string]fileState) []string {
+	var changed []string
+
+	for path, state := range next {
+		if old, ok := prev[path]; !ok || old != state {
+			changed = append(changed, path)
+		}
+	}
+	for path := range prev {
+		if _, ok := next[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+
+	return changed
+}
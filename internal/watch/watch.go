@@ -0,0 +1,105 @@
+package watch
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultInterval is the polling interval used when none is configured
+const DefaultInterval = time.Second
+
+// Watcher reports the paths of files and directories that change below a
+// set of root directories
+type Watcher interface {
+	// Events delivers changed paths. It is closed when the watcher stops.
+	Events() <-chan string
+	// Backend names the mechanism in use, e.g. "inotify" or "polling"
+	Backend() string
+	// Close stops the watcher and releases its resources
+	Close() error
+}
+
+// Options configures a Watcher
+type Options struct {
+	Roots []string // Directories to watch recursively
+
+	// Ignore reports whether a path below a root should be skipped.
+	// Roots themselves are never ignored. May be nil.
+	Ignore func(path string, isDir bool) bool
+
+	Poll     bool          // Force polling even if native notifications are available
+	Interval time.Duration // Polling interval (DefaultInterval if zero)
+}
+
+// New returns a Watcher using native file system notifications where the
+// platform supports them, falling back to polling otherwise
+func New(opts Options) (Watcher, error) {
+	if opts.Ignore == nil {
+		opts.Ignore = func(string, bool) bool { return false }
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+
+	if !opts.Poll {
+		if w, err := newNative(opts); err == nil {
+			return w, nil
+		}
+	}
+
+	return newPoller(opts)
+}
+
+// Batch groups paths arriving on events into batches, emitting a batch once
+// no new path has arrived for delay. Duplicate paths within a batch are
+// dropped and order of first arrival is kept. The returned channel is closed
+// when events is closed or ctx is done.
+func Batch(ctx context.Context, events <-chan string, delay time.Duration) <-chan []string {
+	out := make(chan []string)
+
+	go func() {
+		defer close(out)
+
+		var pending []string
+		seen := make(map[string]bool)
+		timer := time.NewTimer(delay)
+		timer.Stop()
+
+		flush := func() bool {
+			if len(pending) == 0 {
+				return true
+			}
+			select {
+			case out <- pending:
+			case <-ctx.Done():
+				return false
+			}
+			pending = nil
+			seen = make(map[string]bool)
+			return true
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case path, ok := <-events:
+				if !ok {
+					flush()
+					return
+				}
+				if !seen[path] {
+					seen[path] = true
+					pending = append(pending, path)
+				}
+				timer.Reset(delay)
+			case <-timer.C:
+				if !flush() {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
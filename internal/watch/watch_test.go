@@ -0,0 +1,129 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan string)
+	batches := Batch(ctx, events, 50*time.Millisecond)
+
+	go func() {
+		events <- "a"
+		events <- "b"
+		events <- "a"
+	}()
+
+	select {
+	case batch := <-batches:
+		assert.Equal(t, []string{"a", "b"}, batch)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no batch received")
+	}
+
+	close(events)
+	_, ok := <-batches
+	assert.False(t, ok)
+}
+
+// waitFor reads events until one matches path or the timeout expires,
+// returning the paths seen before it
+func waitFor(t *testing.T, w Watcher, path string) []string {
+	t.Helper()
+	var seen []string
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case got, ok := <-w.Events():
+			require.True(t, ok, "watcher closed")
+			if got == path {
+				return seen
+			}
+			seen = append(seen, got)
+		case <-timeout:
+			t.Fatalf("no event for %s", path)
+		}
+	}
+}
+
+func testWatcher(t *testing.T, poll bool) {
+	tmpDir := t.TempDir()
+	ignoredDir := filepath.Join(tmpDir, "ignored")
+	require.NoError(t, os.MkdirAll(ignoredDir, 0755))
+
+	w, err := New(Options{
+		Roots: []string{tmpDir},
+		Ignore: func(path string, isDir bool) bool {
+			return path == ignoredDir || strings.HasSuffix(path, ".tmp")
+		},
+		Poll:     poll,
+		Interval: 20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer w.Close()
+
+	if poll {
+		assert.Equal(t, "polling", w.Backend())
+	}
+
+	// Create, then modify a file
+	file := filepath.Join(tmpDir, "a.go")
+	require.NoError(t, os.WriteFile(file, []byte("one"), 0644))
+	waitFor(t, w, file)
+
+	require.NoError(t, os.WriteFile(file, []byte("two!"), 0644))
+	waitFor(t, w, file)
+
+	// Files in new directories are reported
+	nested := filepath.Join(tmpDir, "pkg", "b.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(nested), 0755))
+	require.NoError(t, os.WriteFile(nested, []byte("x"), 0644))
+	waitFor(t, w, nested)
+
+	// Ignored paths are not reported
+	require.NoError(t, os.WriteFile(filepath.Join(ignoredDir, "c.go"), []byte("x"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "d.tmp"), []byte("x"), 0644))
+	require.NoError(t, os.Remove(file))
+	for _, path := range waitFor(t, w, file) {
+		assert.NotContains(t, path, "ignored")
+		assert.NotContains(t, path, ".tmp")
+	}
+
+	require.NoError(t, w.Close())
+}
+
+func TestPoller(t *testing.T) {
+	testWatcher(t, true)
+}
+
+func TestNew_Native(t *testing.T) {
+	testWatcher(t, false)
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	now := time.Now()
+	prev := map[string]fileState{
+		"same":     {modTime: now, size: 1},
+		"modified": {modTime: now, size: 1},
+		"removed":  {modTime: now, size: 1},
+	}
+	next := map[string]fileState{
+		"same":     {modTime: now, size: 1},
+		"modified": {modTime: now, size: 2},
+		"added":    {modTime: now, size: 1},
+	}
+
+	changed := diffSnapshots(prev, next)
+	assert.ElementsMatch(t, []string{"modified", "removed", "added"}, changed)
+}
@@ -77,15 +77,40 @@ if [ "$CHANGES_DETECTED" = "true" ]; then
     done <<< "$MODIFIED"
     COMMENT+="\n"
   fi
+
+  # List moved files (category changes)
+  MOVED=$(echo "$SYNC_PREVIEW" | jq -r '.files.moved[]? | "- `\(.from)` → `\(.to)`"' 2>/dev/null || true)
+  if [ -n "$MOVED" ]; then
+    COMMENT+="**Moved:**\n${MOVED}\n\n"
+  fi
+
+  # List deleted (archived or pruned) files
+  DELETED=$(echo "$SYNC_PREVIEW" | jq -r '.files.deleted[]?' 2>/dev/null || true)
+  if [ -n "$DELETED" ]; then
+    COMMENT+="**Deleted:**\n"
+    while IFS= read -r file; do
+      ADR_NAME=$(echo "$SYNC_PREVIEW" | jq -r ".adrs[] | select(.file_path == \"$file\") | .name")
+      COMMENT+="- \`${file}\` - ${ADR_NAME}\n"
+    done <<< "$DELETED"
+    COMMENT+="\n"
+  fi
+fi
+
+BODY="$(echo -e "$COMMENT")"
+
+# Add per-ADR diffs (appended after echo -e so diff content is not unescaped)
+DIFFS=$(echo "$SYNC_PREVIEW" | jq -r '.adrs[]? | select((.diff // "") != "") | "<details>\n<summary><code>\(.file_path)</code> - \(.name)</summary>\n\n```diff\n\(.diff)```\n\n</details>\n"' 2>/dev/null || true)
+if [ -n "$DIFFS" ]; then
+  BODY+=$'\n'"${DIFFS}"$'\n\n'
 fi
 
 # Add footer
-COMMENT+="---\n"
-COMMENT+="🤖 [ADR Buddy](https://github.com/weaby/adr-buddy)"
+BODY+=$'\n---\n'
+BODY+="🤖 [ADR Buddy](https://github.com/weaby/adr-buddy)"
 
 # Post comment
-echo -e "$COMMENT"
-post_pr_comment "$(echo -e "$COMMENT")"
+echo "$BODY"
+post_pr_comment "$BODY"
 
 # Exit with appropriate code
 if [ "$STATUS" = "fail" ]; then